@@ -3,11 +3,12 @@ package main
 
 import (
 	"fmt"
+	"galaxy/internal/router"
 	"galaxy/pkg/config"
 	"galaxy/pkg/database"
 	"galaxy/pkg/logger"
 	"galaxy/pkg/redis"
-	"galaxy/pkg/router"
+	pkgRouter "galaxy/pkg/router"
 	"github.com/gin-gonic/gin"
 	"os"
 )
@@ -29,14 +30,19 @@ func main() {
 	// 初始化Redis
 	redis.Init()
 
-	// 创建路由
-	r := router.NewRouter()
-	r.SetupAdminRoutes()
+	// 创建路由引擎
+	engine := pkgRouter.NewEngine()
+
+	// 设置管理后台路由
+	router.SetupAdminRoutes(engine)
+
+	// 打印路由信息
+	pkgRouter.PrintRoutes(engine)
 
 	// 启动服务
 	port := fmt.Sprintf(":%d", cfg.Server.Admin.Port)
 	logger.Service("Admin").Str("port", fmt.Sprintf("%d", cfg.Server.Admin.Port))
-	if err := r.GetEngine().Run(port); err != nil {
+	if err := engine.Run(port); err != nil {
 		logger.Fatal().Err(err).Msg("Admin Error")
 	}
 }
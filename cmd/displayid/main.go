@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/service/admin/problem"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"log"
+	"os"
+)
+
+const usage = `题目展示编号维护
+
+用法:
+  displayid renumber [-prefix P] [-start 1000] [-category ID] [-source 来源] [-dry-run]
+      按创建顺序为匹配的题目重新分配连续编号，未指定分类与来源时处理全部题目
+  displayid reserve -start 2000 -end 2999 [-prefix P] [-remark 说明]
+      保留编号区间，自动分配时跳过，仍可手动指定
+  displayid release -id <区间ID>
+      取消保留区间
+  displayid ranges [-prefix P]
+      查看保留区间
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+
+	// 加载配置
+	cfg := config.Load("configs/config.yaml")
+
+	// 初始化数据库
+	database.Init()
+
+	service := problem.NewDisplayIDService()
+	command, args := os.Args[1], os.Args[2:]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	prefix := flags.String("prefix", cfg.Problem.DisplayID.Prefix, "编号前缀")
+
+	switch command {
+	case "renumber":
+		defaultStart := cfg.Problem.DisplayID.Start
+		if defaultStart <= 0 {
+			defaultStart = 1000
+		}
+		start := flags.Int("start", defaultStart, "起始编号")
+		categoryID := flags.String("category", "", "只处理该分类的题目")
+		source := flags.String("source", "", "只处理该来源的题目")
+		dryRun := flags.Bool("dry-run", false, "只输出结果，不写入")
+		flags.Parse(args)
+
+		items, err := service.Renumber(&dto.RenumberRequest{
+			Prefix:     *prefix,
+			Start:      *start,
+			CategoryID: *categoryID,
+			Source:     *source,
+			DryRun:     *dryRun,
+		})
+		if err != nil {
+			log.Fatalf("重新编号失败: %v", err)
+		}
+		for _, item := range items {
+			fmt.Printf("%s\t%s -> %s\t%s\n", item.ProblemID, value(item.OldDisplayID), item.NewDisplayID, value(item.Title))
+		}
+		if *dryRun {
+			log.Printf("试运行完成，共 %d 道题目，未写入", len(items))
+		} else {
+			log.Printf("重新编号完成，共 %d 道题目", len(items))
+		}
+
+	case "reserve":
+		start := flags.Int("start", -1, "起始编号")
+		end := flags.Int("end", -1, "结束编号（包含）")
+		remark := flags.String("remark", "", "说明")
+		flags.Parse(args)
+
+		reserved, err := service.Reserve(&dto.ReserveRequest{
+			Prefix: *prefix,
+			Start:  *start,
+			End:    *end,
+			Remark: *remark,
+		})
+		if err != nil {
+			log.Fatalf("保留编号区间失败: %v", err)
+		}
+		log.Printf("已保留 %s%d - %s%d，区间ID: %s", reserved.Prefix, reserved.StartValue, reserved.Prefix, reserved.EndValue, reserved.ID)
+
+	case "release":
+		id := flags.String("id", "", "区间ID")
+		flags.Parse(args)
+
+		if err := service.Release(*id); err != nil {
+			log.Fatalf("取消保留区间失败: %v", err)
+		}
+		log.Println("已取消保留区间")
+
+	case "ranges":
+		flags.Parse(args)
+
+		// 未显式指定前缀时列出全部
+		filter := ""
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name == "prefix" {
+				filter = *prefix
+			}
+		})
+		ranges, err := service.ReservedRanges(filter)
+		if err != nil {
+			log.Fatalf("获取保留区间失败: %v", err)
+		}
+		for _, reserved := range ranges {
+			fmt.Printf("%s\t%s%d - %s%d\t%s\n", reserved.ID, reserved.Prefix, reserved.StartValue, reserved.Prefix, reserved.EndValue, value(reserved.Remark))
+		}
+
+	default:
+		fmt.Print(usage)
+		os.Exit(2)
+	}
+}
+
+func value(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
@@ -1 +1,70 @@
 package main
+
+import (
+	"context"
+	"galaxy/internal/judge"
+	"galaxy/internal/judge/language"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/redis"
+	"galaxy/pkg/sandbox"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+func main() {
+	// 沙箱子进程在此处接管并执行提交程序
+	sandbox.Init()
+
+	// 加载配置
+	cfg := config.Load("configs/config.yaml")
+
+	// 初始化数据库
+	database.Init()
+
+	// 初始化Redis
+	redis.Init()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 加载判题语言并探测版本
+	language.Get().Probe(ctx)
+
+	workers := cfg.Judge.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	// 启动判题协程
+	logger.Service("Judge").Int("workers", workers).Msg("🚀 Judge service starts")
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			judge.NewWorker(id).Run(ctx)
+		}(i)
+	}
+
+	// 定期估计题目经验难度
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		judge.RunDifficultyEstimator(ctx)
+	}()
+
+	// 定期推进竞赛状态
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		judge.RunContestScheduler(ctx)
+	}()
+	wg.Wait()
+
+	logger.Info().Msg("Judge service stopped")
+}
@@ -0,0 +1,153 @@
+package contest
+
+import (
+	"galaxy/internal/dto/problem"
+	"galaxy/internal/models"
+	"time"
+)
+
+// ====================== 管理端 ======================
+
+// ContestRequest 创建、更新竞赛请求
+type ContestRequest struct {
+	Title             string     `json:"title" binding:"required,max=255"`
+	Description       *string    `json:"description"`
+	ContestType       *string    `json:"contest_type"`
+	RuleType          string     `json:"rule_type"` // icpc/oi/ioi，默认 icpc
+	Category          *string    `json:"category"`
+	Cover             *string    `json:"cover"`
+	MaxTeamMembers    int        `json:"max_team_members" binding:"min=0"`
+	IsTeamContest     bool       `json:"is_team_contest"`
+	IsVisible         bool       `json:"is_visible"`
+	IsPublic          bool       `json:"is_public"`                           // 非公开竞赛须凭密码或受邀访问
+	Password          *string    `json:"password" binding:"omitempty,max=64"` // 不传表示不修改，空字符串表示清除密码
+	RegisterStartTime *time.Time `json:"register_start_time"`                 // 为空表示创建后即可报名
+	RegisterEndTime   *time.Time `json:"register_end_time"`                   // 为空表示比赛结束前均可报名
+	ContestStartTime  *time.Time `json:"contest_start_time" binding:"required"`
+	ContestEndTime    *time.Time `json:"contest_end_time" binding:"required"`
+	FrozenTime        int        `json:"frozen_time" binding:"min=0"`  // 结束前封榜分钟数，0 表示不封榜，仅 ICPC 赛制使用
+	PenaltyTime       int        `json:"penalty_time" binding:"min=0"` // 每次错误提交的罚时（分钟）
+	AllowedLanguages  []string   `json:"allowed_languages"`            // 为空表示不限制
+	Sort              int        `json:"sort"`
+}
+
+// ContestProblemRequest 竞赛题目，ProblemCode 为空时按顺序分配 A、B、C…
+type ContestProblemRequest struct {
+	ProblemID   string `json:"problem_id" binding:"required"`
+	ProblemCode string `json:"problem_code" binding:"max=10"`
+	Score       int    `json:"score" binding:"min=0"`
+}
+
+// ContestProblemsRequest 整体替换竞赛题目，按数组顺序排列
+type ContestProblemsRequest struct {
+	Problems []ContestProblemRequest `json:"problems" binding:"dive"`
+}
+
+// ContestAdminDetail 管理端竞赛详情，不包含密码哈希
+type ContestAdminDetail struct {
+	ContestItem
+	Description      *string              `json:"description"`
+	IsVisible        bool                 `json:"is_visible"`
+	AllowedLanguages []string             `json:"allowed_languages"`
+	UnfreezeTime     *time.Time           `json:"unfreeze_time"` // 赛后解除封榜的时间，未解除时为空
+	Sort             int                  `json:"sort"`
+	Problems         []ContestProblemItem `json:"problems"`
+	CreateTime       time.Time            `json:"create_time"`
+	UpdateTime       time.Time            `json:"update_time"`
+}
+
+// InviteRequest 邀请用户，Users 为用户 ID 或用户名
+type InviteRequest struct {
+	Users []string `json:"users" binding:"required,min=1"`
+}
+
+// InviteItem 已授权访问竞赛的用户
+type InviteItem struct {
+	UserID     string    `json:"user_id"`
+	Username   string    `json:"username"`
+	Nickname   string    `json:"nickname"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// ====================== 公共 ======================
+
+// ContestItem 竞赛列表项
+type ContestItem struct {
+	ID                string     `json:"id"`
+	Title             string     `json:"title"`
+	ContestType       *string    `json:"contest_type"`
+	RuleType          *string    `json:"rule_type"`
+	Category          *string    `json:"category"`
+	Cover             *string    `json:"cover"`
+	IsTeamContest     bool       `json:"is_team_contest"`
+	MaxTeamMembers    int        `json:"max_team_members"`
+	IsPublic          bool       `json:"is_public"`
+	HasPassword       bool       `json:"has_password"` // 非公开竞赛是否可凭密码访问
+	RegisterStartTime *time.Time `json:"register_start_time"`
+	RegisterEndTime   *time.Time `json:"register_end_time"`
+	ContestStartTime  *time.Time `json:"contest_start_time"`
+	ContestEndTime    *time.Time `json:"contest_end_time"`
+	FrozenTime        int        `json:"frozen_time"`
+	PenaltyTime       int        `json:"penalty_time"`
+	Status            string     `json:"status"`
+	ParticipantCount  int64      `json:"participant_count"`
+}
+
+// NewContestItem 由竞赛构建列表项，status 为按当前时间计算的状态
+func NewContestItem(contest *models.ContestInfo, status string) ContestItem {
+	return ContestItem{
+		ID:                contest.ID,
+		Title:             contest.Title,
+		ContestType:       contest.ContestType,
+		RuleType:          contest.RuleType,
+		Category:          contest.Category,
+		Cover:             contest.Cover,
+		IsTeamContest:     contest.IsTeamContest,
+		MaxTeamMembers:    contest.MaxTeamMembers,
+		IsPublic:          contest.IsPublic,
+		HasPassword:       !contest.IsPublic && contest.Password != nil && *contest.Password != "",
+		RegisterStartTime: contest.RegisterStartTime,
+		RegisterEndTime:   contest.RegisterEndTime,
+		ContestStartTime:  contest.ContestStartTime,
+		ContestEndTime:    contest.ContestEndTime,
+		FrozenTime:        contest.FrozenTime,
+		PenaltyTime:       contest.PenaltyTime,
+		Status:            status,
+	}
+}
+
+// ContestDetail 竞赛详情，题目在比赛开始后且当前用户可访问时才返回
+type ContestDetail struct {
+	ContestItem
+	Description      *string              `json:"description"`
+	AllowedLanguages []string             `json:"allowed_languages"`
+	Problems         []ContestProblemItem `json:"problems"`
+	Registered       bool                 `json:"registered"` // 当前用户是否已报名
+	Authorized       bool                 `json:"authorized"` // 当前用户是否可访问竞赛
+}
+
+// ContestProblemItem 竞赛题目
+type ContestProblemItem struct {
+	ProblemCode string  `json:"problem_code"`
+	ProblemID   string  `json:"problem_id"`
+	DisplayID   *string `json:"display_id"`
+	Title       *string `json:"title"`
+	Score       int     `json:"score"`
+}
+
+// ContestProblemDetail 竞赛题目详情，题目信息与题库中的详情相同
+type ContestProblemDetail struct {
+	problem.ProblemDetail
+	ProblemCode string `json:"problem_code"`
+	Score       int    `json:"score"`
+}
+
+// UnlockRequest 输入竞赛密码
+type UnlockRequest struct {
+	Password string `json:"password" binding:"required,max=64"`
+}
+
+// RegisterRequest 报名请求，团队赛须填写队伍名称
+type RegisterRequest struct {
+	TeamName string `json:"team_name" binding:"max=255"`
+}
@@ -0,0 +1,88 @@
+package contest
+
+// FeedEvent ICPC CDS 事件流（2020-03 格式）中的一条事件，导出时每行一条
+type FeedEvent struct {
+	Type string      `json:"type"`
+	ID   string      `json:"id"`
+	Op   string      `json:"op"`
+	Data interface{} `json:"data"`
+}
+
+// 事件流中的时间为 ISO 8601 绝对时间，相对时间为 h:mm:ss.sss 格式的比赛时间
+
+// FeedContest 竞赛
+type FeedContest struct {
+	ID                       string  `json:"id"`
+	Name                     string  `json:"name"`
+	FormalName               string  `json:"formal_name"`
+	StartTime                string  `json:"start_time"`
+	Duration                 string  `json:"duration"`
+	ScoreboardFreezeDuration *string `json:"scoreboard_freeze_duration"`
+	PenaltyTime              int     `json:"penalty_time"`
+}
+
+// FeedJudgementType 判题结果类型
+type FeedJudgementType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Penalty bool   `json:"penalty"`
+	Solved  bool   `json:"solved"`
+}
+
+// FeedLanguage 编程语言
+type FeedLanguage struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// FeedProblem 题目，Label 为题目编号
+type FeedProblem struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Name    string `json:"name"`
+	Ordinal int    `json:"ordinal"`
+}
+
+// FeedGroup 队伍分组
+type FeedGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// FeedTeam 队伍，个人赛中每个用户为一支队伍
+type FeedTeam struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	GroupIDs []string `json:"group_ids"`
+}
+
+// FeedState 竞赛状态
+type FeedState struct {
+	Started      *string `json:"started"`
+	Frozen       *string `json:"frozen"`
+	Ended        *string `json:"ended"`
+	Thawed       *string `json:"thawed"`
+	Finalized    *string `json:"finalized"`
+	EndOfUpdates *string `json:"end_of_updates"`
+}
+
+// FeedSubmission 提交
+type FeedSubmission struct {
+	ID          string `json:"id"`
+	LanguageID  string `json:"language_id"`
+	ProblemID   string `json:"problem_id"`
+	TeamID      string `json:"team_id"`
+	Time        string `json:"time"`
+	ContestTime string `json:"contest_time"`
+}
+
+// FeedJudgement 判题结果
+type FeedJudgement struct {
+	ID               string `json:"id"`
+	SubmissionID     string `json:"submission_id"`
+	JudgementTypeID  string `json:"judgement_type_id"`
+	StartTime        string `json:"start_time"`
+	StartContestTime string `json:"start_contest_time"`
+	EndTime          string `json:"end_time"`
+	EndContestTime   string `json:"end_contest_time"`
+}
@@ -0,0 +1,58 @@
+package contest
+
+import "time"
+
+// 榜单单元格结果
+const (
+	CellAccepted = "accepted" // 已通过
+	CellRejected = "rejected" // 尝试未通过
+	CellPending  = "pending"  // 有判题中或封榜后的提交，结果未公布
+)
+
+// Scoreboard 竞赛榜单
+type Scoreboard struct {
+	ContestID   string              `json:"contest_id"`
+	RuleType    string              `json:"rule_type"`
+	PenaltyTime int                 `json:"penalty_time"` // 每次错误提交的罚时（分钟）
+	Frozen      bool                `json:"frozen"`       // 是否为封榜视图，封榜后的提交显示为待定
+	FreezeTime  *time.Time          `json:"freeze_time"`
+	UpdateTime  time.Time           `json:"update_time"`
+	Problems    []ScoreboardProblem `json:"problems"`
+	Rows        []ScoreboardRow     `json:"rows"`
+}
+
+// ScoreboardProblem 榜单题目列及其统计
+type ScoreboardProblem struct {
+	ProblemCode string `json:"problem_code"`
+	ProblemID   string `json:"problem_id"`
+	Score       int    `json:"score"`
+	SolvedCount int    `json:"solved_count"` // 通过人数
+	TriedCount  int    `json:"tried_count"`  // 提交人数
+	FirstSolver string `json:"first_solver"` // 一血用户
+}
+
+// ScoreboardRow 榜单一行，Cells 与 Problems 一一对应
+type ScoreboardRow struct {
+	Rank     int              `json:"rank"`
+	UserID   string           `json:"user_id"`
+	Username string           `json:"username"`
+	Nickname string           `json:"nickname"`
+	TeamName *string          `json:"team_name"`
+	Solved   int              `json:"solved"`
+	Penalty  int              `json:"penalty"` // 总罚时（分钟）
+	Score    float64          `json:"score"`   // 总分，OI/IOI 赛制使用
+	Cells    []ScoreboardCell `json:"cells"`
+}
+
+// ScoreboardCell 用户在一道题上的结果
+type ScoreboardCell struct {
+	ProblemCode string  `json:"problem_code"`
+	Result      string  `json:"result"`     // accepted/rejected/pending，未提交为空；OI/IOI 赛制满分为 accepted
+	Display     string  `json:"display"`    // 展示文本，如 +、+2、-3、?，OI/IOI 赛制为得分
+	Attempts    int     `json:"attempts"`   // 已公布结果的计罚时提交次数，通过时包含通过的一次
+	Pending     int     `json:"pending"`    // 结果未公布的提交次数
+	SolveTime   int     `json:"solve_time"` // 通过时间（比赛开始后的分钟数），OI/IOI 赛制为取得该得分的时间
+	Penalty     int     `json:"penalty"`    // 本题罚时（分钟）
+	Score       float64 `json:"score"`      // 本题得分，OI/IOI 赛制使用
+	FirstBlood  bool    `json:"first_blood"`
+}
@@ -0,0 +1,28 @@
+package judge
+
+import "time"
+
+// 重判范围
+const (
+	RejudgeScopeSubmission = "submission" // 单个提交
+	RejudgeScopeProblem    = "problem"    // 题目的全部提交
+	RejudgeScopeContest    = "contest"    // 竞赛的全部提交
+)
+
+// RejudgeTask 重判任务，保存在 Redis 中
+type RejudgeTask struct {
+	TaskID     string    `json:"task_id"`
+	Scope      string    `json:"scope"`
+	TargetID   string    `json:"target_id"`
+	Total      int64     `json:"total"`
+	Operator   string    `json:"operator"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// RejudgeProgress 重判进度
+type RejudgeProgress struct {
+	RejudgeTask
+	Finished int64            `json:"finished"`
+	Statuses map[string]int64 `json:"statuses"` // 各判题状态的提交数
+	IsDone   bool             `json:"is_done"`
+}
@@ -0,0 +1,26 @@
+package problem
+
+// RenumberRequest 重新编号请求，按创建顺序为匹配的题目分配 Prefix+Start 起的连续编号
+type RenumberRequest struct {
+	Prefix     string // 新编号前缀
+	Start      int    // 起始编号
+	CategoryID string // 只处理该分类的题目，为空表示不限
+	Source     string // 只处理该来源的题目，为空表示不限
+	DryRun     bool   // 只计算结果，不写入
+}
+
+// RenumberItem 重新编号结果
+type RenumberItem struct {
+	ProblemID    string  `json:"problem_id"`
+	Title        *string `json:"title"`
+	OldDisplayID *string `json:"old_display_id"`
+	NewDisplayID string  `json:"new_display_id"`
+}
+
+// ReserveRequest 保留编号区间请求
+type ReserveRequest struct {
+	Prefix string
+	Start  int
+	End    int // 包含
+	Remark string
+}
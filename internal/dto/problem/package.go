@@ -0,0 +1,74 @@
+package problem
+
+import "galaxy/internal/judge/template"
+
+// 题目包格式
+const (
+	PackageFormatFPS     = "fps"     // Free Problem Set XML
+	PackageFormatPolygon = "polygon" // Codeforces Polygon 完整包
+	PackageFormatGalaxy  = "galaxy"  // 本系统导出的题目包
+)
+
+// CodeTemplate 单个语言的代码模板
+type CodeTemplate = template.Template
+
+// ImportResult 导入得到的单个题目
+type ImportResult struct {
+	ProblemID string   `json:"problem_id"`
+	DisplayID *string  `json:"display_id"`
+	Title     string   `json:"title"`
+	CaseCount int      `json:"case_count"`
+	Warnings  []string `json:"warnings"`
+}
+
+// PackageManifest Galaxy 题目包中的 problem.yaml，未结构化的题目描述位于 statement.md，
+// 用例位于 tests/<name>.in 与 tests/<name>.out
+type PackageManifest struct {
+	Title       string                  `yaml:"title"`
+	Statement   *Statement              `yaml:"statement,omitempty"`
+	Source      string                  `yaml:"source,omitempty"`
+	URL         string                  `yaml:"url,omitempty"`
+	TimeLimit   int                     `yaml:"time_limit"`   // 毫秒
+	MemoryLimit int                     `yaml:"memory_limit"` // MB
+	Difficulty  int                     `yaml:"difficulty,omitempty"`
+	Threshold   float64                 `yaml:"threshold,omitempty"`
+	Languages   []string                `yaml:"languages,omitempty"`
+	Tags        []string                `yaml:"tags,omitempty"`
+	Checker     ManifestChecker         `yaml:"checker"`
+	Interactor  *ManifestProgram        `yaml:"interactor,omitempty"`
+	UseTemplate bool                    `yaml:"use_template,omitempty"`
+	Templates   map[string]CodeTemplate `yaml:"templates,omitempty"`
+	Subtasks    []ManifestSubtask       `yaml:"subtasks,omitempty"`
+	Cases       []ManifestCase          `yaml:"cases"`
+}
+
+// ManifestChecker 输出比较方式，special 时 Source 为包内判题程序路径
+type ManifestChecker struct {
+	Type     string  `yaml:"type"`
+	AbsEps   float64 `yaml:"abs_eps,omitempty"`
+	RelEps   float64 `yaml:"rel_eps,omitempty"`
+	Language string  `yaml:"language,omitempty"`
+	Source   string  `yaml:"source,omitempty"`
+}
+
+// ManifestProgram 包内附带的程序源码
+type ManifestProgram struct {
+	Language string `yaml:"language"`
+	Source   string `yaml:"source"`
+}
+
+// ManifestSubtask 子任务，依赖按子任务在列表中的下标引用
+type ManifestSubtask struct {
+	Title        string  `yaml:"title,omitempty"`
+	Score        float64 `yaml:"score"`
+	Aggregation  string  `yaml:"aggregation,omitempty"`
+	Dependencies []int   `yaml:"dependencies,omitempty"`
+}
+
+// ManifestCase 用例，Subtask 为所属子任务的下标
+type ManifestCase struct {
+	Name    string  `yaml:"name"`
+	Score   float64 `yaml:"score,omitempty"`
+	Sample  bool    `yaml:"sample,omitempty"`
+	Subtask *int    `yaml:"subtask,omitempty"`
+}
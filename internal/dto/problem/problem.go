@@ -0,0 +1,223 @@
+package problem
+
+import (
+	"galaxy/internal/models"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ====================== 管理端 ======================
+
+// ProblemRequest 创建、更新题目请求
+type ProblemRequest struct {
+	DisplayID    *string        `json:"display_id"`
+	CategoryID   string         `json:"category_id"`
+	Title        string         `json:"title" binding:"required,max=255"`
+	Source       *string        `json:"source"`
+	URL          *string        `json:"url"`
+	TimeLimit    int            `json:"time_limit" binding:"min=0"`   // 毫秒
+	MemoryLimit  int            `json:"memory_limit" binding:"min=0"` // MB
+	Description  *string        `json:"description"`
+	Statement    *Statement     `json:"statement"`
+	Languages    []string       `json:"languages"` // 为空表示不限制
+	Difficulty   int            `json:"difficulty"`
+	Threshold    float64        `json:"threshold"`
+	UseTemplate  bool           `json:"use_template"`
+	CodeTemplate datatypes.JSON `json:"code_template"`
+	IsPublic     bool           `json:"is_public"`
+	IsVisible    bool           `json:"is_visible"`
+	UseAI        bool           `json:"use_ai"`
+	TagIDs       []string       `json:"tag_ids"`
+	// 输出比较方式
+	CheckerType     string  `json:"checker_type"`
+	CheckerAbsEps   float64 `json:"checker_abs_eps"`
+	CheckerRelEps   float64 `json:"checker_rel_eps"`
+	CheckerCode     *string `json:"checker_code"`
+	CheckerLanguage *string `json:"checker_language"`
+	// 交互题
+	IsInteractive      bool    `json:"is_interactive"`
+	InteractorCode     *string `json:"interactor_code"`
+	InteractorLanguage *string `json:"interactor_language"`
+}
+
+// ProblemAdminDetail 管理端题目详情，包含判题配置、全部用例与子任务
+type ProblemAdminDetail struct {
+	ID           string         `json:"id"`
+	DisplayID    *string        `json:"display_id"`
+	CategoryID   string         `json:"category_id"`
+	Title        *string        `json:"title"`
+	Source       *string        `json:"source"`
+	URL          *string        `json:"url"`
+	TimeLimit    int            `json:"time_limit"`   // 毫秒
+	MemoryLimit  int            `json:"memory_limit"` // MB
+	Description  *string        `json:"description"`
+	Statement    datatypes.JSON `json:"statement"`
+	Languages    datatypes.JSON `json:"languages"`
+	Difficulty   int            `json:"difficulty"`
+	Threshold    float64        `json:"threshold"`
+	UseTemplate  bool           `json:"use_template"`
+	CodeTemplate datatypes.JSON `json:"code_template"`
+	IsPublic     bool           `json:"is_public"`
+	IsVisible    bool           `json:"is_visible"`
+	UseAI        bool           `json:"use_ai"`
+	// 输出比较方式
+	CheckerType     string  `json:"checker_type"`
+	CheckerAbsEps   float64 `json:"checker_abs_eps"`
+	CheckerRelEps   float64 `json:"checker_rel_eps"`
+	CheckerCode     *string `json:"checker_code"`
+	CheckerLanguage *string `json:"checker_language"`
+	// 交互题
+	IsInteractive      bool    `json:"is_interactive"`
+	InteractorCode     *string `json:"interactor_code"`
+	InteractorLanguage *string `json:"interactor_language"`
+
+	CreateTime time.Time         `json:"create_time"`
+	UpdateTime time.Time         `json:"update_time"`
+	TagIDs     []string          `json:"tag_ids"`
+	TestCases  []TestCaseItem    `json:"test_cases"`
+	Subtasks   []SubtaskItem     `json:"subtasks"`
+	Stats      *ProblemStatsItem `json:"stats"` // 提交统计与建议难度，尚无提交时为空
+}
+
+// NewProblemAdminDetail 由题目构建管理端详情，标签、用例、子任务与统计由调用方填充
+func NewProblemAdminDetail(problem *models.ProblemInfo) *ProblemAdminDetail {
+	return &ProblemAdminDetail{
+		ID:                 problem.ID,
+		DisplayID:          problem.DisplayID,
+		CategoryID:         problem.CategoryID,
+		Title:              problem.Title,
+		Source:             problem.Source,
+		URL:                problem.URL,
+		TimeLimit:          problem.TimeLimit,
+		MemoryLimit:        problem.MemoryLimit,
+		Description:        problem.Description,
+		Statement:          problem.Statement,
+		Languages:          problem.Languages,
+		Difficulty:         problem.Difficulty,
+		Threshold:          problem.Threshold,
+		UseTemplate:        problem.UseTemplate,
+		CodeTemplate:       problem.CodeTemplate,
+		IsPublic:           problem.IsPublic,
+		IsVisible:          problem.IsVisible,
+		UseAI:              problem.UseAI,
+		CheckerType:        problem.CheckerType,
+		CheckerAbsEps:      problem.CheckerAbsEps,
+		CheckerRelEps:      problem.CheckerRelEps,
+		CheckerCode:        problem.CheckerCode,
+		CheckerLanguage:    problem.CheckerLanguage,
+		IsInteractive:      problem.IsInteractive,
+		InteractorCode:     problem.InteractorCode,
+		InteractorLanguage: problem.InteractorLanguage,
+		CreateTime:         problem.CreatedAt,
+		UpdateTime:         problem.UpdatedAt,
+	}
+}
+
+// ProblemStatsItem 题目提交统计与建议难度
+type ProblemStatsItem struct {
+	SubmitCount         int64          `json:"submit_count"`
+	AcceptedCount       int64          `json:"accepted_count"`
+	SolverCount         int64          `json:"solver_count"`
+	Verdicts            datatypes.JSON `json:"verdicts"`             // 各判题状态的次数
+	Rating              float64        `json:"rating"`               // 通过概率为 50% 的用户评分
+	RatingSamples       int            `json:"rating_samples"`       // 参与估计的有评分的尝试人数
+	SuggestedDifficulty int            `json:"suggested_difficulty"` // 建议难度，0 表示样本不足
+	EstimateTime        *time.Time     `json:"estimate_time"`
+}
+
+// NewProblemStatsItem 由题目统计构建返回信息，stats 为空时返回 nil
+func NewProblemStatsItem(stats *models.ProblemStats) *ProblemStatsItem {
+	if stats == nil {
+		return nil
+	}
+	return &ProblemStatsItem{
+		SubmitCount:         stats.SubmitCount,
+		AcceptedCount:       stats.AcceptedCount,
+		SolverCount:         stats.SolverCount,
+		Verdicts:            stats.Verdicts,
+		Rating:              stats.Rating,
+		RatingSamples:       stats.RatingSamples,
+		SuggestedDifficulty: stats.SuggestedDifficulty,
+		EstimateTime:        stats.EstimateTime,
+	}
+}
+
+// ====================== 公共 ======================
+
+// ProblemItem 题目列表项
+type ProblemItem struct {
+	ID          string    `json:"id"`
+	DisplayID   *string   `json:"display_id"`
+	CategoryID  string    `json:"category_id"`
+	Title       *string   `json:"title"`
+	Source      *string   `json:"source"`
+	Difficulty  int       `json:"difficulty"`
+	TimeLimit   int       `json:"time_limit"`
+	MemoryLimit int       `json:"memory_limit"`
+	IsPublic    bool      `json:"is_public"`
+	IsVisible   bool      `json:"is_visible"`
+	TagIDs      []string  `json:"tag_ids"`
+	CreateTime  time.Time `json:"create_time"`
+	// 提交统计
+	SubmitCount   int64   `json:"submit_count"`
+	AcceptedCount int64   `json:"accepted_count"`
+	AcceptRate    float64 `json:"accept_rate"` // 通过次数 / 提交次数
+	SolverCount   int64   `json:"solver_count"`
+}
+
+// NewProblemItem 由题目与其标签构建列表项
+func NewProblemItem(problem *models.ProblemInfo, tagIDs []string) ProblemItem {
+	if tagIDs == nil {
+		tagIDs = []string{}
+	}
+	return ProblemItem{
+		ID:          problem.ID,
+		DisplayID:   problem.DisplayID,
+		CategoryID:  problem.CategoryID,
+		Title:       problem.Title,
+		Source:      problem.Source,
+		Difficulty:  problem.Difficulty,
+		TimeLimit:   problem.TimeLimit,
+		MemoryLimit: problem.MemoryLimit,
+		IsPublic:    problem.IsPublic,
+		IsVisible:   problem.IsVisible,
+		TagIDs:      tagIDs,
+		CreateTime:  problem.CreatedAt,
+	}
+}
+
+// ApplyStats 写入提交统计，stats 为空表示尚无提交
+func (item *ProblemItem) ApplyStats(stats *models.ProblemStats) {
+	if stats == nil {
+		return
+	}
+	item.SubmitCount = stats.SubmitCount
+	item.AcceptedCount = stats.AcceptedCount
+	item.SolverCount = stats.SolverCount
+	if stats.SubmitCount > 0 {
+		item.AcceptRate = float64(stats.AcceptedCount) / float64(stats.SubmitCount)
+	}
+}
+
+// ProblemDetail 题目公开详情，只包含样例
+type ProblemDetail struct {
+	ProblemItem
+	Description   *string            `json:"description"`
+	Statement     *RenderedStatement `json:"statement"`
+	URL           *string            `json:"url"`
+	Languages     datatypes.JSON     `json:"languages"`
+	UseTemplate   bool               `json:"use_template"`
+	CodeTemplate  map[string]string  `json:"code_template"` // 各语言的可编辑部分，仅 UseTemplate 时返回
+	IsInteractive bool               `json:"is_interactive"`
+	Samples       []SampleCase       `json:"samples"`
+	Verdicts      map[string]int64   `json:"verdicts"` // 各判题状态的次数
+}
+
+// SampleCase 样例
+type SampleCase struct {
+	CaseSign    *string `json:"case_sign"`
+	Input       string  `json:"input"`
+	Output      string  `json:"output"`
+	Explanation string  `json:"explanation"` // 样例说明 HTML
+}
@@ -0,0 +1,35 @@
+package problem
+
+// Statement 结构化题面，存于 ProblemInfo.Statement。各部分均为 Markdown，
+// 公式写在 $...$ 或 $$...$$ 中
+type Statement struct {
+	Background  string            `json:"background" yaml:"background,omitempty"`
+	Description string            `json:"description" yaml:"description,omitempty"`
+	Input       string            `json:"input" yaml:"input,omitempty"`
+	Output      string            `json:"output" yaml:"output,omitempty"`
+	Samples     []StatementSample `json:"samples" yaml:"samples,omitempty"`
+	Hint        string            `json:"hint" yaml:"hint,omitempty"`
+}
+
+// StatementSample 题面中的样例。题目有样例用例时数据取自用例，此处只提供按顺序对应的说明；
+// 没有样例用例时（如交互题）直接展示此处的数据
+type StatementSample struct {
+	Input       string `json:"input" yaml:"input,omitempty"`
+	Output      string `json:"output" yaml:"output,omitempty"`
+	Explanation string `json:"explanation" yaml:"explanation,omitempty"` // Markdown
+}
+
+// Empty 题面各部分是否均为空
+func (s *Statement) Empty() bool {
+	return s.Background == "" && s.Description == "" && s.Input == "" &&
+		s.Output == "" && s.Hint == "" && len(s.Samples) == 0
+}
+
+// RenderedStatement 渲染后的题面，各部分为清理过的 HTML，公式保留为 KaTeX 可渲染的 span
+type RenderedStatement struct {
+	Background  string `json:"background"`
+	Description string `json:"description"`
+	Input       string `json:"input"`
+	Output      string `json:"output"`
+	Hint        string `json:"hint"`
+}
@@ -0,0 +1,45 @@
+package problem
+
+import (
+	"encoding/json"
+	"galaxy/internal/models"
+)
+
+// ====================== 管理端 ======================
+
+// SubtaskRequest 创建、更新子任务请求
+type SubtaskRequest struct {
+	Title        *string  `json:"title" binding:"omitempty,max=255"`
+	Score        float64  `json:"score" binding:"min=0"`                         // 子任务满分，min 方式使用
+	Aggregation  string   `json:"aggregation" binding:"omitempty,oneof=min sum"` // 默认 min
+	Dependencies []string `json:"dependencies"`                                  // 依赖的子任务 ID，须属于同一题目
+	Sort         int      `json:"sort"`
+}
+
+// SubtaskItem 子任务信息
+type SubtaskItem struct {
+	ID           string   `json:"id"`
+	Title        *string  `json:"title"`
+	Score        float64  `json:"score"`
+	Aggregation  string   `json:"aggregation"`
+	Dependencies []string `json:"dependencies"`
+	Sort         int      `json:"sort"`
+}
+
+// NewSubtaskItem 由子任务构建返回信息，依赖格式错误时视为没有依赖
+func NewSubtaskItem(subtask *models.ProblemSubtask) SubtaskItem {
+	dependencies := []string{}
+	if len(subtask.Dependencies) > 0 && string(subtask.Dependencies) != "null" {
+		if err := json.Unmarshal(subtask.Dependencies, &dependencies); err != nil || dependencies == nil {
+			dependencies = []string{}
+		}
+	}
+	return SubtaskItem{
+		ID:           subtask.ID,
+		Title:        subtask.Title,
+		Score:        subtask.Score,
+		Aggregation:  subtask.Aggregation,
+		Dependencies: dependencies,
+		Sort:         subtask.Sort,
+	}
+}
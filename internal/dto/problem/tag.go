@@ -0,0 +1,70 @@
+package problem
+
+import (
+	"galaxy/internal/models"
+	"time"
+)
+
+// 批量设置标签的方式
+const (
+	TagAssignAdd     = "add"     // 追加标签
+	TagAssignRemove  = "remove"  // 移除标签
+	TagAssignReplace = "replace" // 整体替换标签
+)
+
+// ====================== 管理端 ======================
+
+// TagRequest 创建、更新标签请求
+type TagRequest struct {
+	Name      string `json:"name" binding:"required,max=255"`
+	ParentID  string `json:"parent_id"`  // 为空或 "0" 表示顶级标签
+	IsVisible *bool  `json:"is_visible"` // 为空时创建默认可见，更新保持不变
+}
+
+// TagItem 标签信息
+type TagItem struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	ParentID   string    `json:"parent_id"`
+	IsVisible  bool      `json:"is_visible"`
+	CreateTime time.Time `json:"create_time"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+func NewTagItem(tag *models.ContentTag) *TagItem {
+	return &TagItem{
+		ID:         tag.ID,
+		Name:       tag.Name,
+		ParentID:   tag.ParentID,
+		IsVisible:  tag.IsVisible,
+		CreateTime: tag.CreatedAt,
+		UpdateTime: tag.UpdatedAt,
+	}
+}
+
+// AssignTagsRequest 批量设置题目标签请求
+type AssignTagsRequest struct {
+	ProblemIDs []string `json:"problem_ids" binding:"required,min=1,max=500"`
+	TagIDs     []string `json:"tag_ids"`
+	Mode       string   `json:"mode"` // add/remove/replace，默认 add
+}
+
+// AssignTagsResult 批量设置标签结果
+type AssignTagsResult struct {
+	ProblemCount int `json:"problem_count"`
+	Added        int `json:"added"`
+	Removed      int `json:"removed"`
+}
+
+// ====================== 公共 ======================
+
+// TagNode 标签树节点，题目数按标签及其子标签去重统计
+type TagNode struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	ParentID     string     `json:"parent_id"`
+	IsVisible    bool       `json:"is_visible"`
+	ProblemCount int        `json:"problem_count"`
+	SolvedCount  int        `json:"solved_count"` // 当前用户已通过的题目数，未登录时为 0
+	Children     []*TagNode `json:"children"`
+}
@@ -0,0 +1,32 @@
+package problem
+
+// TestCaseConfig 测试数据压缩包中的 config.yaml，按用例名（不含扩展名）配置
+//
+//	cases:
+//	  "1": {score: 10, sample: true}
+//	  "2": {score: 20, subtask: "<子任务ID或标题>"}
+type TestCaseConfig struct {
+	Cases map[string]TestCaseOption `yaml:"cases"`
+}
+
+// TestCaseOption 单个用例的配置
+type TestCaseOption struct {
+	Score   float64 `yaml:"score"`
+	Sample  bool    `yaml:"sample"`
+	Subtask string  `yaml:"subtask"`
+}
+
+// TestCaseItem 用例信息（不含数据内容）
+type TestCaseItem struct {
+	ID             string  `json:"id"`
+	CaseSign       *string `json:"case_sign"`
+	InputFilePath  *string `json:"input_file_path"`
+	InputFileSize  int64   `json:"input_file_size"`
+	InputFileHash  *string `json:"input_file_hash"`
+	OutputFilePath *string `json:"output_file_path"`
+	OutputFileSize int64   `json:"output_file_size"`
+	OutputFileHash *string `json:"output_file_hash"`
+	IsSample       bool    `json:"is_sample"`
+	Score          float64 `json:"score"`
+	SubtaskID      *string `json:"subtask_id"`
+}
@@ -0,0 +1,74 @@
+package submission
+
+import "time"
+
+// ====================== 提交代码 ======================
+
+// SubmitRequest 提交代码请求，ContestID 为空表示题库提交
+type SubmitRequest struct {
+	ProblemID string `json:"problem_id" binding:"required"` // 题目 ID 或展示编号
+	Language  string `json:"language" binding:"required"`
+	Code      string `json:"code" binding:"required"`
+	ContestID string `json:"contest_id"`
+}
+
+// SubmitResponse 提交代码响应
+type SubmitResponse struct {
+	SubmitID string `json:"submit_id"`
+}
+
+// ====================== 提交记录 ======================
+
+// SubmissionItem 提交列表项（不含代码）
+type SubmissionItem struct {
+	ID         string    `json:"id"`
+	UserID     *string   `json:"user_id"`
+	ModuleType *string   `json:"module_type"`
+	ModuleID   *string   `json:"module_id"`
+	ProblemID  *string   `json:"problem_id"`
+	Language   *string   `json:"language"`
+	CodeLength int       `json:"code_length"`
+	MaxTime    int       `json:"max_time"`
+	MaxMemory  int       `json:"max_memory"`
+	Score      float64   `json:"score"`
+	Status     *string   `json:"status"`
+	IsFinish   bool      `json:"is_finish"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+// SubmissionDetail 提交详情，代码仅对提交者本人返回
+type SubmissionDetail struct {
+	SubmissionItem
+	Code     *string       `json:"code"`
+	Message  *string       `json:"message"`
+	Cases    []CaseItem    `json:"cases"`
+	Subtasks []SubtaskItem `json:"subtasks"`
+}
+
+// CaseItem 用例判题结果，非样例用例不返回数据
+type CaseItem struct {
+	ID             string  `json:"id"`
+	CaseSign       *string `json:"case_sign"`
+	SubtaskID      *string `json:"subtask_id"`
+	IsSample       bool    `json:"is_sample"`
+	InputData      *string `json:"input_data,omitempty"`
+	OutputData     *string `json:"output_data,omitempty"`
+	ExpectedOutput *string `json:"expected_output,omitempty"`
+	MaxTime        float64 `json:"max_time"`
+	MaxMemory      float64 `json:"max_memory"`
+	Score          float64 `json:"score"`
+	Status         *string `json:"status"`
+	Message        *string `json:"message"`
+	ExitCode       int64   `json:"exit_code"`
+}
+
+// SubtaskItem 子任务判题结果
+type SubtaskItem struct {
+	SubtaskID string  `json:"subtask_id"`
+	Title     *string `json:"title"`
+	Score     float64 `json:"score"`
+	FullScore float64 `json:"full_score"`
+	Status    *string `json:"status"`
+	MaxTime   int     `json:"max_time"`
+	MaxMemory int     `json:"max_memory"`
+}
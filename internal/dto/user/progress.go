@@ -0,0 +1,58 @@
+package user
+
+import "time"
+
+// UserProgress 用户做题概况
+type UserProgress struct {
+	AccountID      string `json:"account_id"`
+	SubmitCount    int64  `json:"submit_count"`
+	AcceptedCount  int64  `json:"accepted_count"`
+	AttemptedCount int64  `json:"attempted_count"`
+	SolvedCount    int64  `json:"solved_count"`
+	// 连续做题天数，按有通过提交的自然日计算；今天尚未通过时从昨天起算
+	CurrentStreak int                   `json:"current_streak"`
+	LongestStreak int                   `json:"longest_streak"`
+	Heatmap       []HeatmapDay          `json:"heatmap"` // 最近 365 天，按日期升序，包含没有提交的日期
+	Difficulties  []DifficultyBreakdown `json:"difficulties"`
+	Tags          []TagBreakdown        `json:"tags"`
+	Languages     []LanguageBreakdown   `json:"languages"`
+}
+
+// HeatmapDay 每日提交数
+type HeatmapDay struct {
+	Date          string `json:"date"` // YYYY-MM-DD
+	SubmitCount   int64  `json:"submit_count"`
+	AcceptedCount int64  `json:"accepted_count"`
+}
+
+// DifficultyBreakdown 按难度统计尝试与通过的题目数
+type DifficultyBreakdown struct {
+	Difficulty int   `json:"difficulty"`
+	Attempted  int64 `json:"attempted"`
+	Solved     int64 `json:"solved"`
+}
+
+// TagBreakdown 按标签统计尝试与通过的题目数
+type TagBreakdown struct {
+	TagID     string `json:"tag_id"`
+	Name      string `json:"name"`
+	Attempted int64  `json:"attempted"`
+	Solved    int64  `json:"solved"`
+}
+
+// LanguageBreakdown 按语言统计提交与通过次数
+type LanguageBreakdown struct {
+	Language      string `json:"language"`
+	SubmitCount   int64  `json:"submit_count"`
+	AcceptedCount int64  `json:"accepted_count"`
+}
+
+// ProgressProblem 已通过或尝试过的题目
+type ProgressProblem struct {
+	ProblemID       string     `json:"problem_id"`
+	DisplayID       *string    `json:"display_id"`
+	Title           *string    `json:"title"`
+	Difficulty      int        `json:"difficulty"`
+	FirstSubmitTime *time.Time `json:"first_submit_time"`
+	FirstSolvedTime *time.Time `json:"first_solved_time"` // 未通过时为空
+}
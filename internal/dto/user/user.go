@@ -36,6 +36,11 @@ type UserPublicAssociatedInfo struct {
 	LikeCount    int64 `json:"like_count"`
 	FollowCount  int64 `json:"follow_count"`
 	FansCount    int64 `json:"fans_count"`
+	// 做题统计
+	SubmitCount    int64 `json:"submit_count"`
+	AcceptedCount  int64 `json:"accepted_count"`
+	AttemptedCount int64 `json:"attempted_count"`
+	SolvedCount    int64 `json:"solved_count"`
 }
 
 // UserAssociatedProfile 用户公开关联信息
@@ -43,3 +48,10 @@ type UserAssociatedProfile struct {
 	models.UserInfo
 	models.UserProfile
 }
+
+// PrivacySettings 隐私设置，未传的字段不修改
+type PrivacySettings struct {
+	ShowBirthday *bool `json:"show_birthday"`
+	ShowLocation *bool `json:"show_location"`
+	ShowProgress *bool `json:"show_progress"` // 是否向他人公开做题记录
+}
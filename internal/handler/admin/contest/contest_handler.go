@@ -0,0 +1,279 @@
+package contest
+
+import (
+	"encoding/json"
+	dto "galaxy/internal/dto/contest"
+	contestQuery "galaxy/internal/query/contest"
+	"galaxy/internal/service/admin/contest"
+	"galaxy/pkg/handler"
+	"galaxy/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ContestHandler struct {
+	handler.BaseHandler
+	contestService contest.ContestService
+}
+
+func NewContestHandler() *ContestHandler {
+	return &ContestHandler{
+		contestService: contest.NewContestService(),
+	}
+}
+
+// CreateContest 创建竞赛
+func (h *ContestHandler) CreateContest(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req dto.ContestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.contestService.CreateContest(&req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// GetContest 获取竞赛详情
+func (h *ContestHandler) GetContest(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	result, err := h.contestService.GetContest(id)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// UpdateContest 更新竞赛
+func (h *ContestHandler) UpdateContest(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	var req dto.ContestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.contestService.UpdateContest(id, &req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// DeleteContest 删除竞赛
+func (h *ContestHandler) DeleteContest(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	if err := h.contestService.DeleteContest(id, c.GetString("account_id")); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// ContestList 获取竞赛列表
+func (h *ContestHandler) ContestList(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req contestQuery.ContestQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.contestService.ContestList(&req)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// SetProblems 设置竞赛题目
+func (h *ContestHandler) SetProblems(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	var req dto.ContestProblemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.contestService.SetProblems(id, &req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// Scoreboard 获取完整榜单
+func (h *ContestHandler) Scoreboard(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	result, err := h.contestService.Scoreboard(id)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// Unfreeze 赛后解除封榜
+func (h *ContestHandler) Unfreeze(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	if err := h.contestService.Unfreeze(id, c.GetString("account_id")); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// EventFeed 下载 CDS 事件流，每行一条事件
+func (h *ContestHandler) EventFeed(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	events, err := h.contestService.EventFeed(id)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	// 响应头发出后无法再返回错误，只记录日志
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="event-feed.json"`)
+	encoder := json.NewEncoder(c.Writer)
+	for i := range events {
+		if err := encoder.Encode(&events[i]); err != nil {
+			logger.Error().Err(err).Str("contest_id", id).Msg("Failed to export event feed")
+			return
+		}
+	}
+}
+
+// Invites 获取已授权访问竞赛的用户
+func (h *ContestHandler) Invites(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	result, err := h.contestService.Invites(id)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// Invite 授权用户访问竞赛
+func (h *ContestHandler) Invite(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	var req dto.InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.contestService.Invite(id, &req, c.GetString("account_id")); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// RemoveInvite 撤销用户的访问授权
+func (h *ContestHandler) RemoveInvite(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+	userID := c.Param("user_id")
+	if userID == "" {
+		h.BadRequest(c, "用户ID不能为空")
+		return
+	}
+
+	if err := h.contestService.RemoveInvite(id, userID, c.GetString("account_id")); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, nil)
+}
@@ -0,0 +1,100 @@
+package judge
+
+import (
+	"errors"
+	"galaxy/internal/service/admin/judge"
+	"galaxy/pkg/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RejudgeHandler struct {
+	handler.BaseHandler
+	rejudgeService judge.RejudgeService
+}
+
+func NewRejudgeHandler() *RejudgeHandler {
+	return &RejudgeHandler{
+		rejudgeService: judge.NewRejudgeService(),
+	}
+}
+
+// RejudgeSubmission 重判单个提交
+func (h *RejudgeHandler) RejudgeSubmission(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "提交ID不能为空")
+		return
+	}
+
+	task, err := h.rejudgeService.RejudgeSubmission(id, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, task)
+}
+
+// RejudgeProblem 重判题目的全部提交
+func (h *RejudgeHandler) RejudgeProblem(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	task, err := h.rejudgeService.RejudgeProblem(id, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, task)
+}
+
+// RejudgeContest 重判竞赛的全部提交
+func (h *RejudgeHandler) RejudgeContest(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	task, err := h.rejudgeService.RejudgeContest(id, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, task)
+}
+
+// GetTask 查询重判进度
+func (h *RejudgeHandler) GetTask(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "任务ID不能为空")
+		return
+	}
+
+	progress, err := h.rejudgeService.GetTask(id)
+	if err != nil {
+		if errors.Is(err, judge.ErrRejudgeTaskNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.InternalServerError(c, "获取重判进度失败")
+		return
+	}
+
+	h.Success(c, progress)
+}
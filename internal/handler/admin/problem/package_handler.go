@@ -0,0 +1,69 @@
+package problem
+
+import (
+	"galaxy/internal/service/admin/problem"
+	"galaxy/pkg/handler"
+	"galaxy/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PackageHandler struct {
+	handler.BaseHandler
+	packageService problem.PackageService
+}
+
+func NewPackageHandler() *PackageHandler {
+	return &PackageHandler{
+		packageService: problem.NewPackageService(),
+	}
+}
+
+// ImportPackage 导入 FPS / Polygon / Galaxy 题目包，可通过 format 指定格式
+func (h *PackageHandler) ImportPackage(c *gin.Context) {
+	h.StartTimer(c)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.BadRequest(c, "请上传题目包")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	results, err := h.packageService.ImportPackage(c.PostForm("format"), file, fileHeader.Size, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, results)
+}
+
+// ExportPackage 导出 Galaxy 题目包
+func (h *PackageHandler) ExportPackage(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	export, err := h.packageService.ExportPackage(id)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	// 响应头发出后无法再返回错误，只记录日志
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	if err := export.Write(c.Writer); err != nil {
+		logger.Error().Err(err).Str("problem_id", id).Msg("Failed to export problem package")
+	}
+}
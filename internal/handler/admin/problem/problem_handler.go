@@ -0,0 +1,121 @@
+package problem
+
+import (
+	dto "galaxy/internal/dto/problem"
+	problemQuery "galaxy/internal/query/problem"
+	"galaxy/internal/service/admin/problem"
+	"galaxy/pkg/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProblemHandler struct {
+	handler.BaseHandler
+	problemService problem.ProblemService
+}
+
+func NewProblemHandler() *ProblemHandler {
+	return &ProblemHandler{
+		problemService: problem.NewProblemService(),
+	}
+}
+
+// CreateProblem 创建题目
+func (h *ProblemHandler) CreateProblem(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req dto.ProblemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.problemService.CreateProblem(&req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// GetProblem 获取题目详情
+func (h *ProblemHandler) GetProblem(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	result, err := h.problemService.GetProblem(id)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// UpdateProblem 更新题目
+func (h *ProblemHandler) UpdateProblem(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	var req dto.ProblemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.problemService.UpdateProblem(id, &req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// DeleteProblem 删除题目
+func (h *ProblemHandler) DeleteProblem(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	if err := h.problemService.DeleteProblem(id, c.GetString("account_id")); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// ProblemList 获取题目列表
+func (h *ProblemHandler) ProblemList(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req problemQuery.ProblemQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.problemService.ProblemList(&req)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
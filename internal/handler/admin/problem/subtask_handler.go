@@ -0,0 +1,107 @@
+package problem
+
+import (
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/service/admin/problem"
+	"galaxy/pkg/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SubtaskHandler struct {
+	handler.BaseHandler
+	subtaskService problem.SubtaskService
+}
+
+func NewSubtaskHandler() *SubtaskHandler {
+	return &SubtaskHandler{
+		subtaskService: problem.NewSubtaskService(),
+	}
+}
+
+// SubtaskList 获取题目的子任务列表
+func (h *SubtaskHandler) SubtaskList(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	items, err := h.subtaskService.SubtaskList(id)
+	if err != nil {
+		h.InternalServerError(c, "获取子任务列表失败")
+		return
+	}
+
+	h.Success(c, items)
+}
+
+// CreateSubtask 创建子任务
+func (h *SubtaskHandler) CreateSubtask(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	var req dto.SubtaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.subtaskService.CreateSubtask(id, &req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// UpdateSubtask 更新子任务
+func (h *SubtaskHandler) UpdateSubtask(c *gin.Context) {
+	h.StartTimer(c)
+
+	id, subtaskID := c.Param("id"), c.Param("subtask_id")
+	if id == "" || subtaskID == "" {
+		h.BadRequest(c, "题目ID与子任务ID不能为空")
+		return
+	}
+
+	var req dto.SubtaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.subtaskService.UpdateSubtask(id, subtaskID, &req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// DeleteSubtask 删除子任务
+func (h *SubtaskHandler) DeleteSubtask(c *gin.Context) {
+	h.StartTimer(c)
+
+	id, subtaskID := c.Param("id"), c.Param("subtask_id")
+	if id == "" || subtaskID == "" {
+		h.BadRequest(c, "题目ID与子任务ID不能为空")
+		return
+	}
+
+	if err := h.subtaskService.DeleteSubtask(id, subtaskID, c.GetString("account_id")); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, nil)
+}
@@ -0,0 +1,114 @@
+package problem
+
+import (
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/service/admin/problem"
+	"galaxy/pkg/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	handler.BaseHandler
+	tagService problem.TagService
+}
+
+func NewTagHandler() *TagHandler {
+	return &TagHandler{
+		tagService: problem.NewTagService(),
+	}
+}
+
+// CreateTag 创建标签
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.tagService.CreateTag(&req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// UpdateTag 更新标签
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "标签ID不能为空")
+		return
+	}
+
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.tagService.UpdateTag(id, &req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// DeleteTag 删除标签
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "标签ID不能为空")
+		return
+	}
+
+	if err := h.tagService.DeleteTag(id, c.GetString("account_id")); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// TagTree 获取全部标签的树形结构
+func (h *TagHandler) TagTree(c *gin.Context) {
+	h.StartTimer(c)
+
+	result, err := h.tagService.TagTree()
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// AssignTags 批量设置题目标签
+func (h *TagHandler) AssignTags(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req dto.AssignTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.tagService.AssignTags(&req, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
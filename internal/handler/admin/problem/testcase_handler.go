@@ -0,0 +1,69 @@
+package problem
+
+import (
+	"galaxy/internal/service/admin/problem"
+	"galaxy/pkg/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TestCaseHandler struct {
+	handler.BaseHandler
+	testCaseService problem.TestCaseService
+}
+
+func NewTestCaseHandler() *TestCaseHandler {
+	return &TestCaseHandler{
+		testCaseService: problem.NewTestCaseService(),
+	}
+}
+
+// UploadTestCases 上传测试数据压缩包，替换题目现有用例
+func (h *TestCaseHandler) UploadTestCases(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		h.BadRequest(c, "请上传测试数据压缩包")
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+	defer file.Close()
+
+	items, err := h.testCaseService.UploadTestCases(id, file, fileHeader.Size, c.GetString("account_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, items)
+}
+
+// TestCaseList 获取题目的用例列表
+func (h *TestCaseHandler) TestCaseList(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	items, err := h.testCaseService.TestCaseList(id)
+	if err != nil {
+		h.InternalServerError(c, "获取用例列表失败")
+		return
+	}
+
+	h.Success(c, items)
+}
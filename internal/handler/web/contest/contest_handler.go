@@ -0,0 +1,197 @@
+package contest
+
+import (
+	"errors"
+	dto "galaxy/internal/dto/contest"
+	contestQuery "galaxy/internal/query/contest"
+	shareContest "galaxy/internal/service/share/contest"
+	"galaxy/internal/service/web/contest"
+	"galaxy/pkg/handler"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ContestHandler struct {
+	handler.BaseHandler
+	contestService contest.ContestService
+}
+
+func NewContestHandler() *ContestHandler {
+	return &ContestHandler{
+		contestService: contest.NewContestService(),
+	}
+}
+
+// ContestList 获取竞赛列表
+func (h *ContestHandler) ContestList(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req contestQuery.ContestQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.contestService.ContestList(&req)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// GetContest 获取竞赛详情
+func (h *ContestHandler) GetContest(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	result, err := h.contestService.GetContest(id, c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, shareContest.ErrContestNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.InternalServerError(c, "获取竞赛详情失败")
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// Register 报名竞赛
+func (h *ContestHandler) Register(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	// 个人赛可以不带请求体
+	var req dto.RegisterRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	if err := h.contestService.Register(id, c.GetString("account_id"), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// CancelRegistration 取消报名
+func (h *ContestHandler) CancelRegistration(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	if err := h.contestService.CancelRegistration(id, c.GetString("account_id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// Unlock 输入竞赛密码获得访问授权
+func (h *ContestHandler) Unlock(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	var req dto.UnlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.contestService.Unlock(id, c.GetString("account_id"), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, nil)
+}
+
+// GetProblem 获取竞赛题目详情
+func (h *ContestHandler) GetProblem(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	code := c.Param("code")
+	if id == "" || code == "" {
+		h.BadRequest(c, "竞赛ID与题目编号不能为空")
+		return
+	}
+
+	result, err := h.contestService.GetProblem(id, code)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// Scoreboard 获取竞赛榜单
+func (h *ContestHandler) Scoreboard(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "竞赛ID不能为空")
+		return
+	}
+
+	result, err := h.contestService.Scoreboard(id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, result)
+}
+
+func (h *ContestHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, shareContest.ErrContestNotFound),
+		errors.Is(err, contest.ErrProblemNotFound):
+		h.NotFound(c, err.Error())
+	case shareContest.AccessDenied(err):
+		h.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, contest.ErrUnlockTooFrequent):
+		h.Error(c, http.StatusTooManyRequests, err.Error())
+	case errors.Is(err, shareContest.ErrWrongPassword),
+		errors.Is(err, shareContest.ErrRegisterNotOpen),
+		errors.Is(err, shareContest.ErrRegisterClosed),
+		errors.Is(err, shareContest.ErrAlreadyRegistered),
+		errors.Is(err, shareContest.ErrNotRegistered),
+		errors.Is(err, shareContest.ErrContestNotStarted),
+		errors.Is(err, contest.ErrTeamNameRequired),
+		errors.Is(err, contest.ErrContestStarted):
+		h.BadRequest(c, err.Error())
+	default:
+		h.InternalServerError(c, "竞赛操作失败")
+	}
+}
@@ -0,0 +1,76 @@
+package problem
+
+import (
+	"errors"
+	problemQuery "galaxy/internal/query/problem"
+	"galaxy/internal/service/web/problem"
+	"galaxy/pkg/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProblemHandler struct {
+	handler.BaseHandler
+	problemService problem.ProblemService
+}
+
+func NewProblemHandler() *ProblemHandler {
+	return &ProblemHandler{
+		problemService: problem.NewProblemService(),
+	}
+}
+
+// ProblemList 获取公开题目列表
+func (h *ProblemHandler) ProblemList(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req problemQuery.ProblemQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.problemService.ProblemList(&req)
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// GetProblem 获取公开题目详情，id 可为题目 ID 或展示编号
+func (h *ProblemHandler) GetProblem(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "题目ID不能为空")
+		return
+	}
+
+	result, err := h.problemService.GetProblem(id)
+	if err != nil {
+		if errors.Is(err, problem.ErrProblemNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		h.InternalServerError(c, "获取题目详情失败")
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// TagTree 获取标签树及各标签的题目数，登录时包含已通过的题目数
+func (h *ProblemHandler) TagTree(c *gin.Context) {
+	h.StartTimer(c)
+
+	result, err := h.problemService.TagTree(c.GetString("user_id"))
+	if err != nil {
+		h.InternalServerError(c, "获取标签失败")
+		return
+	}
+
+	h.Success(c, result)
+}
@@ -0,0 +1,239 @@
+package submission
+
+import (
+	"errors"
+	dto "galaxy/internal/dto/submission"
+	"galaxy/internal/judge/progress"
+	submissionQuery "galaxy/internal/query/submission"
+	shareContest "galaxy/internal/service/share/contest"
+	"galaxy/internal/service/web/submission"
+	"galaxy/pkg/handler"
+	"galaxy/pkg/logger"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// heartbeatInterval SSE 心跳间隔，防止代理断开空闲连接
+const heartbeatInterval = 15 * time.Second
+
+type SubmissionHandler struct {
+	handler.BaseHandler
+	submissionService submission.SubmissionService
+}
+
+func NewSubmissionHandler() *SubmissionHandler {
+	return &SubmissionHandler{
+		submissionService: submission.NewSubmissionService(),
+	}
+}
+
+// Submit 提交代码
+func (h *SubmissionHandler) Submit(c *gin.Context) {
+	h.StartTimer(c)
+
+	accountID := c.GetString("account_id")
+	if accountID == "" {
+		h.Unauthorized(c, "认证失败")
+		return
+	}
+
+	var req dto.SubmitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.submissionService.Submit(accountID, &req)
+	if err != nil {
+		if errors.Is(err, submission.ErrSubmitTooFrequent) {
+			h.Error(c, http.StatusTooManyRequests, err.Error())
+			return
+		}
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// GetSubmission 获取提交详情
+func (h *SubmissionHandler) GetSubmission(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "提交ID不能为空")
+		return
+	}
+
+	detail, err := h.submissionService.GetSubmission(id, c.GetString("user_id"))
+	if err != nil {
+		if errors.Is(err, submission.ErrSubmissionNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		if shareContest.AccessDenied(err) {
+			h.Error(c, http.StatusForbidden, err.Error())
+			return
+		}
+		h.InternalServerError(c, "获取提交详情失败")
+		return
+	}
+
+	h.Success(c, detail)
+}
+
+// SubmissionList 获取提交列表
+func (h *SubmissionHandler) SubmissionList(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req submissionQuery.SubmissionQueryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.submissionService.SubmissionList(&req, c.GetString("user_id"))
+	if err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// StreamSubmission 以 SSE 推送单个提交的判题进度，判题结束后关闭
+func (h *SubmissionHandler) StreamSubmission(c *gin.Context) {
+	h.StartTimer(c)
+
+	id := c.Param("id")
+	if id == "" {
+		h.BadRequest(c, "提交ID不能为空")
+		return
+	}
+
+	// 先订阅再读取当前状态，避免错过两者之间发布的事件
+	sub, err := progress.Subscribe(c.Request.Context(), progress.SubmissionChannel(id))
+	if err != nil {
+		h.InternalServerError(c, "订阅判题进度失败")
+		return
+	}
+	defer sub.Close()
+
+	viewerID := c.GetString("user_id")
+	current, err := h.submissionService.SubmissionEvent(id, viewerID)
+	if err != nil {
+		if errors.Is(err, submission.ErrSubmissionNotFound) {
+			h.NotFound(c, err.Error())
+			return
+		}
+		if shareContest.AccessDenied(err) {
+			h.Error(c, http.StatusForbidden, err.Error())
+			return
+		}
+		h.InternalServerError(c, "获取提交状态失败")
+		return
+	}
+	hidden, err := h.submissionService.ResultHidden(id, viewerID)
+	if err != nil {
+		h.InternalServerError(c, "获取提交状态失败")
+		return
+	}
+
+	// 编译信息仅对提交者本人可见，SubmissionEvent 已按同一规则处理当前状态
+	owner := viewerID != "" && current.UserID == viewerID
+	filter := func(event *progress.Event) *progress.Event {
+		if hidden {
+			return event.Redact()
+		}
+		if !owner {
+			return event.StripMessage()
+		}
+		return event
+	}
+	h.stream(c, current, sub, true, filter)
+}
+
+// StreamUser 以 SSE 推送当前用户全部提交的判题进度
+func (h *SubmissionHandler) StreamUser(c *gin.Context) {
+	h.StartTimer(c)
+
+	accountID := c.GetString("account_id")
+	if accountID == "" {
+		h.Unauthorized(c, "认证失败")
+		return
+	}
+
+	sub, err := progress.Subscribe(c.Request.Context(), progress.UserChannel(accountID))
+	if err != nil {
+		h.InternalServerError(c, "订阅判题进度失败")
+		return
+	}
+	defer sub.Close()
+
+	// 按提交缓存是否隐藏结果，查询失败时按隐藏处理且不缓存
+	hidden := make(map[string]bool)
+	filter := func(event *progress.Event) *progress.Event {
+		value, ok := hidden[event.SubmitID]
+		if !ok {
+			var err error
+			if value, err = h.submissionService.ResultHidden(event.SubmitID, accountID); err != nil {
+				logger.Warn().Str("submit_id", event.SubmitID).Err(err).Msg("Check submission visibility failed")
+				value = true
+			} else {
+				hidden[event.SubmitID] = value
+			}
+		}
+		if value {
+			return event.Redact()
+		}
+		return event
+	}
+	h.stream(c, nil, sub, false, filter)
+}
+
+// stream 写出 SSE 事件，stopOnFinish 为 true 时收到最终判定后结束。
+// filter 不为空时推送前处理事件，返回 nil 表示不推送该事件
+func (h *SubmissionHandler) stream(c *gin.Context, first *progress.Event, sub *progress.Subscription, stopOnFinish bool,
+	filter func(*progress.Event) *progress.Event) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	if first != nil {
+		c.SSEvent(first.Type, first)
+		c.Writer.Flush()
+		if stopOnFinish && first.Type == progress.TypeFinish {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-sub.Events:
+			if !ok {
+				return false
+			}
+			if filter != nil {
+				if event = filter(event); event == nil {
+					return true
+				}
+			}
+			c.SSEvent(event.Type, event)
+			return !stopOnFinish || event.Type != progress.TypeFinish
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
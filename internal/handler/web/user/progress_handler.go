@@ -0,0 +1,83 @@
+package user
+
+import (
+	"errors"
+	"galaxy/internal/service/web/user"
+	"galaxy/pkg/handler"
+	"galaxy/pkg/query"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ProgressHandler struct {
+	handler.BaseHandler
+	progressService user.ProgressService
+}
+
+func NewProgressHandler() *ProgressHandler {
+	return &ProgressHandler{
+		progressService: user.NewProgressService(),
+	}
+}
+
+// GetProgress 获取用户做题概况、提交热力图与分类统计
+func (h *ProgressHandler) GetProgress(c *gin.Context) {
+	h.StartTimer(c)
+
+	progress, err := h.progressService.GetProgress(c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, progress)
+}
+
+// SolvedProblems 获取用户已通过的题目
+func (h *ProgressHandler) SolvedProblems(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req query.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.progressService.SolvedProblems(c.Param("id"), c.GetString("user_id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, result)
+}
+
+// AttemptedProblems 获取用户尝试过但未通过的题目
+func (h *ProgressHandler) AttemptedProblems(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req query.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	result, err := h.progressService.AttemptedProblems(c.Param("id"), c.GetString("user_id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	h.Success(c, result)
+}
+
+func (h *ProgressHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, user.ErrUserNotFound):
+		h.NotFound(c, err.Error())
+	case errors.Is(err, user.ErrProgressHidden):
+		h.Error(c, http.StatusForbidden, err.Error())
+	default:
+		h.InternalServerError(c, "获取做题记录失败")
+	}
+}
@@ -1,6 +1,7 @@
 package user
 
 import (
+	dto "galaxy/internal/dto/user"
 	"galaxy/internal/service/web/user"
 	"galaxy/pkg/handler"
 	"github.com/gin-gonic/gin"
@@ -48,3 +49,21 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 
 	h.Success(c, associatedInfo)
 }
+
+// UpdatePrivacy 更新当前用户的隐私设置
+func (h *UserHandler) UpdatePrivacy(c *gin.Context) {
+	h.StartTimer(c)
+
+	var req dto.PrivacySettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.BadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.userService.UpdatePrivacy(c.GetString("account_id"), &req); err != nil {
+		h.Error(c, http.StatusInternalServerError, "更新隐私设置失败")
+		return
+	}
+
+	h.Success(c, nil)
+}
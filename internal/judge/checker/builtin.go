@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"galaxy/internal/models"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// strictChecker 逐字节比较，仅空白不同时判为格式错误
+type strictChecker struct{}
+
+func (strictChecker) Check(_ context.Context, _, output, answer []byte) (*Result, error) {
+	if bytes.Equal(output, answer) {
+		return accepted(), nil
+	}
+	if tokensEqual(output, answer) {
+		return rejected(models.JudgeStatusPresentationError, "输出空白字符与答案不一致"), nil
+	}
+	return rejected(models.JudgeStatusWrongAnswer, "输出与答案不一致"), nil
+}
+
+// trailingChecker 忽略行末空白与末尾空行
+type trailingChecker struct{}
+
+func (trailingChecker) Check(_ context.Context, _, output, answer []byte) (*Result, error) {
+	outLines := trimLines(output)
+	ansLines := trimLines(answer)
+
+	for i := 0; i < len(outLines) && i < len(ansLines); i++ {
+		if !bytes.Equal(outLines[i], ansLines[i]) {
+			if tokensEqual(output, answer) {
+				return rejected(models.JudgeStatusPresentationError, "第 %d 行空白字符与答案不一致", i+1), nil
+			}
+			return rejected(models.JudgeStatusWrongAnswer, "第 %d 行与答案不一致", i+1), nil
+		}
+	}
+	if len(outLines) != len(ansLines) {
+		if tokensEqual(output, answer) {
+			return rejected(models.JudgeStatusPresentationError, "输出行数与答案不一致"), nil
+		}
+		return rejected(models.JudgeStatusWrongAnswer, "输出 %d 行，答案 %d 行", len(outLines), len(ansLines)), nil
+	}
+	return accepted(), nil
+}
+
+// floatChecker 按空白分词，可解析为数字的词按绝对或相对误差比较
+type floatChecker struct {
+	absEps float64
+	relEps float64
+}
+
+func (c floatChecker) Check(_ context.Context, _, output, answer []byte) (*Result, error) {
+	outTokens := bytes.Fields(output)
+	ansTokens := bytes.Fields(answer)
+	if len(outTokens) != len(ansTokens) {
+		return rejected(models.JudgeStatusWrongAnswer, "输出 %d 个数据，答案 %d 个", len(outTokens), len(ansTokens)), nil
+	}
+
+	for i := range ansTokens {
+		expected, errExpected := strconv.ParseFloat(string(ansTokens[i]), 64)
+		actual, errActual := strconv.ParseFloat(string(outTokens[i]), 64)
+		if errExpected != nil || errActual != nil {
+			if !bytes.Equal(outTokens[i], ansTokens[i]) {
+				return rejected(models.JudgeStatusWrongAnswer, "第 %d 个数据不一致", i+1), nil
+			}
+			continue
+		}
+		if !c.close(actual, expected) {
+			// 不输出答案中的数据，判题信息会展示给选手
+			return rejected(models.JudgeStatusWrongAnswer, "第 %d 个数据误差过大", i+1), nil
+		}
+	}
+	return accepted(), nil
+}
+
+func (c floatChecker) close(actual, expected float64) bool {
+	if math.IsNaN(actual) || math.IsNaN(expected) {
+		return math.IsNaN(actual) && math.IsNaN(expected)
+	}
+	diff := math.Abs(actual - expected)
+	return diff <= c.absEps || diff <= c.relEps*math.Abs(expected)
+}
+
+// unorderedChecker 忽略行的顺序与空行
+type unorderedChecker struct{}
+
+func (unorderedChecker) Check(_ context.Context, _, output, answer []byte) (*Result, error) {
+	outLines := nonEmptyLines(output)
+	ansLines := nonEmptyLines(answer)
+	if len(outLines) != len(ansLines) {
+		return rejected(models.JudgeStatusWrongAnswer, "输出 %d 行，答案 %d 行", len(outLines), len(ansLines)), nil
+	}
+
+	sortLines(outLines)
+	sortLines(ansLines)
+	for i := range ansLines {
+		if !bytes.Equal(outLines[i], ansLines[i]) {
+			return rejected(models.JudgeStatusWrongAnswer, "输出行集合与答案不一致"), nil
+		}
+	}
+	return accepted(), nil
+}
+
+// trimLines 按行切分，去掉行末空白与末尾空行
+func trimLines(data []byte) [][]byte {
+	lines := bytes.Split(data, []byte("\n"))
+	for i := range lines {
+		lines[i] = bytes.TrimRight(lines[i], " \t\r")
+	}
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// nonEmptyLines 去掉首尾空白后的非空行
+func nonEmptyLines(data []byte) [][]byte {
+	var lines [][]byte
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func sortLines(lines [][]byte) {
+	sort.Slice(lines, func(i, j int) bool {
+		return bytes.Compare(lines[i], lines[j]) < 0
+	})
+}
+
+// tokensEqual 忽略全部空白后是否一致
+func tokensEqual(a, b []byte) bool {
+	aTokens := bytes.Fields(a)
+	bTokens := bytes.Fields(b)
+	if len(aTokens) != len(bTokens) {
+		return false
+	}
+	for i := range aTokens {
+		if !bytes.Equal(aTokens[i], bTokens[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"galaxy/internal/models"
+	"strconv"
+	"strings"
+)
+
+// 比较方式，对应 ProblemInfo.CheckerType
+const (
+	TypeStrict    = "strict"    // 逐字节比较
+	TypeTrailing  = "trailing"  // 忽略行末空白与末尾空行
+	TypeFloat     = "float"     // 按空白分词，数字按误差比较
+	TypeUnordered = "unordered" // 忽略行的顺序
+	TypeSpecial   = "special"   // 题目提供的特殊判题程序
+)
+
+// Result 单个用例的比较结果
+type Result struct {
+	Status  string  // Accepted / Wrong Answer / Presentation Error / Partial Accepted
+	Ratio   float64 // 得分比例 0 - 1
+	Message string
+}
+
+// Checker 输出比较器
+type Checker interface {
+	Check(ctx context.Context, input, output, answer []byte) (*Result, error)
+}
+
+// New 按题目配置创建内置比较器，特殊判题需由调用方提供
+func New(problem *models.ProblemInfo) (Checker, error) {
+	switch problem.CheckerType {
+	case TypeStrict:
+		return strictChecker{}, nil
+	case "", TypeTrailing:
+		return trailingChecker{}, nil
+	case TypeFloat:
+		return floatChecker{absEps: problem.CheckerAbsEps, relEps: problem.CheckerRelEps}, nil
+	case TypeUnordered:
+		return unorderedChecker{}, nil
+	default:
+		return nil, fmt.Errorf("不支持的比较方式: %s", problem.CheckerType)
+	}
+}
+
+func accepted() *Result {
+	return &Result{Status: models.JudgeStatusAccepted, Ratio: 1}
+}
+
+func rejected(status, format string, args ...interface{}) *Result {
+	return &Result{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+// testlib 退出码
+const (
+	exitOK                = 0
+	exitWrongAnswer       = 1
+	exitPresentationError = 2
+	exitFail              = 3
+	exitDirt              = 4
+	exitPoints            = 7
+	exitUnexpectedEOF     = 8
+)
+
+// ParseTestlibResult 将 testlib 兼容程序的退出码与输出映射为比较结果
+func ParseTestlibResult(exitCode int, message string) (*Result, error) {
+	message = strings.TrimSpace(message)
+
+	switch {
+	case exitCode == exitOK:
+		return &Result{Status: models.JudgeStatusAccepted, Ratio: 1, Message: message}, nil
+	case exitCode == exitWrongAnswer, exitCode == exitDirt, exitCode == exitUnexpectedEOF:
+		return &Result{Status: models.JudgeStatusWrongAnswer, Message: message}, nil
+	case exitCode == exitPresentationError:
+		return &Result{Status: models.JudgeStatusPresentationError, Message: message}, nil
+	case exitCode == exitFail:
+		return nil, fmt.Errorf("判题程序失败: %s", message)
+	case exitCode == exitPoints:
+		// quitp 的输出以得分比例开头，如 "0.5 partially correct"
+		fields := strings.Fields(message)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("判题程序未给出得分: %s", message)
+		}
+		ratio, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("判题程序得分格式错误: %s", message)
+		}
+		return partial(ratio, message), nil
+	default:
+		return nil, fmt.Errorf("判题程序异常退出(%d): %s", exitCode, message)
+	}
+}
+
+func partial(ratio float64, message string) *Result {
+	if ratio >= 1 {
+		return &Result{Status: models.JudgeStatusAccepted, Ratio: 1, Message: message}
+	}
+	if ratio <= 0 {
+		return &Result{Status: models.JudgeStatusWrongAnswer, Message: message}
+	}
+	return &Result{Status: models.JudgeStatusPartialAccepted, Ratio: ratio, Message: message}
+}
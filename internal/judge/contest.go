@@ -0,0 +1,103 @@
+package judge
+
+import (
+	"context"
+	"galaxy/internal/models"
+	shareContest "galaxy/internal/service/share/contest"
+	"galaxy/internal/service/share/record"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/redis"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// contestStatusLockKey 竞赛状态流转锁，多个判题服务实例每个周期只执行一次
+const contestStatusLockKey = "contest:status:lock"
+
+// RunContestScheduler 按配置周期推进竞赛状态并补计已公布结果的竞赛统计，直到 ctx 被取消；未配置周期时直接返回
+func RunContestScheduler(ctx context.Context) {
+	seconds := config.Get().Contest.StatusInterval
+	if seconds <= 0 {
+		return
+	}
+	interval := time.Duration(seconds) * time.Second
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		updateContestStatuses(interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func updateContestStatuses(interval time.Duration) {
+	// 锁的有效期略短于周期，保证下个周期可以重新获取
+	ok, err := redis.SetNX(contestStatusLockKey, 1, interval*9/10)
+	if err != nil {
+		logger.Error().Err(err).Msg("Acquire contest status lock failed")
+		return
+	}
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	changed, err := shareContest.UpdateStatuses(database.GetDB(), now)
+	if err != nil {
+		logger.Error().Err(err).Msg("Update contest status failed")
+		return
+	}
+	if changed > 0 {
+		logger.Service("Judge").Int("changed", changed).Msg("Contest status updated")
+	}
+
+	if err := settleContests(now); err != nil {
+		logger.Error().Err(err).Msg("Settle contest statistics failed")
+	}
+}
+
+// settleContests 结果公布后补计竞赛提交的题目统计、解决记录与用户统计。
+// 仍有提交在判题时等到下个周期，避免漏掉按隐藏结果跳过增量统计、尚未写入的提交
+func settleContests(now time.Time) error {
+	db := database.GetDB()
+	contests, err := shareContest.Unsettled(db, now)
+	if err != nil {
+		return err
+	}
+
+	records := record.NewRecordService()
+	for i := range contests {
+		contestID := contests[i].ID
+		submits := func() *gorm.DB {
+			return db.Model(&models.JudgeSubmit{}).
+				Where("module_type = ? AND module_id = ?", models.ModuleTypeContest, contestID)
+		}
+		var pending int64
+		if err := submits().Where("is_finish = ?", false).Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			continue
+		}
+
+		count, err := recompute(db, records, submits)
+		if err != nil {
+			return err
+		}
+		if err := db.Model(&models.ContestInfo{}).
+			Where("id = ?", contestID).
+			UpdateColumn("settle_time", now).Error; err != nil {
+			return err
+		}
+		logger.Service("Judge").Str("contest_id", contestID).Int("records", count).Msg("Contest statistics settled")
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package judge
+
+import (
+	"context"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/redis"
+	"time"
+)
+
+// difficultyLockKey 经验难度估计锁，多个判题服务实例每个周期只执行一次
+const difficultyLockKey = "problem:difficulty:lock"
+
+// RunDifficultyEstimator 按配置周期估计题目经验难度，直到 ctx 被取消；未配置周期时直接返回
+func RunDifficultyEstimator(ctx context.Context) {
+	cfg := config.Get().Problem.Difficulty
+	if cfg.Interval <= 0 {
+		return
+	}
+	interval := time.Duration(cfg.Interval) * time.Minute
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		estimateDifficulty(cfg, interval)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func estimateDifficulty(cfg config.DifficultyConfig, interval time.Duration) {
+	ok, err := redis.SetNX(difficultyLockKey, 1, interval)
+	if err != nil {
+		logger.Error().Err(err).Msg("Acquire difficulty lock failed")
+		return
+	}
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	suggested, err := shareProblem.EstimateDifficulty(database.GetDB(), cfg)
+	if err != nil {
+		logger.Error().Err(err).Msg("Estimate difficulty failed")
+		return
+	}
+	logger.Service("Judge").
+		Int("suggested", suggested).
+		Dur("elapsed", time.Since(start)).
+		Msg("Difficulty estimated")
+}
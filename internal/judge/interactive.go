@@ -0,0 +1,145 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"galaxy/internal/judge/checker"
+	"galaxy/internal/models"
+	"galaxy/pkg/sandbox"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// interactor 交互题的交互程序：interactor <input> <output> <answer>
+//
+// 交互程序的标准输入输出与选手程序相连，output 为交互程序写出的结果，可再交给特殊判题程序检查。
+type interactor struct {
+	runner  *runner
+	command []string
+}
+
+// newInteractor 在 programDir 下的独立目录中编译交互程序
+func (j *Judger) newInteractor(ctx context.Context, problem *models.ProblemInfo, programDir string) (*interactor, error) {
+	runner, command, err := j.prepareProgram(ctx, filepath.Join(programDir, "interactor"), problem.InteractorCode, problem.InteractorLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("交互程序: %w", err)
+	}
+	return &interactor{runner: runner, command: command}, nil
+}
+
+// interaction 一次交互的结果
+type interaction struct {
+	run     *runResult      // 选手程序运行结果
+	checked *checker.Result // 交互程序的判定
+	output  []byte          // 交互程序写出的结果
+}
+
+// run 运行一次交互，时间与内存限制只作用于选手程序
+func (it *interactor) run(ctx context.Context, contestant *runner, args []string, input, answer []byte, timeLimit time.Duration, memoryLimit int64) (*interaction, error) {
+	dir := it.runner.dir
+	if err := os.WriteFile(filepath.Join(dir, "interactor_input.txt"), input, 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "interactor_answer.txt"), answer, 0o600); err != nil {
+		return nil, err
+	}
+	outputPath := filepath.Join(dir, "interactor_output.txt")
+	if err := os.Remove(outputPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// 交互程序 -> 选手程序
+	toContestantReader, toContestantWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	// 选手程序 -> 交互程序
+	toInteractorReader, toInteractorWriter, err := os.Pipe()
+	if err != nil {
+		toContestantReader.Close()
+		toContestantWriter.Close()
+		return nil, err
+	}
+
+	contestantStderr := &limitedBuffer{limit: stderrLimit}
+	contestantCfg := contestant.sandboxConfig(args, timeLimit, memoryLimit)
+	contestantCfg.Stdin = toContestantReader
+	contestantCfg.Stdout = toInteractorWriter
+	contestantCfg.Stderr = contestantStderr
+
+	interactorStderr := &limitedBuffer{limit: stderrLimit}
+	interactorArgs := append(append([]string{}, it.command...),
+		"interactor_input.txt", "interactor_output.txt", "interactor_answer.txt")
+	interactorCfg := it.runner.sandboxConfig(interactorArgs, checkerTimeLimit, checkerMemoryLimit)
+	interactorCfg.WallTimeLimit = timeLimit*2 + checkerTimeLimit
+	interactorCfg.Stdin = toInteractorReader
+	interactorCfg.Stdout = toContestantWriter
+	interactorCfg.Stderr = interactorStderr
+
+	// 任一方退出后关闭其管道端，另一方才能读到 EOF 或收到 SIGPIPE
+	var (
+		wg                                 sync.WaitGroup
+		contestantResult, interactorResult *sandbox.Result
+		contestantErr, interactorErr       error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer toContestantReader.Close()
+		defer toInteractorWriter.Close()
+		contestantResult, contestantErr = sandbox.Run(ctx, contestantCfg)
+	}()
+	go func() {
+		defer wg.Done()
+		defer toInteractorReader.Close()
+		defer toContestantWriter.Close()
+		interactorResult, interactorErr = sandbox.Run(ctx, interactorCfg)
+	}()
+	wg.Wait()
+
+	if contestantErr != nil {
+		return nil, contestantErr
+	}
+	if interactorErr != nil {
+		return nil, fmt.Errorf("交互程序: %w", interactorErr)
+	}
+	if interactorResult.Status != sandbox.StatusOK && interactorResult.Status != sandbox.StatusRuntimeError {
+		return nil, fmt.Errorf("交互程序运行异常: %s", interactorResult.Status)
+	}
+
+	checked, err := checker.ParseTestlibResult(interactorResult.ExitCode, interactorStderr.buf.String())
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := os.ReadFile(outputPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return &interaction{
+		run:     newRunResult(contestantResult, nil, contestantStderr.buf.Bytes()),
+		checked: checked,
+		output:  output,
+	}, nil
+}
+
+// verdict 合并选手程序与交互程序的结果
+//
+// 资源超限以选手程序为准；选手程序因交互程序提前结束而异常退出时，以交互程序的判定为准。
+func (r *interaction) verdict() (string, float64, string) {
+	status := r.run.Status
+	switch status {
+	case models.JudgeStatusTimeLimitExceeded,
+		models.JudgeStatusMemoryLimitExceeded,
+		models.JudgeStatusOutputLimitExceeded:
+		return status, 0, string(r.run.Stderr)
+	}
+	if r.checked.Status != models.JudgeStatusAccepted || status == "" {
+		return r.checked.Status, r.checked.Ratio, r.checked.Message
+	}
+	return status, 0, string(r.run.Stderr)
+}
@@ -0,0 +1,577 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"galaxy/internal/judge/checker"
+	"galaxy/internal/judge/language"
+	"galaxy/internal/judge/progress"
+	"galaxy/internal/judge/template"
+	"galaxy/internal/judge/testdata"
+	"galaxy/internal/models"
+	shareContest "galaxy/internal/service/share/contest"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/internal/service/share/record"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTimeLimit   = 1000 // 默认时间限制（毫秒）
+	defaultMemoryLimit = 256  // 默认内存限制（MB）
+	casePreviewSize    = 4096 // 用例数据在判题结果中保留的最大字节数
+)
+
+// verdict 提交的最终判定
+type verdict struct {
+	Status    string
+	Message   string
+	MaxTime   int                // 毫秒
+	MaxMemory int                // KB
+	Score     float64            // 各子任务得分之和
+	Subtasks  map[string]float64 // 各子任务得分，键为子任务 ID
+}
+
+// Judger 判题器
+type Judger struct {
+	db        *gorm.DB
+	cfg       config.JudgeConfig
+	languages *language.Registry
+	records   record.RecordService
+	data      *testdata.Cache
+}
+
+func NewJudger() *Judger {
+	cfg := config.Get().Judge
+	return &Judger{
+		db:        database.GetDB(),
+		cfg:       cfg,
+		languages: language.Get(),
+		records:   record.NewRecordService(),
+		data:      testdata.NewCache(cfg.DataRoot, cfg.CacheDir),
+	}
+}
+
+// Judge 判定一次提交，并将结果写回 JudgeSubmit / JudgeCase / JudgeSubtask
+func (j *Judger) Judge(ctx context.Context, submitID string) error {
+	var submit models.JudgeSubmit
+	if err := j.db.Where("id = ?", submitID).First(&submit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("提交 %s 不存在", submitID)
+		}
+		return err
+	}
+
+	// 已完成的提交不重复判题（重判会先重置 IsFinish）
+	if submit.IsFinish {
+		return nil
+	}
+
+	result, err := j.judge(ctx, &submit)
+	// 服务退出中断的判题不写回结果，由 Worker 放回队列
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		result = &verdict{
+			Status:  models.JudgeStatusSystemError,
+			Message: err.Error(),
+		}
+	}
+
+	if err := j.finish(&submit, result); err != nil {
+		return err
+	}
+
+	if submit.TaskID != nil && *submit.TaskID != "" {
+		if err := j.finishRejudge(*submit.TaskID); err != nil {
+			logger.Error().Str("task_id", *submit.TaskID).Err(err).Msg("Finish rejudge failed")
+		}
+	}
+	return nil
+}
+
+// judge 编译并逐个运行测试用例
+func (j *Judger) judge(ctx context.Context, submit *models.JudgeSubmit) (*verdict, error) {
+	if submit.ProblemID == nil || submit.Language == nil || submit.Code == nil {
+		return nil, errors.New("提交信息不完整")
+	}
+
+	var problem models.ProblemInfo
+	if err := j.db.Where("id = ?", *submit.ProblemID).First(&problem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("题目不存在")
+		}
+		return nil, err
+	}
+
+	allowed := []datatypes.JSON{problem.Languages}
+	if submit.ModuleType != nil && *submit.ModuleType == models.ModuleTypeContest && submit.ModuleID != nil {
+		var contest models.ContestInfo
+		if err := j.db.Where("id = ?", *submit.ModuleID).First(&contest).Error; err != nil {
+			return nil, err
+		}
+		allowed = append(allowed, contest.AllowedLanguages)
+	}
+	if err := j.languages.Validate(*submit.Language, allowed...); err != nil {
+		return &verdict{
+			Status:  models.JudgeStatusCompileError,
+			Message: err.Error(),
+		}, nil
+	}
+	lang, _ := j.languages.Get(*submit.Language)
+
+	var testCases []models.ProblemTestCase
+	if err := j.db.Where("problem_id = ?", problem.ID).
+		Order("create_time ASC, id ASC").
+		Find(&testCases).Error; err != nil {
+		return nil, err
+	}
+	if len(testCases) == 0 {
+		return nil, errors.New("题目没有测试用例")
+	}
+
+	// 准备工作目录
+	workDir := filepath.Join(j.cfg.WorkDir, submit.ID)
+	// 上次判题中断时可能遗留选手程序放置的符号链接，先整体删除再创建
+	if err := os.RemoveAll(workDir); err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(workDir, 0o700); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+	// 特殊判题程序与交互程序放在工作目录之外，选手程序无权访问
+	programDir := workDir + ".program"
+	if err := os.RemoveAll(programDir); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(programDir)
+
+	// 使用代码模板的题目，提交代码只是可编辑部分
+	code := *submit.Code
+	if problem.UseTemplate {
+		spliced, err := template.Splice(problem.CodeTemplate, lang.Name, code)
+		if err != nil {
+			return nil, err
+		}
+		code = spliced
+	}
+	if err := os.WriteFile(filepath.Join(workDir, lang.SourceFile), []byte(code), 0o644); err != nil {
+		return nil, err
+	}
+
+	timeLimit := problem.TimeLimit
+	if timeLimit <= 0 {
+		timeLimit = defaultTimeLimit
+	}
+	memoryLimit := problem.MemoryLimit
+	if memoryLimit <= 0 {
+		memoryLimit = defaultMemoryLimit
+	}
+
+	runner, err := newRunner(workDir, j.cfg, lang.Env)
+	if err != nil {
+		return nil, err
+	}
+
+	// 编译
+	if lang.NeedCompile() {
+		if err := j.setStatus(submit, models.JudgeStatusCompiling); err != nil {
+			return nil, err
+		}
+
+		success, output, err := runner.compile(ctx, lang.CompileCommand(memoryLimit))
+		if err != nil {
+			return nil, err
+		}
+		if !success {
+			return &verdict{
+				Status:  models.JudgeStatusCompileError,
+				Message: output,
+			}, nil
+		}
+	}
+
+	if err := j.setStatus(submit, models.JudgeStatusJudging); err != nil {
+		return nil, err
+	}
+
+	// 清理上一次判题遗留的用例与子任务结果
+	if err := j.db.Where("submit_id = ?", submit.ID).Delete(&models.JudgeCase{}).Error; err != nil {
+		return nil, err
+	}
+	if err := j.db.Where("submit_id = ?", submit.ID).Delete(&models.JudgeSubtask{}).Error; err != nil {
+		return nil, err
+	}
+
+	sess := &session{
+		runner:      runner,
+		command:     lang.RunCommand(memoryLimit),
+		timeLimit:   time.Duration(lang.TimeLimit(timeLimit)) * time.Millisecond,
+		memoryLimit: int64(lang.MemoryLimit(memoryLimit)) << 20,
+	}
+
+	// 输出比较方式，交互题默认由交互程序判定
+	switch {
+	case problem.CheckerType == checker.TypeSpecial:
+		sess.checker, err = j.newSpecialChecker(ctx, &problem, programDir)
+	case !problem.IsInteractive:
+		sess.checker, err = checker.New(&problem)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if problem.IsInteractive {
+		if sess.interactor, err = j.newInteractor(ctx, &problem, programDir); err != nil {
+			return nil, err
+		}
+	}
+
+	groups, err := j.loadGroups(problem.ID, testCases)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &verdict{Status: models.JudgeStatusAccepted, Subtasks: make(map[string]float64)}
+	passed := make(map[string]bool) // 已满分的子任务
+	index := 0
+	for _, g := range groups {
+		groupResult := newGroupResult(g, passed)
+		for _, testCase := range g.cases {
+			index++
+
+			// 依赖未满足或 min 方式的子任务已失败时，其余用例不再评测
+			if groupResult.failed() {
+				groupResult.skip()
+				if err := j.db.Create(skippedCase(submit.ID, testCase)).Error; err != nil {
+					return nil, err
+				}
+				j.publish(submit, &progress.Event{
+					Type:      progress.TypeCase,
+					Status:    models.JudgeStatusSkipped,
+					CaseIndex: index,
+					CaseTotal: len(testCases),
+				})
+				continue
+			}
+
+			input, expected, err := j.loadCaseData(testCase)
+			if err != nil {
+				return nil, err
+			}
+
+			caseResult, err := sess.runCase(ctx, input, expected)
+			if err != nil {
+				return nil, err
+			}
+			run, status := caseResult.run, caseResult.status
+			groupResult.add(testCase, caseResult)
+
+			judgeCase := &models.JudgeCase{
+				SubmitID:       submit.ID,
+				CaseSign:       testCase.CaseSign,
+				InputData:      preview(input),
+				OutputData:     preview(caseResult.output),
+				ExpectedOutput: preview(expected),
+				InputFilePath:  testCase.InputFilePath,
+				InputFileSize:  int64(len(input)),
+				OutputFilePath: testCase.OutputFilePath,
+				OutputFileSize: int64(len(caseResult.output)),
+				MaxTime:        float64(run.Time),
+				MaxMemory:      float64(run.Memory),
+				IsSample:       testCase.IsSample,
+				Score:          testCase.Score * caseResult.ratio,
+				Status:         &status,
+				Message:        preview([]byte(caseResult.message)),
+				ExitCode:       int64(run.ExitCode),
+				SubtaskID:      testCase.SubtaskID,
+			}
+			if err := j.db.Create(judgeCase).Error; err != nil {
+				return nil, err
+			}
+			j.publish(submit, &progress.Event{
+				Type:      progress.TypeCase,
+				Status:    status,
+				CaseIndex: index,
+				CaseTotal: len(testCases),
+				Score:     judgeCase.Score,
+				MaxTime:   int(run.Time),
+				MaxMemory: int(run.Memory),
+			})
+
+			if int(run.Time) > result.MaxTime {
+				result.MaxTime = int(run.Time)
+			}
+			if int(run.Memory) > result.MaxMemory {
+				result.MaxMemory = int(run.Memory)
+			}
+			// 以第一个未通过用例的状态作为最终结果
+			if status != models.JudgeStatusAccepted && result.Status == models.JudgeStatusAccepted {
+				result.Status = status
+			}
+		}
+
+		if g.subtask != nil {
+			passed[g.subtask.ID] = groupResult.passed()
+			result.Subtasks[g.subtask.ID] = groupResult.totalScore()
+			if err := j.db.Create(groupResult.record(submit.ID)).Error; err != nil {
+				return nil, err
+			}
+		}
+		result.Score += groupResult.totalScore()
+	}
+
+	return result, nil
+}
+
+// skippedCase 被跳过用例的判题结果
+func skippedCase(submitID string, testCase *models.ProblemTestCase) *models.JudgeCase {
+	status := models.JudgeStatusSkipped
+	return &models.JudgeCase{
+		SubmitID:       submitID,
+		CaseSign:       testCase.CaseSign,
+		InputFilePath:  testCase.InputFilePath,
+		InputFileSize:  testCase.InputFileSize,
+		OutputFilePath: testCase.OutputFilePath,
+		OutputFileSize: testCase.OutputFileSize,
+		IsSample:       testCase.IsSample,
+		Status:         &status,
+		SubtaskID:      testCase.SubtaskID,
+	}
+}
+
+// session 一次判题中各用例共享的运行环境
+type session struct {
+	runner      *runner
+	interactor  *interactor     // 非交互题为 nil
+	checker     checker.Checker // 交互题未配置特殊判题时为 nil
+	command     []string
+	timeLimit   time.Duration
+	memoryLimit int64
+}
+
+// caseResult 单个用例的判定
+type caseResult struct {
+	run     *runResult
+	output  []byte
+	status  string
+	ratio   float64
+	message string
+}
+
+// runCase 运行单个用例并给出判定
+func (s *session) runCase(ctx context.Context, input, expected []byte) (*caseResult, error) {
+	var result *caseResult
+	if s.interactor != nil {
+		interaction, err := s.interactor.run(ctx, s.runner, s.command, input, expected, s.timeLimit, s.memoryLimit)
+		if err != nil {
+			return nil, err
+		}
+		result = &caseResult{run: interaction.run, output: interaction.output}
+		result.status, result.ratio, result.message = interaction.verdict()
+		// 交互通过后，再由特殊判题程序检查交互程序的输出
+		if result.status != models.JudgeStatusAccepted || s.checker == nil {
+			return result, nil
+		}
+	} else {
+		run, err := s.runner.run(ctx, s.command, input, s.timeLimit, s.memoryLimit)
+		if err != nil {
+			return nil, err
+		}
+		result = &caseResult{run: run, output: run.Stdout, status: run.Status, message: string(run.Stderr)}
+		if result.status != "" {
+			return result, nil
+		}
+	}
+
+	checked, err := s.checker.Check(ctx, input, result.output, expected)
+	if err != nil {
+		return nil, err
+	}
+	result.status = checked.Status
+	result.ratio = checked.Ratio
+	if checked.Message != "" {
+		result.message = checked.Message
+	}
+	return result, nil
+}
+
+// setStatus 更新提交的中间状态
+func (j *Judger) setStatus(submit *models.JudgeSubmit, status string) error {
+	if err := j.db.Model(&models.JudgeSubmit{}).
+		Where("id = ?", submit.ID).
+		Update("status", status).Error; err != nil {
+		return err
+	}
+	j.publish(submit, &progress.Event{Type: progress.TypeStatus, Status: status})
+	return nil
+}
+
+// finish 在一个事务中写入最终判定，并累加题目统计、更新解决记录与用户统计；
+// 重判中的提交不做增量更新，由 finishRejudge 统一重新统计；结果尚未公布的竞赛提交
+// 也不做增量更新，由 settleContests 在结果公布后补计
+func (j *Judger) finish(submit *models.JudgeSubmit, result *verdict) error {
+	// 编译信息与错误信息可能包含 NUL 或非法 UTF-8，无法写入数据库
+	result.Message = cleanText([]byte(result.Message), compileOutputLimit)
+	deferred, err := j.resultHidden(submit)
+	if err != nil {
+		return err
+	}
+	rejudging := submit.TaskID != nil && *submit.TaskID != ""
+	counted := shareProblem.CountedSubmit(submit) && !rejudging && !deferred
+	finished := false
+	err = j.db.Transaction(func(tx *gorm.DB) error {
+		// 先锁定统计行再写入结果，保证并发判题时通过人数的判断基于已提交的结果
+		var stats *models.ProblemStats
+		if counted {
+			var err error
+			if stats, err = shareProblem.LockStats(tx, *submit.ProblemID); err != nil {
+				return err
+			}
+		}
+		// 只更新未完成的提交，同一提交被重复判题时不会重复计数
+		updated := tx.Model(&models.JudgeSubmit{}).
+			Where("id = ? AND is_finish = ?", submit.ID, false).
+			Updates(map[string]interface{}{
+				"status":     result.Status,
+				"message":    result.Message,
+				"max_time":   result.MaxTime,
+				"max_memory": result.MaxMemory,
+				"score":      result.Score,
+				"is_finish":  true,
+			})
+		if updated.Error != nil {
+			return updated.Error
+		}
+		if finished = updated.RowsAffected > 0; !finished {
+			return nil
+		}
+		if counted {
+			if err := shareProblem.RecordVerdict(tx, stats, submit, result.Status); err != nil {
+				return err
+			}
+		}
+		if err := j.records.ArchiveSubmit(tx, submit, result.Status, j.sourceFile(submit)); err != nil {
+			return err
+		}
+		if rejudging || deferred {
+			return nil
+		}
+		return j.records.RecordSubmit(tx, submit, result.Status)
+	})
+	if err != nil {
+		return err
+	}
+	if !finished {
+		return nil
+	}
+	// 榜单缓存失败只影响展示，缓存缺失时会由数据库重建
+	if err := shareContest.RecordFinished(submit, result.Status, result.Score, result.Subtasks); err != nil {
+		logger.Warn().Str("submit_id", submit.ID).Err(err).Msg("Update scoreboard failed")
+	}
+	j.publish(submit, &progress.Event{
+		Type:      progress.TypeFinish,
+		Status:    result.Status,
+		Score:     result.Score,
+		MaxTime:   result.MaxTime,
+		MaxMemory: result.MaxMemory,
+		Message:   result.Message,
+	})
+	return nil
+}
+
+// resultHidden 竞赛提交的结果当前是否对公众隐藏：OI 赛制比赛结束前，ICPC 赛制封榜后的提交
+func (j *Judger) resultHidden(submit *models.JudgeSubmit) (bool, error) {
+	if submit.ModuleType == nil || *submit.ModuleType != models.ModuleTypeContest || submit.ModuleID == nil {
+		return false, nil
+	}
+	contest, err := shareContest.FindContest(j.db, *submit.ModuleID)
+	if errors.Is(err, shareContest.ErrContestNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return shareContest.ResultHidden(contest, submit, "", time.Now()), nil
+}
+
+// sourceFile 提交语言的源文件名，语言已下线时为空
+func (j *Judger) sourceFile(submit *models.JudgeSubmit) string {
+	if submit.Language == nil {
+		return ""
+	}
+	if lang, ok := j.languages.Get(*submit.Language); ok {
+		return lang.SourceFile
+	}
+	return ""
+}
+
+// publish 发布判题进度，失败只记录日志，不影响判题
+func (j *Judger) publish(submit *models.JudgeSubmit, event *progress.Event) {
+	event.SubmitID = submit.ID
+	if submit.UserID != nil {
+		event.UserID = *submit.UserID
+	}
+	if err := progress.Publish(event); err != nil {
+		logger.Warn().Str("submit_id", submit.ID).Err(err).Msg("Publish judge progress failed")
+	}
+}
+
+// loadCaseData 读取用例的输入与期望输出，文件优先于内联数据
+func (j *Judger) loadCaseData(testCase *models.ProblemTestCase) ([]byte, []byte, error) {
+	input, err := j.readCaseField(testCase.InputFilePath, testCase.InputFileHash, testCase.InputData)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected, err := j.readCaseField(testCase.OutputFilePath, testCase.OutputFileHash, testCase.ExpectedOutput)
+	if err != nil {
+		return nil, nil, err
+	}
+	return input, expected, nil
+}
+
+func (j *Judger) readCaseField(path, hash, data *string) ([]byte, error) {
+	if path != nil && *path != "" {
+		return j.data.Read(*path, hash)
+	}
+	if data != nil {
+		return []byte(*data), nil
+	}
+	return nil, nil
+}
+
+// preview 截取数据前缀用于展示
+func preview(data []byte) *string {
+	s := cleanText(data, casePreviewSize)
+	return &s
+}
+
+// cleanText 转换为可写入数据库的文本：按字符边界截取不超过 limit 字节，
+// 去除 NUL 并替换非法的 UTF-8 序列
+func cleanText(data []byte, limit int) string {
+	if len(data) > limit {
+		data = data[:limit]
+		// 截断处的多字节字符不完整时整个丢弃
+		for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					data = data[:i]
+				}
+				break
+			}
+		}
+	}
+	s := strings.ReplaceAll(string(data), "\x00", "")
+	return strings.ToValidUTF8(s, "\uFFFD")
+}
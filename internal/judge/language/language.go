@@ -0,0 +1,249 @@
+package language
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"galaxy/internal/models"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// versionProbeTimeout 版本探测超时
+const versionProbeTimeout = 5 * time.Second
+
+// Language 判题语言
+type Language struct {
+	config.LanguageConfig
+	VersionText string `json:"version_text"` // 版本探测结果
+}
+
+// NeedCompile 是否需要编译
+func (l *Language) NeedCompile() bool {
+	return len(l.Compile) > 0
+}
+
+// TimeLimit 按语言倍数换算时间限制（毫秒）
+func (l *Language) TimeLimit(base int) int {
+	if l.TimeFactor <= 0 {
+		return base
+	}
+	return int(float64(base) * l.TimeFactor)
+}
+
+// MemoryLimit 按语言倍数换算内存限制（MB）
+func (l *Language) MemoryLimit(base int) int {
+	if l.MemoryFactor <= 0 {
+		return base
+	}
+	return int(float64(base) * l.MemoryFactor)
+}
+
+// CompileCommand 编译命令
+func (l *Language) CompileCommand(memoryLimit int) []string {
+	return expand(l.Compile, memoryLimit)
+}
+
+// RunCommand 运行命令，memoryLimit 为题目原始内存限制（MB）
+func (l *Language) RunCommand(memoryLimit int) []string {
+	return expand(l.Run, memoryLimit)
+}
+
+// expand 替换命令中的占位符
+func expand(args []string, memoryLimit int) []string {
+	result := make([]string, len(args))
+	for i, arg := range args {
+		result[i] = strings.ReplaceAll(arg, "{memory}", strconv.Itoa(memoryLimit))
+	}
+	return result
+}
+
+// Registry 语言注册表
+type Registry struct {
+	mu        sync.RWMutex
+	languages map[string]*Language
+}
+
+var (
+	instance *Registry
+	once     sync.Once
+)
+
+// Get 获取全局语言注册表，首次调用时从配置文件与配置分组加载
+func Get() *Registry {
+	once.Do(func() {
+		instance = NewRegistry()
+		if err := instance.Reload(); err != nil {
+			logger.Error().Err(err).Msg("Load judge languages failed")
+		}
+	})
+	return instance
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		languages: make(map[string]*Language),
+	}
+}
+
+// Reload 重新加载语言：先读配置文件，再用配置分组中的同名项覆盖
+func (r *Registry) Reload() error {
+	cfg := config.Get().Judge
+
+	languages := make(map[string]*Language)
+	for _, lang := range cfg.Languages {
+		languages[lang.Name] = &Language{LanguageConfig: lang}
+	}
+
+	var err error
+	if cfg.LanguageGroup != "" {
+		err = loadConfigGroup(database.GetDB(), cfg.LanguageGroup, languages)
+	}
+
+	r.mu.Lock()
+	r.languages = languages
+	r.mu.Unlock()
+	return err
+}
+
+// loadConfigGroup 读取配置分组，配置项 Name 为语言名称，Value 为 LanguageConfig 的 JSON
+func loadConfigGroup(db *gorm.DB, groupCode string, languages map[string]*Language) error {
+	var group models.ConfigGroup
+	if err := db.Where("code = ?", groupCode).First(&group).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var items []models.ConfigItem
+	if err := db.Where("group_id = ?", group.ID).Order("sort ASC").Find(&items).Error; err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		var lang config.LanguageConfig
+		if err := json.Unmarshal([]byte(item.Value), &lang); err != nil {
+			return fmt.Errorf("语言配置 %s 格式错误: %w", item.Code, err)
+		}
+		if lang.Name == "" {
+			lang.Name = item.Name
+		}
+		languages[lang.Name] = &Language{LanguageConfig: lang}
+	}
+	return nil
+}
+
+// Get 按名称获取语言
+func (r *Registry) Get(name string) (*Language, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	lang, ok := r.languages[name]
+	return lang, ok
+}
+
+// List 所有语言，按名称排序
+func (r *Registry) List() []*Language {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*Language, 0, len(r.languages))
+	for _, lang := range r.languages {
+		list = append(list, lang)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Validate 校验语言已注册，且在每个非空的允许列表中（题目、竞赛等）
+func (r *Registry) Validate(name string, allowed ...datatypes.JSON) error {
+	if _, ok := r.Get(name); !ok {
+		return fmt.Errorf("不支持的语言: %s", name)
+	}
+
+	for _, list := range allowed {
+		names, err := ParseList(list)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			continue
+		}
+		if !contains(names, name) {
+			return fmt.Errorf("不允许使用的语言: %s", name)
+		}
+	}
+	return nil
+}
+
+// Probe 执行各语言的版本探测命令。判题协程可能同时持有注册表中的 *Language，
+// 因此不修改已有的值，而是复制出新值后整体替换注册表
+func (r *Registry) Probe(ctx context.Context) {
+	probed := make(map[*Language]string)
+	for _, lang := range r.List() {
+		if len(lang.Version) == 0 {
+			continue
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, versionProbeTimeout)
+		output, err := exec.CommandContext(probeCtx, lang.Version[0], lang.Version[1:]...).CombinedOutput()
+		cancel()
+
+		if err != nil {
+			probed[lang] = ""
+			logger.Warn().Str("language", lang.Name).Err(err).Msg("Language unavailable")
+			continue
+		}
+		// 只保留第一行，如 "gcc (GCC) 13.2.0"
+		probed[lang] = strings.TrimSpace(strings.SplitN(string(output), "\n", 2)[0])
+		logger.Info().Str("language", lang.Name).Str("version", probed[lang]).Msg("Language available")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	languages := make(map[string]*Language, len(r.languages))
+	for name, lang := range r.languages {
+		// 探测期间重新加载过的语言保留新值
+		if version, ok := probed[lang]; ok {
+			updated := *lang
+			updated.VersionText = version
+			lang = &updated
+		}
+		languages[name] = lang
+	}
+	r.languages = languages
+}
+
+// ParseList 解析 JSON 数组形式的语言列表，空值表示不限制
+func ParseList(data datatypes.JSON) ([]string, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("语言列表格式错误: %w", err)
+	}
+	return names, nil
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Package progress 通过 Redis 发布订阅传递判题进度，判题端发布，Web 端订阅后推送给前端
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"galaxy/internal/models"
+	"galaxy/pkg/redis"
+)
+
+const channelPrefix = "judge:progress:"
+
+// 事件类型
+const (
+	TypeStatus = "status" // 提交状态变化：编译中、判题中
+	TypeCase   = "case"   // 一个用例判定完成
+	TypeFinish = "finish" // 最终判定
+)
+
+// Event 判题进度事件
+type Event struct {
+	Type      string  `json:"type"`
+	SubmitID  string  `json:"submit_id"`
+	UserID    string  `json:"user_id"`
+	Status    string  `json:"status"`               // 提交当前状态，case 事件中为该用例状态
+	CaseIndex int     `json:"case_index,omitempty"` // 用例序号，从 1 开始
+	CaseTotal int     `json:"case_total,omitempty"`
+	Score     float64 `json:"score"`
+	MaxTime   int     `json:"max_time"`   // 毫秒
+	MaxMemory int     `json:"max_memory"` // KB
+	Message   string  `json:"message,omitempty"`
+}
+
+// Redact 隐藏判题结果后的事件，只保留提交信息与判题阶段，最终判定显示为已提交；用例事件返回 nil
+func (e *Event) Redact() *Event {
+	if e.Type == TypeCase {
+		return nil
+	}
+	redacted := &Event{Type: e.Type, SubmitID: e.SubmitID, UserID: e.UserID, Status: e.Status}
+	if e.Type == TypeFinish {
+		redacted.Status = models.JudgeStatusSubmitted
+	}
+	return redacted
+}
+
+// StripMessage 去掉编译信息后的事件，编译信息只对提交者本人可见
+func (e *Event) StripMessage() *Event {
+	if e.Message == "" {
+		return e
+	}
+	stripped := *e
+	stripped.Message = ""
+	return &stripped
+}
+
+// SubmissionChannel 单个提交的进度频道
+func SubmissionChannel(submitID string) string {
+	return channelPrefix + "submit:" + submitID
+}
+
+// UserChannel 用户全部提交的进度频道
+func UserChannel(userID string) string {
+	return channelPrefix + "user:" + userID
+}
+
+// Publish 将事件同时发布到提交频道与用户频道
+func Publish(event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := redis.Publish(SubmissionChannel(event.SubmitID), data); err != nil {
+		return err
+	}
+	if event.UserID == "" {
+		return nil
+	}
+	return redis.Publish(UserChannel(event.UserID), data)
+}
+
+// Subscription 进度订阅
+type Subscription struct {
+	Events <-chan *Event
+	close  func() error
+}
+
+// Subscribe 订阅频道，返回前已确认订阅生效，调用方需 Close
+func Subscribe(ctx context.Context, channel string) (*Subscription, error) {
+	pubsub := redis.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	events := make(chan *Event)
+	go func() {
+		defer close(events)
+		for message := range pubsub.Channel() {
+			var event Event
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				continue
+			}
+			select {
+			case events <- &event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &Subscription{Events: events, close: pubsub.Close}, nil
+}
+
+// Close 取消订阅
+func (s *Subscription) Close() error {
+	return s.close()
+}
@@ -0,0 +1,102 @@
+package judge
+
+import (
+	"galaxy/internal/models"
+	shareContest "galaxy/internal/service/share/contest"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/internal/service/share/record"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/redis"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	rejudgeDoneKeyPrefix = "rejudge:done:"    // 重判任务收尾标记，防止多个判题协程重复收尾
+	rejudgeDoneTTL       = 7 * 24 * time.Hour // 与重判任务保留时间一致
+)
+
+// finishRejudge 重判任务的最后一个提交完成后，重新计算受影响的解决记录、用户统计与题目统计；
+// 重判期间不做增量统计
+func (j *Judger) finishRejudge(taskID string) error {
+	var pending int64
+	if err := j.db.Model(&models.JudgeSubmit{}).
+		Where("task_id = ? AND is_finish = ?", taskID, false).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return nil
+	}
+
+	ok, err := redis.SetNX(rejudgeDoneKeyPrefix+taskID, 1, rejudgeDoneTTL)
+	if err != nil || !ok {
+		return err
+	}
+
+	count, err := recompute(j.db, j.records, func() *gorm.DB {
+		return j.db.Model(&models.JudgeSubmit{}).Where("task_id = ?", taskID)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Service("Judge").Str("task_id", taskID).Int("records", count).Msg("Rejudge finished")
+	return nil
+}
+
+// recompute 根据提交历史重新计算 submits 范围内提交涉及的解决记录、用户统计与题目统计，
+// 并重建相关竞赛的榜单，返回重新计算的解决记录数
+func recompute(db *gorm.DB, records record.RecordService, submits func() *gorm.DB) (int, error) {
+	var rows []struct {
+		ModuleType string
+		ModuleID   string
+		UserID     string
+		ProblemID  string
+	}
+	if err := submits().
+		Distinct("module_type", "module_id", "user_id", "problem_id").
+		Where("module_type IS NOT NULL AND module_id IS NOT NULL AND user_id IS NOT NULL AND problem_id IS NOT NULL").
+		Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+
+	users := make(map[string]bool)
+	contests := make(map[string]bool)
+	for _, row := range rows {
+		if err := records.RecomputeSolved(row.ModuleType, row.ModuleID, row.UserID, row.ProblemID); err != nil {
+			return 0, err
+		}
+		users[row.UserID] = true
+		if row.ModuleType == models.ModuleTypeContest {
+			contests[row.ModuleID] = true
+		}
+	}
+	// 榜单按判题结果逐条更新，重新统计后整体重建，保证与数据库一致
+	for contestID := range contests {
+		if err := shareContest.InvalidateScoreboard(contestID); err != nil {
+			logger.Warn().Str("contest_id", contestID).Err(err).Msg("Invalidate scoreboard failed")
+		}
+	}
+	for userID := range users {
+		if err := records.RecomputeUserStats(userID); err != nil {
+			return 0, err
+		}
+	}
+
+	var problemIDs []string
+	if err := submits().
+		Distinct("problem_id").
+		Where("problem_id IS NOT NULL").
+		Pluck("problem_id", &problemIDs).Error; err != nil {
+		return 0, err
+	}
+	for _, problemID := range problemIDs {
+		if err := shareProblem.RecomputeStats(db, problemID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(rows), nil
+}
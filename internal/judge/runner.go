@@ -0,0 +1,227 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"galaxy/internal/models"
+	"galaxy/pkg/config"
+	"galaxy/pkg/sandbox"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	compileMemoryLimit  = 2 << 30  // 编译内存上限（字节）
+	compileProcessLimit = 256      // 编译进程/线程数上限
+	compileOutputLimit  = 64 << 10 // 编译信息保留上限（字节）
+	stderrLimit         = 64 << 10 // 运行时标准错误保留上限（字节）
+)
+
+// runResult 单次运行结果
+type runResult struct {
+	Status   string // 为空表示正常退出
+	Time     int64  // CPU 时间（毫秒）
+	Memory   int64  // 峰值内存（KB）
+	ExitCode int
+	Stdout   []byte
+	Stderr   []byte
+}
+
+// limitedBuffer 超出上限后丢弃写入的缓冲区
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remain := b.limit - b.buf.Len(); remain < len(p) {
+		b.exceeded = true
+		if remain > 0 {
+			b.buf.Write(p[:remain])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// runner 在沙箱中编译、运行一次提交
+type runner struct {
+	dir string
+	cfg config.JudgeConfig
+	env []string
+	uid int // 运行用户，0 表示以判题进程的用户运行
+	gid int
+}
+
+// newRunner 运行选手程序，工作目录交给运行用户
+func newRunner(dir string, cfg config.JudgeConfig, env []string) (*runner, error) {
+	// 编译产物需要由运行用户写入工作目录
+	if cfg.RunUID > 0 && os.Geteuid() == 0 {
+		if err := os.Chown(dir, cfg.RunUID, cfg.RunGID); err != nil {
+			return nil, err
+		}
+	}
+	return &runner{dir: dir, cfg: cfg, env: env, uid: cfg.RunUID, gid: cfg.RunGID}, nil
+}
+
+// newProgramRunner 运行题目附带的程序（特殊判题、交互器），以判题进程的用户运行，
+// 工作目录不交给选手程序的运行用户
+func newProgramRunner(dir string, cfg config.JudgeConfig, env []string) *runner {
+	return &runner{dir: dir, cfg: cfg, env: env}
+}
+
+// compile 执行编译命令，返回是否成功与编译输出
+func (r *runner) compile(ctx context.Context, args []string) (bool, string, error) {
+	timeout := time.Duration(r.cfg.CompileTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	output := &limitedBuffer{limit: compileOutputLimit}
+	result, err := sandbox.Run(ctx, &sandbox.Config{
+		Args:           args,
+		Dir:            r.dir,
+		Env:            r.environ("HOME=" + r.dir),
+		Stdout:         output,
+		Stderr:         output,
+		TimeLimit:      timeout,
+		WallTimeLimit:  timeout,
+		MemoryLimit:    compileMemoryLimit,
+		ProcessLimit:   compileProcessLimit,
+		DisableNetwork: true,
+		UID:            r.uid,
+		GID:            r.gid,
+	})
+	if err != nil {
+		return false, "", err
+	}
+
+	switch result.Status {
+	case sandbox.StatusOK:
+		return true, output.buf.String(), nil
+	case sandbox.StatusTimeLimitExceeded:
+		return false, "编译超时", nil
+	case sandbox.StatusMemoryLimitExceeded:
+		return false, "编译内存超限", nil
+	default:
+		return false, output.buf.String(), nil
+	}
+}
+
+// run 以 input 为标准输入运行程序。工作目录归运行用户所有，选手程序可以在其中放置符号链接，
+// 因此标准输入输出使用判题进程目录下的匿名文件，只以文件描述符交给沙箱
+func (r *runner) run(ctx context.Context, args []string, input []byte, timeLimit time.Duration, memoryLimit int64) (*runResult, error) {
+	stdin, err := r.tempFile()
+	if err != nil {
+		return nil, err
+	}
+	defer stdin.Close()
+	if _, err := stdin.Write(input); err != nil {
+		return nil, err
+	}
+	if _, err := stdin.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	stdout, err := r.tempFile()
+	if err != nil {
+		return nil, err
+	}
+	defer stdout.Close()
+
+	stderr := &limitedBuffer{limit: stderrLimit}
+	cfg := r.sandboxConfig(args, timeLimit, memoryLimit)
+	cfg.Stdin = stdin
+	cfg.Stdout = stdout
+	cfg.Stderr = stderr
+
+	result, err := sandbox.Run(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := stdout.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	output, err := io.ReadAll(stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRunResult(result, output, stderr.buf.Bytes()), nil
+}
+
+// tempFile 在工作目录的上级目录（判题进程所有）中以 O_EXCL 创建文件并立即删除路径
+func (r *runner) tempFile() (*os.File, error) {
+	file, err := os.CreateTemp(filepath.Dir(r.dir), ".stdio-*")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(file.Name()); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// sandboxConfig 运行提交程序的沙箱配置，标准输入输出由调用方设置
+func (r *runner) sandboxConfig(args []string, timeLimit time.Duration, memoryLimit int64) *sandbox.Config {
+	return &sandbox.Config{
+		Args:           args,
+		Dir:            r.dir,
+		Env:            r.environ(),
+		TimeLimit:      timeLimit,
+		MemoryLimit:    memoryLimit,
+		OutputLimit:    r.outputLimit(),
+		ProcessLimit:   r.cfg.ProcessLimit,
+		DisableNetwork: true,
+		Seccomp:        true,
+		UID:            r.uid,
+		GID:            r.gid,
+	}
+}
+
+func newRunResult(result *sandbox.Result, stdout, stderr []byte) *runResult {
+	return &runResult{
+		Status:   runStatus(result.Status),
+		Time:     result.CPUTime.Milliseconds(),
+		Memory:   result.Memory >> 10,
+		ExitCode: result.ExitCode,
+		Stdout:   stdout,
+		Stderr:   stderr,
+	}
+}
+
+// environ 沙箱内的环境变量
+func (r *runner) environ(extra ...string) []string {
+	env := []string{"PATH=" + os.Getenv("PATH")}
+	env = append(env, extra...)
+	return append(env, r.env...)
+}
+
+// outputLimit 单个用例输出上限（字节）
+func (r *runner) outputLimit() int64 {
+	if r.cfg.OutputLimit <= 0 {
+		return 64 << 20
+	}
+	return int64(r.cfg.OutputLimit) << 10
+}
+
+// runStatus 将沙箱状态映射为判题状态
+func runStatus(status sandbox.Status) string {
+	switch status {
+	case sandbox.StatusTimeLimitExceeded:
+		return models.JudgeStatusTimeLimitExceeded
+	case sandbox.StatusMemoryLimitExceeded:
+		return models.JudgeStatusMemoryLimitExceeded
+	case sandbox.StatusOutputLimitExceeded:
+		return models.JudgeStatusOutputLimitExceeded
+	case sandbox.StatusRuntimeError:
+		return models.JudgeStatusRuntimeError
+	default:
+		return ""
+	}
+}
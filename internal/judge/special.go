@@ -0,0 +1,103 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"galaxy/internal/judge/checker"
+	"galaxy/internal/models"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultCheckerLanguage = "C++17"          // 特殊判题程序默认语言
+	checkerTimeLimit       = 10 * time.Second // 特殊判题程序运行时间上限
+	checkerMemoryLimit     = 1 << 30          // 特殊判题程序内存上限（字节）
+)
+
+// specialChecker 运行题目提供的 testlib 兼容判题程序：checker <input> <output> <answer>
+type specialChecker struct {
+	dir     string
+	runner  *runner
+	command []string
+}
+
+// newSpecialChecker 在 programDir 下的独立目录中编译特殊判题程序
+func (j *Judger) newSpecialChecker(ctx context.Context, problem *models.ProblemInfo, programDir string) (checker.Checker, error) {
+	runner, command, err := j.prepareProgram(ctx, filepath.Join(programDir, "checker"), problem.CheckerCode, problem.CheckerLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("特殊判题程序: %w", err)
+	}
+	return &specialChecker{dir: runner.dir, runner: runner, command: command}, nil
+}
+
+// prepareProgram 写入并编译题目附带的程序（特殊判题、交互器），返回运行命令。
+// 目录只有判题进程的用户可以访问，选手程序无法读取答案或替换程序
+func (j *Judger) prepareProgram(ctx context.Context, dir string, code, langName *string) (*runner, []string, error) {
+	if code == nil || *code == "" {
+		return nil, nil, errors.New("源码为空")
+	}
+	name := defaultCheckerLanguage
+	if langName != nil && *langName != "" {
+		name = *langName
+	}
+	lang, ok := j.languages.Get(name)
+	if !ok {
+		return nil, nil, fmt.Errorf("不支持的语言: %s", name)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, lang.SourceFile), []byte(*code), 0o600); err != nil {
+		return nil, nil, err
+	}
+	if j.cfg.TestlibPath != "" {
+		testlib, err := os.ReadFile(j.cfg.TestlibPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "testlib.h"), testlib, 0o600); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	runner := newProgramRunner(dir, j.cfg, lang.Env)
+
+	memoryLimit := int(checkerMemoryLimit >> 20)
+	if lang.NeedCompile() {
+		success, output, err := runner.compile(ctx, lang.CompileCommand(memoryLimit))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !success {
+			return nil, nil, fmt.Errorf("编译失败: %s", output)
+		}
+	}
+	return runner, lang.RunCommand(memoryLimit), nil
+}
+
+func (c *specialChecker) Check(ctx context.Context, input, output, answer []byte) (*checker.Result, error) {
+	files := map[string][]byte{
+		"checker_input.txt":  input,
+		"checker_output.txt": output,
+		"checker_answer.txt": answer,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(c.dir, name), data, 0o600); err != nil {
+			return nil, err
+		}
+	}
+
+	args := append(append([]string{}, c.command...), "checker_input.txt", "checker_output.txt", "checker_answer.txt")
+	run, err := c.runner.run(ctx, args, nil, checkerTimeLimit, checkerMemoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != "" && run.Status != models.JudgeStatusRuntimeError {
+		return nil, fmt.Errorf("特殊判题程序运行异常: %s", run.Status)
+	}
+	return checker.ParseTestlibResult(run.ExitCode, string(run.Stderr))
+}
@@ -0,0 +1,178 @@
+package judge
+
+import (
+	"encoding/json"
+	"fmt"
+	"galaxy/internal/models"
+)
+
+// fullScore 未配置任何分值时的默认总分
+const fullScore = 100
+
+// group 一组按相同规则计分的用例，对应一个子任务；未分组的用例归入 subtask 为 nil 的组
+type group struct {
+	subtask      *models.ProblemSubtask
+	aggregation  string
+	dependencies []string
+	cases        []*models.ProblemTestCase
+}
+
+// fullScore 组的满分
+func (g *group) fullScore() float64 {
+	if g.aggregation == models.SubtaskAggregationMin && g.subtask.Score > 0 {
+		return g.subtask.Score
+	}
+	var score float64
+	for _, testCase := range g.cases {
+		score += testCase.Score
+	}
+	return score
+}
+
+// loadGroups 按子任务对用例分组：未分组用例在前，子任务按 sort 排列，组内保持用例原有顺序
+func (j *Judger) loadGroups(problemID string, testCases []models.ProblemTestCase) ([]*group, error) {
+	var subtasks []models.ProblemSubtask
+	if err := j.db.Where("problem_id = ?", problemID).
+		Order("sort ASC, create_time ASC").
+		Find(&subtasks).Error; err != nil {
+		return nil, err
+	}
+
+	ungrouped := &group{aggregation: models.SubtaskAggregationSum}
+	groups := []*group{ungrouped}
+	byID := make(map[string]*group, len(subtasks))
+	for i := range subtasks {
+		subtask := &subtasks[i]
+		g := &group{subtask: subtask, aggregation: subtask.Aggregation}
+		if g.aggregation != models.SubtaskAggregationSum {
+			g.aggregation = models.SubtaskAggregationMin
+		}
+		if len(subtask.Dependencies) > 0 && string(subtask.Dependencies) != "null" {
+			if err := json.Unmarshal(subtask.Dependencies, &g.dependencies); err != nil {
+				return nil, fmt.Errorf("子任务 %s 依赖配置错误: %w", subtask.ID, err)
+			}
+		}
+		groups = append(groups, g)
+		byID[subtask.ID] = g
+	}
+
+	for i := range testCases {
+		testCase := &testCases[i]
+		g := ungrouped
+		if testCase.SubtaskID != nil {
+			if owner, ok := byID[*testCase.SubtaskID]; ok {
+				g = owner
+			}
+		}
+		g.cases = append(g.cases, testCase)
+	}
+
+	// 去掉没有用例的组
+	result := groups[:0]
+	var total float64
+	for _, g := range groups {
+		if len(g.cases) > 0 {
+			result = append(result, g)
+			total += g.fullScore()
+		}
+	}
+
+	// 题目未配置分值时，各用例平分默认总分
+	if total == 0 {
+		score := float64(fullScore) / float64(len(testCases))
+		for i := range testCases {
+			testCases[i].Score = score
+		}
+	}
+	return result, nil
+}
+
+// groupResult 一组用例的评测结果
+type groupResult struct {
+	group     *group
+	status    string  // 第一个未通过用例的状态
+	minRatio  float64 // min 方式下各用例得分比例的最小值
+	score     float64 // sum 方式下各用例得分之和
+	skipped   bool    // 依赖未满足，整组未评测
+	maxTime   int
+	maxMemory int
+}
+
+func newGroupResult(g *group, passed map[string]bool) *groupResult {
+	r := &groupResult{group: g, status: models.JudgeStatusAccepted, minRatio: 1}
+	for _, dep := range g.dependencies {
+		// 依赖只能指向排在前面的子任务，未评测或未满分均视为不满足
+		if !passed[dep] {
+			r.skipped = true
+			r.status = models.JudgeStatusSkipped
+			r.minRatio = 0
+			break
+		}
+	}
+	return r
+}
+
+// failed 组内是否已无需继续评测：依赖未满足，或 min 方式下已有用例得 0 分
+func (r *groupResult) failed() bool {
+	return r.skipped || (r.group.aggregation == models.SubtaskAggregationMin && r.minRatio == 0)
+}
+
+// add 记录一个已评测用例
+func (r *groupResult) add(testCase *models.ProblemTestCase, result *caseResult) {
+	if result.status != models.JudgeStatusAccepted && r.status == models.JudgeStatusAccepted {
+		r.status = result.status
+	}
+	if result.ratio < r.minRatio {
+		r.minRatio = result.ratio
+	}
+	r.score += testCase.Score * result.ratio
+	if int(result.run.Time) > r.maxTime {
+		r.maxTime = int(result.run.Time)
+	}
+	if int(result.run.Memory) > r.maxMemory {
+		r.maxMemory = int(result.run.Memory)
+	}
+}
+
+// skip 记录一个被跳过的用例
+func (r *groupResult) skip() {
+	r.minRatio = 0
+	if r.status == models.JudgeStatusAccepted {
+		r.status = models.JudgeStatusSkipped
+	}
+}
+
+// passed 整组是否满分
+func (r *groupResult) passed() bool {
+	return r.status == models.JudgeStatusAccepted
+}
+
+// totalScore 组得分
+func (r *groupResult) totalScore() float64 {
+	if r.skipped {
+		return 0
+	}
+	if r.group.aggregation == models.SubtaskAggregationMin {
+		return r.group.fullScore() * r.minRatio
+	}
+	return r.score
+}
+
+// record 子任务结果，未分组的用例不产生记录
+func (r *groupResult) record(submitID string) *models.JudgeSubtask {
+	if r.group.subtask == nil {
+		return nil
+	}
+	status := r.status
+	return &models.JudgeSubtask{
+		SubmitID:  submitID,
+		SubtaskID: r.group.subtask.ID,
+		Title:     r.group.subtask.Title,
+		Sort:      r.group.subtask.Sort,
+		Score:     r.totalScore(),
+		FullScore: r.group.fullScore(),
+		Status:    &status,
+		MaxTime:   r.maxTime,
+		MaxMemory: r.maxMemory,
+	}
+}
@@ -0,0 +1,82 @@
+package template
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/datatypes"
+)
+
+// ErrNoTemplate 题目没有配置该语言的代码模板
+var ErrNoTemplate = errors.New("该题目未提供此语言的代码模板")
+
+// Template 单个语言的代码模板：用户只编辑 Template 部分，提交后与 Prepend、Append 拼接编译
+type Template struct {
+	Prepend  string `json:"prepend" yaml:"prepend,omitempty"`   // 用户不可见的前置代码，如头文件与辅助类型
+	Template string `json:"template" yaml:"template,omitempty"` // 用户可编辑部分的初始代码，如函数签名
+	Append   string `json:"append" yaml:"append,omitempty"`     // 用户不可见的后置代码，如 main 函数
+}
+
+// Splice 将用户代码放入模板，各部分之间以换行分隔
+func (t *Template) Splice(code string) string {
+	var builder strings.Builder
+	for _, part := range []string{t.Prepend, code, t.Append} {
+		if part == "" {
+			continue
+		}
+		if builder.Len() > 0 && !strings.HasSuffix(builder.String(), "\n") {
+			builder.WriteByte('\n')
+		}
+		builder.WriteString(part)
+	}
+	return builder.String()
+}
+
+// Parse 解析 ProblemInfo.CodeTemplate，格式为语言名到模板的映射，空值返回 nil
+func Parse(data datatypes.JSON) (map[string]Template, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var templates map[string]Template
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("代码模板格式错误: %w", err)
+	}
+	return templates, nil
+}
+
+// Lookup 查找语言的模板，启用模板的题目只能使用配置了模板的语言，未配置时返回 ErrNoTemplate
+func Lookup(data datatypes.JSON, language string) (*Template, error) {
+	templates, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := templates[language]
+	if !ok {
+		return nil, ErrNoTemplate
+	}
+	return &t, nil
+}
+
+// Splice 按题目模板拼接提交代码，未配置该语言模板时返回 ErrNoTemplate
+func Splice(data datatypes.JSON, language, code string) (string, error) {
+	t, err := Lookup(data, language)
+	if err != nil {
+		return "", err
+	}
+	return t.Splice(code), nil
+}
+
+// Editable 各语言的可编辑部分，用于向用户展示
+func Editable(data datatypes.JSON) (map[string]string, error) {
+	templates, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	editable := make(map[string]string, len(templates))
+	for name, t := range templates {
+		editable[name] = t.Template
+	}
+	return editable, nil
+}
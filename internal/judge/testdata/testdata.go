@@ -0,0 +1,111 @@
+// Package testdata 测试数据文件的存储与判题端缓存
+//
+// 管理端将用例文件写入数据根目录，ProblemTestCase 记录相对路径与 SHA-256；
+// 判题端按哈希把文件缓存到本地，文件内容变化后哈希随之变化，只会重新同步变化的文件。
+package testdata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrTooLarge 写入的数据超过上限
+var ErrTooLarge = errors.New("测试数据超过大小上限")
+
+// Path 用例文件的实际路径，相对路径基于数据根目录
+func Path(root, path string) string {
+	if filepath.IsAbs(path) || root == "" {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// Save 将 r 写入 path，返回写入的字节数与 SHA-256；limit 为可写入的最大字节数。
+// 目录与文件只对属主开放，沙箱中以其他用户运行的选手程序无法读取测试数据
+func Save(path string, r io.Reader, limit int64) (int64, string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return 0, "", err
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	// 多读 1 字节用于判断是否超限
+	size, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(r, limit+1))
+	if err != nil {
+		return 0, "", err
+	}
+	if size > limit {
+		return 0, "", ErrTooLarge
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), file.Close()
+}
+
+// Hash 计算数据的 SHA-256
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Cache 判题端的测试数据缓存，按 SHA-256 存放文件
+type Cache struct {
+	root string
+	dir  string
+}
+
+// NewCache dir 为空时不缓存，直接读取数据根目录
+func NewCache(root, dir string) *Cache {
+	return &Cache{root: root, dir: dir}
+}
+
+// Read 读取用例文件；有哈希时优先读取缓存，缓存缺失则从数据根目录同步并校验
+func (c *Cache) Read(path string, hash *string) ([]byte, error) {
+	source := Path(c.root, path)
+	if c.dir == "" || hash == nil || len(*hash) < 2 {
+		return os.ReadFile(source)
+	}
+
+	cached := filepath.Join(c.dir, (*hash)[:2], *hash)
+	if data, err := os.ReadFile(cached); err == nil {
+		return data, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return nil, err
+	}
+	if Hash(data) != *hash {
+		return nil, fmt.Errorf("测试数据 %s 校验失败，请重新上传", path)
+	}
+
+	// 先写临时文件再改名，避免并发判题读到不完整的缓存；与数据目录一样只对属主开放
+	if err := os.MkdirAll(filepath.Dir(cached), 0o700); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(cached), *hash+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), cached); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
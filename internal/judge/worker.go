@@ -0,0 +1,130 @@
+package judge
+
+import (
+	"context"
+	"fmt"
+	"galaxy/pkg/config"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/redis"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// pollTimeout 出队等待时间，用于周期性检查退出信号
+	pollTimeout = 5 * time.Second
+	// maxJudgeRetries 判题出错（如写回结果失败）后重新入队的最大次数
+	maxJudgeRetries = 3
+	// judgeRetryTTL 重试计数的有效期
+	judgeRetryTTL = time.Hour
+)
+
+// Worker 判题队列消费者
+type Worker struct {
+	id     int
+	queue  string
+	judger *Judger
+	// processing 正在判题的提交 ID 列表，出队时原子移入，判题结束后移除；
+	// 以主机名与编号区分，进程崩溃后重启时放回判题队列
+	processing string
+}
+
+func NewWorker(id int) *Worker {
+	queue := config.Get().Queue.JudgeQueue
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return &Worker{
+		id:         id,
+		queue:      queue,
+		judger:     NewJudger(),
+		processing: fmt.Sprintf("%s:processing:%s:%d", queue, host, id),
+	}
+}
+
+// Run 持续从判题队列取出提交 ID 并判题，直到 ctx 被取消
+func (w *Worker) Run(ctx context.Context) {
+	logger.Service("Judge").Int("worker", w.id).Str("queue", w.queue).Msg("Worker started")
+
+	if count, err := redis.RecoverProcessing(w.queue, w.processing); err != nil {
+		logger.Error().Int("worker", w.id).Err(err).Msg("Recover processing submissions failed")
+	} else if count > 0 {
+		logger.Service("Judge").Int("worker", w.id).Int("count", count).Msg("Processing submissions requeued")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Service("Judge").Int("worker", w.id).Msg("Worker stopped")
+			return
+		default:
+		}
+
+		message, err := redis.DequeueReliable(w.queue, w.processing, pollTimeout)
+		if err != nil {
+			logger.Error().Int("worker", w.id).Err(err).Msg("Dequeue failed")
+			time.Sleep(time.Second)
+			continue
+		}
+		if message == nil {
+			continue
+		}
+
+		submitID := strings.TrimSpace(string(message))
+		start := time.Now()
+		err = w.judger.Judge(ctx, submitID)
+
+		// 服务退出中断的判题不写回结果，放回队列由下次启动的服务继续
+		if ctx.Err() != nil {
+			if err := redis.Requeue(w.queue, w.processing, message); err != nil {
+				logger.Error().Int("worker", w.id).Str("submit_id", submitID).Err(err).Msg("Requeue failed")
+			} else {
+				logger.Service("Judge").Int("worker", w.id).Str("submit_id", submitID).Msg("Judge interrupted, requeued")
+			}
+			continue
+		}
+
+		if err != nil {
+			logger.Error().
+				Int("worker", w.id).
+				Str("submit_id", submitID).
+				Err(err).
+				Msg("Judge failed")
+			w.retry(submitID, message)
+			continue
+		}
+
+		if err := redis.Ack(w.processing, message); err != nil {
+			logger.Error().Int("worker", w.id).Str("submit_id", submitID).Err(err).Msg("Ack failed")
+		}
+
+		logger.Service("Judge").
+			Int("worker", w.id).
+			Str("submit_id", submitID).
+			Dur("elapsed", time.Since(start)).
+			Msg("Judge finished")
+	}
+}
+
+// retry 判题出错的提交重新入队，超过重试次数后放弃，避免无法处理的消息反复占用队列
+func (w *Worker) retry(submitID string, message []byte) {
+	count, err := redis.Incr("judge:retry:"+submitID, judgeRetryTTL)
+	if err == nil && count <= maxJudgeRetries {
+		err = redis.Requeue(w.queue, w.processing, message)
+		if err == nil {
+			return
+		}
+	}
+	if err != nil {
+		// 留在 processing 列表中，服务重启时放回队列
+		logger.Error().Int("worker", w.id).Str("submit_id", submitID).Err(err).Msg("Requeue failed")
+		return
+	}
+
+	logger.Error().Int("worker", w.id).Str("submit_id", submitID).Int64("retries", count-1).Msg("Judge retries exhausted")
+	if err := redis.Ack(w.processing, message); err != nil {
+		logger.Error().Int("worker", w.id).Str("submit_id", submitID).Err(err).Msg("Ack failed")
+	}
+}
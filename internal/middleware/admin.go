@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"galaxy/internal/models"
+	"galaxy/pkg/database"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware 管理后台权限中间件，需放在认证中间件之后，只允许持有管理员角色的账户访问
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		accountID := c.GetString("account_id")
+		if accountID == "" {
+			abortJSON(c, http.StatusUnauthorized, "未提供认证令牌")
+			return
+		}
+
+		var count int64
+		if err := database.GetDB().Model(&models.AuthAccountRole{}).
+			Joins("JOIN auth_role ON auth_role.id = auth_account_role.role_id AND auth_role.delete_time IS NULL").
+			Where("auth_account_role.account_id = ? AND auth_role.code IN ?", accountID,
+				[]string{models.RoleCodeSuperAdmin, models.RoleCodeAdmin}).
+			Count(&count).Error; err != nil {
+			abortJSON(c, http.StatusInternalServerError, "服务器内部错误")
+			return
+		}
+		if count == 0 {
+			abortJSON(c, http.StatusForbidden, "没有管理后台的访问权限")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// abortJSON 以统一的响应格式中止请求
+func abortJSON(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{
+		"code":    status,
+		"data":    nil,
+		"message": message,
+		"success": false,
+	})
+	c.Abort()
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"errors"
+	shareContest "galaxy/internal/service/share/contest"
+	"galaxy/pkg/database"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContestAuthMiddleware 竞赛访问授权中间件，路由参数 id 为竞赛 ID，需放在认证中间件之后。
+// 非公开竞赛要求当前用户持有访问授权，未登录用户只能访问公开竞赛
+func ContestAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("account_id")
+		if userID == "" {
+			userID = c.GetString("user_id")
+		}
+
+		db := database.GetDB()
+		contest, err := shareContest.FindContest(db, c.Param("id"))
+		if err != nil {
+			if errors.Is(err, shareContest.ErrContestNotFound) {
+				abortJSON(c, http.StatusNotFound, err.Error())
+				return
+			}
+			abortJSON(c, http.StatusInternalServerError, "服务器内部错误")
+			return
+		}
+
+		if err := shareContest.CheckAccess(db, contest, userID); err != nil {
+			if shareContest.AccessDenied(err) {
+				abortJSON(c, http.StatusForbidden, err.Error())
+				return
+			}
+			abortJSON(c, http.StatusInternalServerError, "服务器内部错误")
+			return
+		}
+
+		c.Next()
+	}
+}
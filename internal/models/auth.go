@@ -83,6 +83,12 @@ func (AuthRole) TableName() string {
 	return "auth_role"
 }
 
+// 可以访问管理后台的角色编码
+const (
+	RoleCodeSuperAdmin = "super_admin" // 超级管理员
+	RoleCodeAdmin      = "admin"       // 管理员
+)
+
 // AuthRoleMenu 角色-菜单 关联表
 type AuthRoleMenu struct {
 	ID     string `gorm:"column:id;primaryKey;type:varchar(32)"`
@@ -24,13 +24,15 @@ type ContestInfo struct {
 	IsTeamContest     bool           `gorm:"column:is_team_contest;default:false"`
 	IsVisible         bool           `gorm:"column:is_visible;default:true"`
 	IsPublic          bool           `gorm:"column:is_public;default:false"`
-	Password          *string        `gorm:"column:password;type:varchar(100)"`
+	Password          *string        `gorm:"column:password;type:varchar(100)"` // bcrypt 哈希，非公开竞赛设置后可凭密码获得授权
 	RegisterStartTime *time.Time     `gorm:"column:register_start_time"`
 	RegisterEndTime   *time.Time     `gorm:"column:register_end_time"`
 	ContestStartTime  *time.Time     `gorm:"column:contest_start_time"`
 	ContestEndTime    *time.Time     `gorm:"column:contest_end_time"`
 	FrozenTime        int            `gorm:"column:frozen_time;default:0"`
 	PenaltyTime       int            `gorm:"column:penalty_time;default:20"`
+	UnfreezeTime      *time.Time     `gorm:"column:unfreeze_time"` // 赛后解除封榜的时间，未解除时为空
+	SettleTime        *time.Time     `gorm:"column:settle_time"`   // 结果公布后补计题目与用户统计的时间，未补计时为空
 	AllowedLanguages  datatypes.JSON `gorm:"column:allowed_languages;type:jsonb"`
 	Status            *string        `gorm:"column:status;type:varchar(32)"`
 	Sort              int            `gorm:"column:sort;default:0"`
@@ -40,11 +42,27 @@ func (ContestInfo) TableName() string {
 	return "contest_info"
 }
 
-// ContestAuth 竞赛认证表
+// 竞赛状态，由调度器按时间自动流转
+const (
+	ContestStatusScheduled   = "scheduled"   // 未开放报名
+	ContestStatusRegistering = "registering" // 报名中
+	ContestStatusRunning     = "running"     // 进行中
+	ContestStatusFrozen      = "frozen"      // 封榜
+	ContestStatusEnded       = "ended"       // 已结束
+)
+
+// 竞赛赛制
+const (
+	ContestRuleICPC = "icpc" // ICPC 赛制，按通过题数与罚时排名
+	ContestRuleOI   = "oi"   // OI 赛制，比赛结束后公布结果，每题以最后一次提交计分
+	ContestRuleIOI  = "ioi"  // IOI 赛制，实时公布得分，每个子任务取各次提交的最高分
+)
+
+// ContestAuth 竞赛认证表，非公开竞赛的访问授权：由管理员邀请或用户输入竞赛密码获得
 type ContestAuth struct {
 	model.BaseModel
-	ContestID string `gorm:"column:contest_id;type:varchar(32);not null;index:idx_contest_id"`
-	UserID    string `gorm:"column:user_id;type:varchar(32);not null;index:idx_user_id"`
+	ContestID string `gorm:"column:contest_id;type:varchar(32);not null;index:idx_contest_id;uniqueIndex:idx_contest_auth_key,where:delete_time IS NULL"`
+	UserID    string `gorm:"column:user_id;type:varchar(32);not null;index:idx_user_id;uniqueIndex:idx_contest_auth_key"`
 	IsAuth    bool   `gorm:"column:is_auth;default:false"`
 }
 
@@ -55,8 +73,8 @@ func (ContestAuth) TableName() string {
 // ContestParticipant 竞赛参与表
 type ContestParticipant struct {
 	model.BaseModel
-	ContestID    string     `gorm:"column:contest_id;type:varchar(32);not null;index:idx_contest_id"`
-	UserID       string     `gorm:"column:user_id;type:varchar(32);not null;index:idx_user_id"`
+	ContestID    string     `gorm:"column:contest_id;type:varchar(32);not null;index:idx_contest_id;uniqueIndex:idx_contest_participant_key,where:delete_time IS NULL"`
+	UserID       string     `gorm:"column:user_id;type:varchar(32);not null;index:idx_user_id;uniqueIndex:idx_contest_participant_key"`
 	TeamID       *string    `gorm:"column:team_id;type:varchar(32)"`
 	TeamName     *string    `gorm:"column:team_name;type:varchar(255)"`
 	IsTeamLeader bool       `gorm:"column:is_team_leader;default:false"`
@@ -68,6 +86,11 @@ func (ContestParticipant) TableName() string {
 	return "contest_participant"
 }
 
+// 参赛状态
+const (
+	ParticipantStatusRegistered = "registered" // 已报名
+)
+
 // ContestProblem 竞赛题目表
 type ContestProblem struct {
 	model.BaseModel
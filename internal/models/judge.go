@@ -27,6 +27,7 @@ type JudgeCase struct {
 	Status         *string `gorm:"column:status;type:varchar(32)"`
 	Message        *string `gorm:"column:message;type:text"`
 	ExitCode       int64   `gorm:"column:exit_code;default:0"`
+	SubtaskID      *string `gorm:"column:subtask_id;type:varchar(32)"`
 }
 
 func (JudgeCase) TableName() string {
@@ -47,6 +48,7 @@ type JudgeSubmit struct {
 	IsAdminSubmit bool    `gorm:"column:is_admin_submit;default:false"`
 	MaxTime       int     `gorm:"column:max_time;default:0"`
 	MaxMemory     int     `gorm:"column:max_memory;default:0"`
+	Score         float64 `gorm:"column:score;type:decimal(10,2);default:0.00"`
 	Message       *string `gorm:"column:message;type:text"`
 	Status        *string `gorm:"column:status;type:varchar(32)"`
 	IsFinish      bool    `gorm:"column:is_finish;default:false"`
@@ -56,3 +58,46 @@ type JudgeSubmit struct {
 func (JudgeSubmit) TableName() string {
 	return "judge_submit"
 }
+
+// JudgeSubtask 判题结果子任务表
+type JudgeSubtask struct {
+	model.BaseModel
+	SubmitID  string  `gorm:"column:submit_id;type:varchar(32);not null;index:idx_submit_id"`
+	SubtaskID string  `gorm:"column:subtask_id;type:varchar(32);not null"`
+	Title     *string `gorm:"column:title;type:varchar(255)"`
+	Sort      int     `gorm:"column:sort;default:0"`
+	Score     float64 `gorm:"column:score;type:decimal(10,2);default:0.00"`
+	FullScore float64 `gorm:"column:full_score;type:decimal(10,2);default:0.00"`
+	Status    *string `gorm:"column:status;type:varchar(32)"`
+	MaxTime   int     `gorm:"column:max_time;default:0"`
+	MaxMemory int     `gorm:"column:max_memory;default:0"`
+}
+
+func (JudgeSubtask) TableName() string {
+	return "judge_subtask"
+}
+
+// 判题状态
+const (
+	JudgeStatusPending             = "Pending"               // 等待判题
+	JudgeStatusCompiling           = "Compiling"             // 编译中
+	JudgeStatusJudging             = "Judging"               // 判题中
+	JudgeStatusAccepted            = "Accepted"              // 答案正确
+	JudgeStatusWrongAnswer         = "Wrong Answer"          // 答案错误
+	JudgeStatusPresentationError   = "Presentation Error"    // 格式错误
+	JudgeStatusPartialAccepted     = "Partial Accepted"      // 部分正确
+	JudgeStatusTimeLimitExceeded   = "Time Limit Exceeded"   // 超出时间限制
+	JudgeStatusMemoryLimitExceeded = "Memory Limit Exceeded" // 超出内存限制
+	JudgeStatusOutputLimitExceeded = "Output Limit Exceeded" // 超出输出限制
+	JudgeStatusRuntimeError        = "Runtime Error"         // 运行时错误
+	JudgeStatusCompileError        = "Compile Error"         // 编译错误
+	JudgeStatusSystemError         = "System Error"          // 系统错误
+	JudgeStatusSkipped             = "Skipped"               // 因子任务失败或依赖未满足而跳过
+	JudgeStatusSubmitted           = "Submitted"             // 已提交，竞赛结果公布前代替最终判定展示，不写入数据库
+)
+
+// 提交所属模块
+const (
+	ModuleTypeProblem = "problem" // 题库
+	ModuleTypeContest = "contest" // 竞赛
+)
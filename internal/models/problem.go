@@ -2,6 +2,7 @@ package models
 
 import (
 	"galaxy/pkg/model"
+	"time"
 
 	"gorm.io/datatypes"
 )
@@ -13,7 +14,7 @@ import (
 // ProblemInfo 题目表
 type ProblemInfo struct {
 	model.BaseModel
-	DisplayID    *string        `gorm:"column:display_id;type:varchar(32)"`
+	DisplayID    *string        `gorm:"column:display_id;type:varchar(32);uniqueIndex:idx_display_id,where:delete_time IS NULL"`
 	CategoryID   string         `gorm:"column:category_id;type:varchar(32);default:0;index:idx_category_id"`
 	Title        *string        `gorm:"column:title;type:varchar(255)"`
 	Source       *string        `gorm:"column:source;type:varchar(255)"`
@@ -21,6 +22,7 @@ type ProblemInfo struct {
 	TimeLimit    int            `gorm:"column:time_limit;default:0"`
 	MemoryLimit  int            `gorm:"column:memory_limit;default:0"`
 	Description  *string        `gorm:"column:description;type:text"`
+	Statement    datatypes.JSON `gorm:"column:statement;type:jsonb"` // 结构化题面，为空时使用 Description
 	Languages    datatypes.JSON `gorm:"column:languages;type:jsonb"`
 	Difficulty   int            `gorm:"column:difficulty;default:1;index:idx_difficulty"`
 	Threshold    float64        `gorm:"column:threshold;type:decimal(10,2);default:0.50"`
@@ -29,6 +31,16 @@ type ProblemInfo struct {
 	IsPublic     bool           `gorm:"column:is_public;default:false;index:idx_is_public"`
 	IsVisible    bool           `gorm:"column:is_visible;default:true;index:idx_is_visible"`
 	UseAI        bool           `gorm:"column:use_ai;default:false"`
+	// 输出比较方式
+	CheckerType     string  `gorm:"column:checker_type;type:varchar(32);default:trailing"`         // strict/trailing/float/unordered/special
+	CheckerAbsEps   float64 `gorm:"column:checker_abs_eps;type:double precision;default:0.000001"` // 浮点比较绝对误差
+	CheckerRelEps   float64 `gorm:"column:checker_rel_eps;type:double precision;default:0.000001"` // 浮点比较相对误差
+	CheckerCode     *string `gorm:"column:checker_code;type:text"`                                 // 特殊判题程序源码（testlib 兼容）
+	CheckerLanguage *string `gorm:"column:checker_language;type:varchar(64)"`                      // 特殊判题程序语言
+	// 交互题
+	IsInteractive      bool    `gorm:"column:is_interactive;default:false"`
+	InteractorCode     *string `gorm:"column:interactor_code;type:text"`            // 交互程序源码（testlib 兼容）
+	InteractorLanguage *string `gorm:"column:interactor_language;type:varchar(64)"` // 交互程序语言
 }
 
 func (ProblemInfo) TableName() string {
@@ -57,10 +69,77 @@ type ProblemTestCase struct {
 	InputFileSize  int64   `gorm:"column:input_file_size;default:0"`
 	OutputFilePath *string `gorm:"column:output_file_path;type:varchar(500)"`
 	OutputFileSize int64   `gorm:"column:output_file_size;default:0"`
+	InputFileHash  *string `gorm:"column:input_file_hash;type:varchar(64)"`  // 输入文件 SHA-256
+	OutputFileHash *string `gorm:"column:output_file_hash;type:varchar(64)"` // 输出文件 SHA-256
 	IsSample       bool    `gorm:"column:is_sample;default:false"`
 	Score          float64 `gorm:"column:score;type:decimal(10,2);default:0.00"`
+	SubtaskID      *string `gorm:"column:subtask_id;type:varchar(32);index:idx_subtask_id"` // 所属子任务，为空表示不分组
 }
 
 func (ProblemTestCase) TableName() string {
 	return "problem_test_case"
 }
+
+// ProblemSubtask 题目子任务表
+type ProblemSubtask struct {
+	model.BaseModel
+	ProblemID    string         `gorm:"column:problem_id;type:varchar(32);not null;index:idx_problem_id"`
+	Title        *string        `gorm:"column:title;type:varchar(255)"`
+	Score        float64        `gorm:"column:score;type:decimal(10,2);default:0.00"`    // 子任务满分，min 方式使用
+	Aggregation  string         `gorm:"column:aggregation;type:varchar(16);default:min"` // min: 按最差用例得分；sum: 用例得分求和
+	Dependencies datatypes.JSON `gorm:"column:dependencies;type:jsonb"`                  // 依赖的子任务 ID 列表，均满分才评测本子任务
+	Sort         int            `gorm:"column:sort;default:0"`
+}
+
+func (ProblemSubtask) TableName() string {
+	return "problem_subtask"
+}
+
+// 子任务得分方式
+const (
+	SubtaskAggregationMin = "min" // 取各用例得分比例的最小值，任一用例未通过即跳过其余用例
+	SubtaskAggregationSum = "sum" // 各用例得分之和
+)
+
+// ProblemSequence 题目展示编号序列，每个前缀一条记录
+type ProblemSequence struct {
+	model.BaseModel
+	Prefix    string `gorm:"column:prefix;type:varchar(16);not null;uniqueIndex:idx_sequence_prefix"`
+	NextValue int    `gorm:"column:next_value;default:1000"` // 下一个待分配的编号
+}
+
+func (ProblemSequence) TableName() string {
+	return "problem_sequence"
+}
+
+// ProblemReservedRange 保留的展示编号区间，自动分配时跳过，可手动指定
+type ProblemReservedRange struct {
+	model.BaseModel
+	Prefix     string  `gorm:"column:prefix;type:varchar(16);not null;index:idx_reserved_range_prefix"`
+	StartValue int     `gorm:"column:start_value;not null"`
+	EndValue   int     `gorm:"column:end_value;not null"` // 包含
+	Remark     *string `gorm:"column:remark;type:varchar(255)"`
+}
+
+func (ProblemReservedRange) TableName() string {
+	return "problem_reserved_range"
+}
+
+// ProblemStats 题目提交统计，判题完成时增量更新，重判后重新统计
+type ProblemStats struct {
+	model.BaseModel
+	ProblemID     string         `gorm:"column:problem_id;type:varchar(32);not null;uniqueIndex:idx_problem_stats_problem"`
+	SubmitCount   int64          `gorm:"column:submit_count;default:0"`   // 提交次数，不含测试与管理员提交
+	AcceptedCount int64          `gorm:"column:accepted_count;default:0"` // 通过次数
+	SolverCount   int64          `gorm:"column:solver_count;default:0"`   // 通过的不同用户数
+	Verdicts      datatypes.JSON `gorm:"column:verdicts;type:jsonb"`      // 各判题状态的次数
+	// 经验难度，由定时任务根据尝试者评分估计
+	Rating              float64    `gorm:"column:rating;type:double precision;default:0"` // 通过概率为 50% 的用户评分
+	RatingSamples       int        `gorm:"column:rating_samples;default:0"`               // 参与估计的有评分的尝试人数
+	SuggestedDifficulty int        `gorm:"column:suggested_difficulty;default:0"`         // 建议难度，0 表示样本不足
+	EstimateTime        *time.Time `gorm:"column:estimate_time"`
+}
+
+func (ProblemStats) TableName() string {
+	return "problem_stats"
+}
@@ -18,13 +18,13 @@ type RecordCodeLibrary struct {
 	ModuleType     *string        `gorm:"column:module_type;type:varchar(32)"`
 	ModuleID       *string        `gorm:"column:module_id;type:varchar(32)"`
 	ProblemID      *string        `gorm:"column:problem_id;type:varchar(32);index:idx_problem_id"`
-	SubmitID       *string        `gorm:"column:submit_id;type:varchar(32)"`
+	SubmitID       *string        `gorm:"column:submit_id;type:varchar(32);uniqueIndex:idx_record_code_library_submit,where:delete_time IS NULL"`
 	SubmitTime     *time.Time     `gorm:"column:submit_time"`
 	Language       *string        `gorm:"column:language;type:varchar(64);index:idx_language"`
 	Code           *string        `gorm:"column:code;type:text"`
-	CodeToken      datatypes.JSON `gorm:"column:code_token;type:jsonb"`
-	CodeTokenName  datatypes.JSON `gorm:"column:code_token_name;type:jsonb"`
-	CodeTokenTexts datatypes.JSON `gorm:"column:code_token_texts;type:jsonb"`
+	CodeToken      datatypes.JSON `gorm:"column:code_token;type:jsonb"`       // 归一化的词法序列，标识符与字面量替换为类别占位符
+	CodeTokenName  datatypes.JSON `gorm:"column:code_token_name;type:jsonb"`  // 各词法单元的类型
+	CodeTokenTexts datatypes.JSON `gorm:"column:code_token_texts;type:jsonb"` // 各词法单元的原文
 	CodeLength     int            `gorm:"column:code_length;default:0"`
 	AccessCount    int            `gorm:"column:access_count;default:0"`
 }
@@ -33,13 +33,13 @@ func (RecordCodeLibrary) TableName() string {
 	return "record_code_library"
 }
 
-// RecordSolved 用户解决表
+// RecordSolved 用户解决表，每个用户在每个模块下的每道题一条记录
 type RecordSolved struct {
 	model.BaseModel
-	ModuleType      *string    `gorm:"column:module_type;type:varchar(32)"`
-	ModuleID        *string    `gorm:"column:module_id;type:varchar(32)"`
-	UserID          *string    `gorm:"column:user_id;type:varchar(32)"`
-	ProblemID       *string    `gorm:"column:problem_id;type:varchar(32)"`
+	ModuleType      *string    `gorm:"column:module_type;type:varchar(32);uniqueIndex:idx_record_solved_key,where:delete_time IS NULL"`
+	ModuleID        *string    `gorm:"column:module_id;type:varchar(32);uniqueIndex:idx_record_solved_key"`
+	UserID          *string    `gorm:"column:user_id;type:varchar(32);uniqueIndex:idx_record_solved_key"`
+	ProblemID       *string    `gorm:"column:problem_id;type:varchar(32);uniqueIndex:idx_record_solved_key"`
 	SubmitID        *string    `gorm:"column:submit_id;type:varchar(32)"`
 	IsSolved        bool       `gorm:"column:is_solved;default:false"`
 	FirstSolvedTime *time.Time `gorm:"column:first_solved_time"`
@@ -57,6 +57,7 @@ type UserProfile struct {
 	// 隐私设置
 	ShowBirthday bool `gorm:"column:show_birthday;default:false"` // 是否显示生日
 	ShowLocation bool `gorm:"column:show_location;default:true"`  // 是否显示地理位置
+	ShowProgress bool `gorm:"column:show_progress;default:true"`  // 是否向他人公开做题记录
 }
 
 func (UserProfile) TableName() string {
@@ -84,7 +85,7 @@ func (UserPreference) TableName() string {
 // UserStats 用户统计信息表
 type UserStats struct {
 	model.BaseModel
-	AccountID string `gorm:"column:account_id;type:varchar(32);not null;uniqueIndex:idx_user_info_account"`
+	AccountID string `gorm:"column:account_id;type:varchar(32);not null;uniqueIndex:idx_user_stats_account"`
 	// 等级与经验
 	Level    int   `gorm:"column:level;default:1;index:idx_user_level"` // 等级
 	Exp      int64 `gorm:"column:exp;default:0"`                        // 经验值
@@ -98,6 +99,11 @@ type UserStats struct {
 	LikeCount    int64 `gorm:"column:like_count;default:0"`    // 获赞数
 	FollowCount  int64 `gorm:"column:follow_count;default:0"`  // 关注数
 	FansCount    int64 `gorm:"column:fans_count;default:0"`    // 粉丝数
+	// 做题统计，不含测试提交
+	SubmitCount    int64 `gorm:"column:submit_count;default:0"`    // 提交次数
+	AcceptedCount  int64 `gorm:"column:accepted_count;default:0"`  // 通过次数
+	AttemptedCount int64 `gorm:"column:attempted_count;default:0"` // 尝试过的题目数
+	SolvedCount    int64 `gorm:"column:solved_count;default:0"`    // 通过的题目数
 }
 
 func (UserStats) TableName() string {
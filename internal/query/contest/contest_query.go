@@ -0,0 +1,11 @@
+package contest
+
+import "galaxy/pkg/query"
+
+// ContestQueryRequest 竞赛查询请求，Keyword 匹配标题
+type ContestQueryRequest struct {
+	query.PaginationRequest
+	Status   string `json:"status" form:"status"` // scheduled/registering/running/frozen/ended
+	RuleType string `json:"rule_type" form:"rule_type"`
+	Category string `json:"category" form:"category"`
+}
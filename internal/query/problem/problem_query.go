@@ -0,0 +1,41 @@
+package problem
+
+import (
+	"galaxy/pkg/query"
+	"strings"
+)
+
+// 多标签筛选方式
+const (
+	TagModeAny = "or"  // 命中任一标签
+	TagModeAll = "and" // 命中全部标签
+)
+
+// ProblemQueryRequest 题目查询请求，Keyword 匹配标题与展示编号
+type ProblemQueryRequest struct {
+	query.PaginationRequest
+	CategoryID string   `json:"category_id" form:"category_id"`
+	Difficulty int      `json:"difficulty" form:"difficulty"` // 0 表示不限
+	TagID      string   `json:"tag_id" form:"tag_id"`
+	TagIDs     []string `json:"tag_ids" form:"tag_ids"`     // 可重复传参或以逗号分隔，与 TagID 合并
+	TagMode    string   `json:"tag_mode" form:"tag_mode"`   // or/and，默认 or
+	IsPublic   *bool    `json:"is_public" form:"is_public"` // 仅管理端生效
+}
+
+// Tags 合并 TagID 与 TagIDs
+func (r *ProblemQueryRequest) Tags() []string {
+	var tags []string
+	for _, value := range append([]string{r.TagID}, r.TagIDs...) {
+		for _, tagID := range strings.Split(value, ",") {
+			if tagID = strings.TrimSpace(tagID); tagID != "" {
+				tags = append(tags, tagID)
+			}
+		}
+	}
+	return tags
+}
+
+// MatchAllTags 是否要求命中全部标签
+func (r *ProblemQueryRequest) MatchAllTags() bool {
+	return strings.EqualFold(r.TagMode, TagModeAll)
+}
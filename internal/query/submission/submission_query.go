@@ -0,0 +1,13 @@
+package submission
+
+import "galaxy/pkg/query"
+
+// SubmissionQueryRequest 提交记录查询请求
+type SubmissionQueryRequest struct {
+	query.PaginationRequest
+	UserID    string `json:"user_id" form:"user_id"`
+	ProblemID string `json:"problem_id" form:"problem_id"` // 题目 ID 或展示编号
+	Language  string `json:"language" form:"language"`
+	Status    string `json:"status" form:"status"`
+	ContestID string `json:"contest_id" form:"contest_id"`
+}
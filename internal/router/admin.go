@@ -1,13 +1,27 @@
 package router
 
 import (
+	"galaxy/internal/handler/admin/contest"
+	"galaxy/internal/handler/admin/judge"
+	"galaxy/internal/handler/admin/problem"
+	internalMiddleware "galaxy/internal/middleware"
+	"galaxy/pkg/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
 // SetupAdminRoutes 设置管理后台路由
 func SetupAdminRoutes(engine *gin.Engine) {
-	//adminGroup := engine.Group("/admin/api/v1")
+	adminGroup := engine.Group("/admin/api/v1")
+	adminGroup.Use(middleware.AuthMiddleware(), internalMiddleware.AdminMiddleware())
 	//systemHandler := system.NewSystemHandler()
+	rejudgeHandler := judge.NewRejudgeHandler()
+	problemHandler := problem.NewProblemHandler()
+	testCaseHandler := problem.NewTestCaseHandler()
+	subtaskHandler := problem.NewSubtaskHandler()
+	packageHandler := problem.NewPackageHandler()
+	tagHandler := problem.NewTagHandler()
+	contestHandler := contest.NewContestHandler()
 
 	// 系统管理路由
 	//systemGroup := adminGroup.Group("/system")
@@ -17,4 +31,58 @@ func SetupAdminRoutes(engine *gin.Engine) {
 		//systemGroup.GET("/dict/:type", systemHandler.GetSysDict)
 		//systemGroup.GET("/menus", systemHandler.GetSysMenus)
 	}
+
+	// 题目管理
+	problemGroup := adminGroup.Group("/problems")
+	{
+		problemGroup.POST("", problemHandler.CreateProblem)
+		problemGroup.GET("", problemHandler.ProblemList)
+		problemGroup.POST("/import", packageHandler.ImportPackage) // 导入 FPS / Polygon / Galaxy 题目包
+		problemGroup.POST("/tags", tagHandler.AssignTags)          // 批量设置题目标签
+		problemGroup.GET("/:id", problemHandler.GetProblem)
+		problemGroup.PUT("/:id", problemHandler.UpdateProblem)
+		problemGroup.DELETE("/:id", problemHandler.DeleteProblem)
+		problemGroup.GET("/:id/test-cases", testCaseHandler.TestCaseList)            // 用例列表
+		problemGroup.POST("/:id/test-cases/upload", testCaseHandler.UploadTestCases) // 上传测试数据压缩包
+		problemGroup.GET("/:id/export", packageHandler.ExportPackage)                // 导出 Galaxy 题目包
+		problemGroup.GET("/:id/subtasks", subtaskHandler.SubtaskList)
+		problemGroup.POST("/:id/subtasks", subtaskHandler.CreateSubtask)
+		problemGroup.PUT("/:id/subtasks/:subtask_id", subtaskHandler.UpdateSubtask)
+		problemGroup.DELETE("/:id/subtasks/:subtask_id", subtaskHandler.DeleteSubtask)
+	}
+
+	// 题目标签
+	tagGroup := adminGroup.Group("/problem-tags")
+	{
+		tagGroup.POST("", tagHandler.CreateTag)
+		tagGroup.GET("", tagHandler.TagTree)
+		tagGroup.PUT("/:id", tagHandler.UpdateTag)
+		tagGroup.DELETE("/:id", tagHandler.DeleteTag)
+	}
+
+	// 竞赛管理
+	contestGroup := adminGroup.Group("/contests")
+	{
+		contestGroup.POST("", contestHandler.CreateContest)
+		contestGroup.GET("", contestHandler.ContestList)
+		contestGroup.GET("/:id", contestHandler.GetContest)
+		contestGroup.PUT("/:id", contestHandler.UpdateContest)
+		contestGroup.DELETE("/:id", contestHandler.DeleteContest)
+		contestGroup.PUT("/:id/problems", contestHandler.SetProblems)             // 整体替换竞赛题目
+		contestGroup.GET("/:id/scoreboard", contestHandler.Scoreboard)            // 不封榜的完整榜单
+		contestGroup.POST("/:id/unfreeze", contestHandler.Unfreeze)               // 赛后解除封榜
+		contestGroup.GET("/:id/event-feed", contestHandler.EventFeed)             // 导出 CDS 事件流，用于滚榜
+		contestGroup.GET("/:id/invites", contestHandler.Invites)                  // 已授权访问的用户
+		contestGroup.POST("/:id/invites", contestHandler.Invite)                  // 授权用户访问非公开竞赛
+		contestGroup.DELETE("/:id/invites/:user_id", contestHandler.RemoveInvite) // 撤销授权
+	}
+
+	// 重判
+	rejudgeGroup := adminGroup.Group("/rejudge")
+	{
+		rejudgeGroup.POST("/submissions/:id", rejudgeHandler.RejudgeSubmission) // 重判单个提交
+		rejudgeGroup.POST("/problems/:id", rejudgeHandler.RejudgeProblem)       // 重判题目的全部提交
+		rejudgeGroup.POST("/contests/:id", rejudgeHandler.RejudgeContest)       // 重判竞赛的全部提交
+		rejudgeGroup.GET("/tasks/:id", rejudgeHandler.GetTask)                  // 重判进度
+	}
 }
@@ -2,7 +2,12 @@ package router
 
 import (
 	"galaxy/internal/handler/share/config"
+	"galaxy/internal/handler/web/contest"
+	"galaxy/internal/handler/web/problem"
+	"galaxy/internal/handler/web/submission"
 	"galaxy/internal/handler/web/user"
+	internalMiddleware "galaxy/internal/middleware"
+	"galaxy/pkg/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,7 +17,11 @@ func SetupWebRoutes(engine *gin.Engine) {
 
 	authHandler := user.NewAuthHandler()
 	userHandler := user.NewUserHandler()
+	progressHandler := user.NewProgressHandler()
 	configHandler := config.NewConfigHandler()
+	submissionHandler := submission.NewSubmissionHandler()
+	problemHandler := problem.NewProblemHandler()
+	contestHandler := contest.NewContestHandler()
 
 	// ==================== 公开路由 ====================
 	public := api.Group("")
@@ -29,6 +38,47 @@ func SetupWebRoutes(engine *gin.Engine) {
 		open := public.Group("/open")
 		{
 			open.GET("/users/:id", userHandler.GetUserByID) // 获取用户公开信息
+
+			// 做题记录（本人始终可见，他人受隐私设置限制）
+			progress := open.Group("/users/:id")
+			progress.Use(middleware.OptionalAuthMiddleware())
+			{
+				progress.GET("/progress", progressHandler.GetProgress)        // 做题概况与热力图
+				progress.GET("/solved", progressHandler.SolvedProblems)       // 已通过题目
+				progress.GET("/attempted", progressHandler.AttemptedProblems) // 尝试未通过题目
+			}
+		}
+
+		// 题库
+		problems := public.Group("/problems")
+		{
+			problems.GET("", problemHandler.ProblemList)                                       // 公开题目列表
+			problems.GET("/tags", middleware.OptionalAuthMiddleware(), problemHandler.TagTree) // 标签树及各标签题目数
+			problems.GET("/:id", problemHandler.GetProblem)                                    // 公开题目详情，未结束竞赛中的题目除外
+		}
+
+		// 竞赛
+		contests := public.Group("/contests")
+		{
+			contests.GET("", contestHandler.ContestList)                                         // 竞赛列表
+			contests.GET("/:id", middleware.OptionalAuthMiddleware(), contestHandler.GetContest) // 竞赛详情，开始后包含题目
+
+			// 非公开竞赛须获得访问授权
+			authorized := contests.Group("/:id")
+			authorized.Use(middleware.OptionalAuthMiddleware(), internalMiddleware.ContestAuthMiddleware())
+			{
+				authorized.GET("/problems/:code", contestHandler.GetProblem) // 竞赛题目详情，比赛开始后可见
+				authorized.GET("/scoreboard", contestHandler.Scoreboard)     // 榜单，封榜后显示为待定
+			}
+		}
+
+		// 提交记录（登录用户可查看自己提交的代码）
+		submissions := public.Group("/submissions")
+		submissions.Use(middleware.OptionalAuthMiddleware())
+		{
+			submissions.GET("", submissionHandler.SubmissionList)              // 提交列表
+			submissions.GET("/:id", submissionHandler.GetSubmission)           // 提交详情
+			submissions.GET("/:id/events", submissionHandler.StreamSubmission) // 判题进度（SSE）
 		}
 	}
 
@@ -42,10 +92,28 @@ func SetupWebRoutes(engine *gin.Engine) {
 			auth.DELETE("/logout", authHandler.DoLogout) // 用户登出（需要认证）
 		}
 
+		// 提交代码
+		submitGroup := protected.Group("/submissions")
+		submitGroup.Use(middleware.AuthMiddleware())
+		{
+			submitGroup.POST("", submissionHandler.Submit)
+			submitGroup.GET("/events", submissionHandler.StreamUser) // 当前用户的判题进度（SSE）
+		}
+
+		// 竞赛报名，非公开竞赛须先获得访问授权
+		registerGroup := protected.Group("/contests/:id/register")
+		registerGroup.Use(middleware.AuthMiddleware(), internalMiddleware.ContestAuthMiddleware())
+		{
+			registerGroup.POST("", contestHandler.Register)             // 报名
+			registerGroup.DELETE("", contestHandler.CancelRegistration) // 取消报名
+		}
+		protected.POST("/contests/:id/unlock", middleware.AuthMiddleware(), contestHandler.Unlock) // 输入竞赛密码获得访问授权
+
 		// 用户管理
 		userGroup := protected.Group("/user")
 		{
 			userGroup.GET("/profile", userHandler.GetUserProfile)
+			userGroup.PUT("/privacy", middleware.AuthMiddleware(), userHandler.UpdatePrivacy) // 更新隐私设置，包括是否公开做题记录
 		}
 
 		// 配置管理
@@ -0,0 +1,478 @@
+package contest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/contest"
+	"galaxy/internal/judge/language"
+	"galaxy/internal/models"
+	contestQuery "galaxy/internal/query/contest"
+	shareContest "galaxy/internal/service/share/contest"
+	"galaxy/pkg/database"
+	"galaxy/pkg/query"
+	"galaxy/pkg/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultPenaltyTime 默认罚时（分钟），与表默认值一致
+const defaultPenaltyTime = 20
+
+// ruleTypes 支持的赛制
+var ruleTypes = map[string]bool{
+	models.ContestRuleICPC: true,
+	models.ContestRuleOI:   true,
+	models.ContestRuleIOI:  true,
+}
+
+// ContestService 竞赛管理服务接口定义
+type ContestService interface {
+	CreateContest(req *dto.ContestRequest, operator string) (*dto.ContestAdminDetail, error)
+	GetContest(id string) (*dto.ContestAdminDetail, error)
+	UpdateContest(id string, req *dto.ContestRequest, operator string) (*dto.ContestAdminDetail, error)
+	DeleteContest(id, operator string) error
+	ContestList(req *contestQuery.ContestQueryRequest) (*query.PaginationResponse[dto.ContestItem], error)
+	// SetProblems 整体替换竞赛题目
+	SetProblems(id string, req *dto.ContestProblemsRequest, operator string) ([]dto.ContestProblemItem, error)
+	// Scoreboard 不封榜的完整榜单
+	Scoreboard(id string) (*dto.Scoreboard, error)
+	// Unfreeze 赛后解除封榜
+	Unfreeze(id, operator string) error
+	// EventFeed 导出 CDS 事件流，用于 Resolver 滚榜
+	EventFeed(id string) ([]dto.FeedEvent, error)
+	// Invites 已授权访问竞赛的用户，包括受邀用户与凭密码获得授权的用户
+	Invites(id string) ([]dto.InviteItem, error)
+	// Invite 授权用户访问竞赛
+	Invite(id string, req *dto.InviteRequest, operator string) error
+	// RemoveInvite 撤销用户的访问授权
+	RemoveInvite(id, userID, operator string) error
+}
+
+// ContestServiceImpl 竞赛管理服务实现
+type ContestServiceImpl struct {
+	db *gorm.DB
+}
+
+// 确保 ContestServiceImpl 实现 ContestService 接口
+var _ ContestService = (*ContestServiceImpl)(nil)
+
+func NewContestService() ContestService {
+	return &ContestServiceImpl{
+		db: database.GetDB(),
+	}
+}
+
+// CreateContest 创建竞赛，返回与详情相同的结构，不包含密码哈希
+func (s *ContestServiceImpl) CreateContest(req *dto.ContestRequest, operator string) (*dto.ContestAdminDetail, error) {
+	contest := &models.ContestInfo{}
+	if err := applyRequest(contest, req); err != nil {
+		return nil, err
+	}
+	contest.CreateUser = &operator
+
+	// 显式写入全部字段，避免 is_visible 等零值被表默认值覆盖
+	if err := s.db.Select("*").Create(contest).Error; err != nil {
+		return nil, err
+	}
+	return s.GetContest(contest.ID)
+}
+
+// GetContest 获取竞赛详情，包含题目与报名人数
+func (s *ContestServiceImpl) GetContest(id string) (*dto.ContestAdminDetail, error) {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	languages, err := shareContest.ParseLanguages(contest.AllowedLanguages)
+	if err != nil {
+		return nil, err
+	}
+	problems, err := shareContest.ProblemItems(s.db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := shareContest.ParticipantCounts(s.db, []string{contest.ID})
+	if err != nil {
+		return nil, err
+	}
+	detail := &dto.ContestAdminDetail{
+		ContestItem:      dto.NewContestItem(contest, shareContest.StatusAt(contest, time.Now())),
+		Description:      contest.Description,
+		IsVisible:        contest.IsVisible,
+		AllowedLanguages: languages,
+		UnfreezeTime:     contest.UnfreezeTime,
+		Sort:             contest.Sort,
+		Problems:         problems,
+		CreateTime:       contest.CreatedAt,
+		UpdateTime:       contest.UpdatedAt,
+	}
+	detail.ParticipantCount = counts[contest.ID]
+	// 公开竞赛的密码不生效，管理端仍需知道是否设置过
+	detail.HasPassword = shareContest.HasPassword(contest)
+	return detail, nil
+}
+
+// UpdateContest 更新竞赛，状态按新的时间重新计算，返回与详情相同的结构
+func (s *ContestServiceImpl) UpdateContest(id string, req *dto.ContestRequest, operator string) (*dto.ContestAdminDetail, error) {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyRequest(contest, req); err != nil {
+		return nil, err
+	}
+	contest.UpdateUser = &operator
+	// 时间与赛制可能变化，结果公布后重新补计统计
+	contest.SettleTime = nil
+
+	if err := s.db.Save(contest).Error; err != nil {
+		return nil, err
+	}
+	return s.GetContest(contest.ID)
+}
+
+// DeleteContest 删除竞赛及其题目、报名与认证记录
+func (s *ContestServiceImpl) DeleteContest(id, operator string) error {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(contest).Update("delete_user", operator).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(contest).Error; err != nil {
+			return err
+		}
+		for _, table := range []interface{}{&models.ContestProblem{}, &models.ContestParticipant{}, &models.ContestAuth{}} {
+			if err := tx.Where("contest_id = ?", contest.ID).Delete(table).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ContestList 获取竞赛列表，包含隐藏的竞赛
+func (s *ContestServiceImpl) ContestList(req *contestQuery.ContestQueryRequest) (*query.PaginationResponse[dto.ContestItem], error) {
+	return shareContest.ContestList(s.db, req)
+}
+
+// SetProblems 整体替换竞赛题目，题目编号未指定时按顺序分配
+func (s *ContestServiceImpl) SetProblems(id string, req *dto.ContestProblemsRequest, operator string) ([]dto.ContestProblemItem, error) {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	problemIDs := make([]string, 0, len(req.Problems))
+	for _, item := range req.Problems {
+		problemIDs = append(problemIDs, item.ProblemID)
+	}
+	var infos []models.ProblemInfo
+	if err := s.db.Select("id", "display_id").Where("id IN ?", problemIDs).Find(&infos).Error; err != nil {
+		return nil, err
+	}
+	displayIDs := make(map[string]*string, len(infos))
+	for i := range infos {
+		displayIDs[infos[i].ID] = infos[i].DisplayID
+	}
+
+	problems := make([]models.ContestProblem, 0, len(req.Problems))
+	seenProblems := make(map[string]bool, len(req.Problems))
+	seenCodes := make(map[string]bool, len(req.Problems))
+	for i, item := range req.Problems {
+		if _, ok := displayIDs[item.ProblemID]; !ok {
+			return nil, fmt.Errorf("题目不存在: %s", item.ProblemID)
+		}
+		if seenProblems[item.ProblemID] {
+			return nil, fmt.Errorf("题目重复: %s", item.ProblemID)
+		}
+		seenProblems[item.ProblemID] = true
+
+		code := strings.ToUpper(strings.TrimSpace(item.ProblemCode))
+		if code == "" {
+			code = problemCode(i)
+		}
+		if seenCodes[code] {
+			return nil, fmt.Errorf("题目编号重复: %s", code)
+		}
+		seenCodes[code] = true
+
+		problems = append(problems, models.ContestProblem{
+			ContestID:   contest.ID,
+			DisplayID:   displayIDs[item.ProblemID],
+			ProblemCode: code,
+			ProblemID:   item.ProblemID,
+			Score:       item.Score,
+			Sort:        i,
+		})
+		problems[i].CreateUser = &operator
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("contest_id = ?", contest.ID).Delete(&models.ContestProblem{}).Error; err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			return nil
+		}
+		return tx.Create(&problems).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shareContest.ProblemItems(s.db, contest.ID)
+}
+
+// Scoreboard 获取完整榜单，封榜期间也显示全部结果
+func (s *ContestServiceImpl) Scoreboard(id string) (*dto.Scoreboard, error) {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return shareContest.BuildScoreboard(s.db, contest, false)
+}
+
+// Unfreeze 比赛结束后解除封榜，选手看到的榜单显示全部结果
+func (s *ContestServiceImpl) Unfreeze(id, operator string) error {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return err
+	}
+	if shareContest.FreezeTime(contest) == nil {
+		return errors.New("竞赛未设置封榜")
+	}
+	now := time.Now()
+	if contest.ContestEndTime == nil || now.Before(*contest.ContestEndTime) {
+		return shareContest.ErrContestNotEnded
+	}
+	if contest.UnfreezeTime != nil {
+		return errors.New("竞赛已解除封榜")
+	}
+
+	return s.db.Model(contest).Updates(map[string]interface{}{
+		"unfreeze_time": now,
+		"update_user":   operator,
+	}).Error
+}
+
+// EventFeed 导出比赛的 CDS 事件流
+func (s *ContestServiceImpl) EventFeed(id string) ([]dto.FeedEvent, error) {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return shareContest.EventFeed(s.db, contest)
+}
+
+// Invites 获取已授权访问竞赛的用户，按授权时间排列
+func (s *ContestServiceImpl) Invites(id string) ([]dto.InviteItem, error) {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var auths []models.ContestAuth
+	if err := s.db.Where("contest_id = ? AND is_auth = ?", contest.ID, true).
+		Order("create_time ASC, id ASC").
+		Find(&auths).Error; err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(auths))
+	for i := range auths {
+		userIDs = append(userIDs, auths[i].UserID)
+	}
+
+	var accounts []models.AuthAccount
+	if err := s.db.Unscoped().Select("id", "username").Where("id IN ?", userIDs).Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	usernames := make(map[string]string, len(accounts))
+	for i := range accounts {
+		usernames[accounts[i].ID] = accounts[i].Username
+	}
+	var infos []models.UserInfo
+	if err := s.db.Select("account_id", "nickname").Where("account_id IN ?", userIDs).Find(&infos).Error; err != nil {
+		return nil, err
+	}
+	nicknames := make(map[string]string, len(infos))
+	for i := range infos {
+		nicknames[infos[i].AccountID] = infos[i].Nickname
+	}
+
+	items := make([]dto.InviteItem, 0, len(auths))
+	for i := range auths {
+		items = append(items, dto.InviteItem{
+			UserID:     auths[i].UserID,
+			Username:   usernames[auths[i].UserID],
+			Nickname:   nicknames[auths[i].UserID],
+			CreateTime: auths[i].CreatedAt,
+		})
+	}
+	return items, nil
+}
+
+// Invite 按用户 ID 或用户名授权用户访问竞赛，任一用户不存在时不做修改
+func (s *ContestServiceImpl) Invite(id string, req *dto.InviteRequest, operator string) error {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return err
+	}
+
+	var accounts []models.AuthAccount
+	if err := s.db.Select("id", "username").
+		Where("id IN ? OR username IN ?", req.Users, req.Users).
+		Find(&accounts).Error; err != nil {
+		return err
+	}
+	found := make(map[string]string, len(accounts)*2)
+	for i := range accounts {
+		found[accounts[i].ID] = accounts[i].ID
+		found[accounts[i].Username] = accounts[i].ID
+	}
+	userIDs := make([]string, 0, len(req.Users))
+	for _, user := range req.Users {
+		userID, ok := found[user]
+		if !ok {
+			return fmt.Errorf("用户不存在: %s", user)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for _, userID := range userIDs {
+			if err := shareContest.Grant(tx, contest.ID, userID, operator); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveInvite 撤销用户的访问授权，已报名的记录保留
+func (s *ContestServiceImpl) RemoveInvite(id, userID, operator string) error {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		db := tx.Model(&models.ContestAuth{}).Where("contest_id = ? AND user_id = ?", contest.ID, userID)
+		if err := db.Session(&gorm.Session{}).Update("delete_user", operator).Error; err != nil {
+			return err
+		}
+		result := db.Session(&gorm.Session{}).Delete(&models.ContestAuth{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("该用户未获得授权")
+		}
+		return nil
+	})
+}
+
+// problemCode 第 i 道题的默认编号：A…Z、AA、AB…
+func problemCode(i int) string {
+	code := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		code = string(rune('A'+(i-1)%26)) + code
+	}
+	return code
+}
+
+// applyRequest 校验请求并写入竞赛
+func applyRequest(contest *models.ContestInfo, req *dto.ContestRequest) error {
+	ruleType := req.RuleType
+	if ruleType == "" {
+		ruleType = models.ContestRuleICPC
+	}
+	if !ruleTypes[ruleType] {
+		return fmt.Errorf("不支持的赛制: %s", ruleType)
+	}
+
+	start, end := req.ContestStartTime, req.ContestEndTime
+	if !start.Before(*end) {
+		return errors.New("比赛结束时间必须晚于开始时间")
+	}
+	if req.RegisterStartTime != nil && req.RegisterEndTime != nil && !req.RegisterStartTime.Before(*req.RegisterEndTime) {
+		return errors.New("报名截止时间必须晚于报名开始时间")
+	}
+	if req.RegisterStartTime != nil && !req.RegisterStartTime.Before(*end) {
+		return errors.New("报名开始时间必须早于比赛结束时间")
+	}
+	if req.RegisterEndTime != nil && req.RegisterEndTime.After(*end) {
+		return errors.New("报名截止时间不能晚于比赛结束时间")
+	}
+	if req.FrozenTime > 0 && ruleType != models.ContestRuleICPC {
+		return errors.New("仅 ICPC 赛制支持封榜")
+	}
+	if time.Duration(req.FrozenTime)*time.Minute > end.Sub(*start) {
+		return errors.New("封榜时长不能超过比赛时长")
+	}
+
+	maxTeamMembers := req.MaxTeamMembers
+	if maxTeamMembers <= 0 {
+		maxTeamMembers = 1
+	}
+	if req.IsTeamContest && maxTeamMembers < 2 {
+		return errors.New("团队赛每队人数至少为 2")
+	}
+
+	registry := language.Get()
+	for _, name := range req.AllowedLanguages {
+		if _, ok := registry.Get(name); !ok {
+			return fmt.Errorf("不支持的语言: %s", name)
+		}
+	}
+	var languages []byte
+	if len(req.AllowedLanguages) > 0 {
+		data, err := json.Marshal(req.AllowedLanguages)
+		if err != nil {
+			return err
+		}
+		languages = data
+	}
+
+	contest.Title = req.Title
+	contest.Description = req.Description
+	contest.ContestType = req.ContestType
+	contest.RuleType = &ruleType
+	contest.Category = req.Category
+	contest.Cover = req.Cover
+	contest.MaxTeamMembers = maxTeamMembers
+	contest.IsTeamContest = req.IsTeamContest
+	contest.IsVisible = req.IsVisible
+	contest.IsPublic = req.IsPublic
+	if req.Password != nil {
+		contest.Password = nil
+		if *req.Password != "" {
+			hash, err := utils.HashPassword(*req.Password)
+			if err != nil {
+				return err
+			}
+			contest.Password = &hash
+		}
+	}
+	contest.RegisterStartTime = req.RegisterStartTime
+	contest.RegisterEndTime = req.RegisterEndTime
+	contest.ContestStartTime = start
+	contest.ContestEndTime = end
+	contest.FrozenTime = req.FrozenTime
+	contest.PenaltyTime = req.PenaltyTime
+	if contest.PenaltyTime <= 0 {
+		contest.PenaltyTime = defaultPenaltyTime
+	}
+	contest.AllowedLanguages = languages
+	contest.Sort = req.Sort
+
+	status := shareContest.StatusAt(contest, time.Now())
+	contest.Status = &status
+	return nil
+}
@@ -0,0 +1,253 @@
+package judge
+
+import (
+	"encoding/json"
+	"errors"
+	dto "galaxy/internal/dto/judge"
+	"galaxy/internal/models"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/redis"
+	"galaxy/pkg/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	rejudgeTaskKeyPrefix = "rejudge:task:"    // 重判任务键前缀
+	rejudgeTaskTTL       = 7 * 24 * time.Hour // 重判任务保留时间
+	rejudgeBatchSize     = 500                // 单条 SQL 处理的提交数
+)
+
+var ErrRejudgeTaskNotFound = errors.New("重判任务不存在或已过期")
+
+// RejudgeService 重判服务接口定义
+type RejudgeService interface {
+	RejudgeSubmission(submitID, operator string) (*dto.RejudgeTask, error)
+	RejudgeProblem(problemID, operator string) (*dto.RejudgeTask, error)
+	RejudgeContest(contestID, operator string) (*dto.RejudgeTask, error)
+	GetTask(taskID string) (*dto.RejudgeProgress, error)
+}
+
+// RejudgeServiceImpl 重判服务实现
+type RejudgeServiceImpl struct {
+	db    *gorm.DB
+	queue string
+}
+
+// 确保 RejudgeServiceImpl 实现 RejudgeService 接口
+var _ RejudgeService = (*RejudgeServiceImpl)(nil)
+
+func NewRejudgeService() RejudgeService {
+	return &RejudgeServiceImpl{
+		db:    database.GetDB(),
+		queue: config.Get().Queue.JudgeQueue,
+	}
+}
+
+// RejudgeSubmission 重判单个提交
+func (s *RejudgeServiceImpl) RejudgeSubmission(submitID, operator string) (*dto.RejudgeTask, error) {
+	var submit models.JudgeSubmit
+	if err := s.db.Select("id", "is_finish").Where("id = ?", submitID).First(&submit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("提交不存在")
+		}
+		return nil, err
+	}
+	if !submit.IsFinish {
+		return nil, errors.New("提交正在判题中")
+	}
+	return s.rejudge(dto.RejudgeScopeSubmission, submitID, operator, []string{submit.ID})
+}
+
+// RejudgeProblem 重判题目的全部提交（包括竞赛中的提交）
+func (s *RejudgeServiceImpl) RejudgeProblem(problemID, operator string) (*dto.RejudgeTask, error) {
+	var count int64
+	if err := s.db.Model(&models.ProblemInfo{}).Where("id = ?", problemID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("题目不存在")
+	}
+
+	ids, err := s.finishedSubmissions(s.db.Where("problem_id = ?", problemID))
+	if err != nil {
+		return nil, err
+	}
+	return s.rejudge(dto.RejudgeScopeProblem, problemID, operator, ids)
+}
+
+// RejudgeContest 重判竞赛的全部提交
+func (s *RejudgeServiceImpl) RejudgeContest(contestID, operator string) (*dto.RejudgeTask, error) {
+	var count int64
+	if err := s.db.Model(&models.ContestInfo{}).Where("id = ?", contestID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("竞赛不存在")
+	}
+
+	ids, err := s.finishedSubmissions(s.db.Where("module_type = ? AND module_id = ?", models.ModuleTypeContest, contestID))
+	if err != nil {
+		return nil, err
+	}
+	return s.rejudge(dto.RejudgeScopeContest, contestID, operator, ids)
+}
+
+// finishedSubmissions 符合条件且已完成判题的提交，判题中的提交不参与重判
+func (s *RejudgeServiceImpl) finishedSubmissions(scope *gorm.DB) ([]string, error) {
+	var ids []string
+	err := s.db.Model(&models.JudgeSubmit{}).
+		Where(scope).
+		Where("is_finish = ?", true).
+		Order("create_time ASC, id ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// rejudge 在事务中重置提交并清理旧结果，提交后按原提交顺序重新入队。
+// 判题中的提交与所属重判任务尚未完成的提交不参与，避免改变进行中任务的进度统计
+func (s *RejudgeServiceImpl) rejudge(scope, targetID, operator string, ids []string) (*dto.RejudgeTask, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("没有需要重判的提交")
+	}
+
+	task := &dto.RejudgeTask{
+		TaskID:     utils.GenerateID(),
+		Scope:      scope,
+		TargetID:   targetID,
+		Operator:   operator,
+		CreateTime: time.Now(),
+	}
+
+	var reset []string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		running := tx.Model(&models.JudgeSubmit{}).
+			Distinct("task_id").
+			Where("task_id IS NOT NULL AND is_finish = ?", false)
+		for start := 0; start < len(ids); start += rejudgeBatchSize {
+			batch := ids[start:min(start+rejudgeBatchSize, len(ids))]
+			result := tx.Model(&models.JudgeSubmit{}).
+				Where("id IN ? AND is_finish = ?", batch, true).
+				Where("task_id IS NULL OR task_id NOT IN (?)", running).
+				Updates(map[string]interface{}{
+					"status":     models.JudgeStatusPending,
+					"message":    nil,
+					"max_time":   0,
+					"max_memory": 0,
+					"score":      0,
+					"is_finish":  false,
+					"task_id":    task.TaskID,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			var batchReset []string
+			if err := tx.Model(&models.JudgeSubmit{}).
+				Where("id IN ? AND task_id = ?", batch, task.TaskID).
+				Order("create_time ASC, id ASC").
+				Pluck("id", &batchReset).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("submit_id IN ?", batchReset).Delete(&models.JudgeCase{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("submit_id IN ?", batchReset).Delete(&models.JudgeSubtask{}).Error; err != nil {
+				return err
+			}
+			reset = append(reset, batchReset...)
+		}
+		if len(reset) == 0 {
+			return errors.New("没有需要重判的提交")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	task.Total = int64(len(reset))
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	if err := redis.Set(rejudgeTaskKeyPrefix+task.TaskID, data, rejudgeTaskTTL); err != nil {
+		s.abandon(task.TaskID, err)
+		return nil, err
+	}
+
+	messages := make([][]byte, len(reset))
+	for i, id := range reset {
+		messages[i] = []byte(id)
+	}
+	if err := redis.EnqueueAll(s.queue, messages); err != nil {
+		s.abandon(task.TaskID, err)
+		return nil, err
+	}
+	return task, nil
+}
+
+// abandon 重判任务入队失败时，将已重置的提交标记为系统错误并结束判题，管理员可以再次重判
+func (s *RejudgeServiceImpl) abandon(taskID string, cause error) {
+	message := "重判入队失败：" + cause.Error()
+	if err := s.db.Model(&models.JudgeSubmit{}).
+		Where("task_id = ? AND is_finish = ?", taskID, false).
+		Updates(map[string]interface{}{
+			"status":    models.JudgeStatusSystemError,
+			"message":   message,
+			"is_finish": true,
+		}).Error; err != nil {
+		logger.Error().Str("task_id", taskID).Err(err).Msg("Abandon rejudge task failed")
+	}
+}
+
+// GetTask 查询重判进度
+func (s *RejudgeServiceImpl) GetTask(taskID string) (*dto.RejudgeProgress, error) {
+	exists, err := redis.Exists(rejudgeTaskKeyPrefix + taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrRejudgeTaskNotFound
+	}
+	data, err := redis.Get(rejudgeTaskKeyPrefix + taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &dto.RejudgeProgress{Statuses: make(map[string]int64)}
+	if err := json.Unmarshal([]byte(data), &progress.RejudgeTask); err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Status   *string
+		IsFinish bool
+		Count    int64
+	}
+	if err := s.db.Model(&models.JudgeSubmit{}).
+		Select("status, is_finish, COUNT(*) AS count").
+		Where("task_id = ?", taskID).
+		Group("status, is_finish").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		status := ""
+		if row.Status != nil {
+			status = *row.Status
+		}
+		progress.Statuses[status] += row.Count
+		if row.IsFinish {
+			progress.Finished += row.Count
+		}
+	}
+	progress.IsDone = progress.Finished >= progress.Total
+	return progress, nil
+}
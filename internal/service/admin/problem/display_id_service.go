@@ -0,0 +1,213 @@
+package problem
+
+import (
+	"errors"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/models"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// maxDisplayIDPrefix 前缀最大长度，与 problem_sequence.prefix 一致
+const maxDisplayIDPrefix = 16
+
+// errDryRun 试运行时用于回滚事务
+var errDryRun = errors.New("dry run")
+
+// DisplayIDService 题目展示编号维护服务接口定义
+type DisplayIDService interface {
+	// Renumber 按创建顺序为匹配的题目重新分配连续编号
+	Renumber(req *dto.RenumberRequest) ([]dto.RenumberItem, error)
+	// Reserve 保留编号区间，自动分配时跳过
+	Reserve(req *dto.ReserveRequest) (*models.ProblemReservedRange, error)
+	// Release 取消保留区间
+	Release(id string) error
+	ReservedRanges(prefix string) ([]models.ProblemReservedRange, error)
+}
+
+// DisplayIDServiceImpl 题目展示编号维护服务实现
+type DisplayIDServiceImpl struct {
+	db *gorm.DB
+}
+
+// 确保 DisplayIDServiceImpl 实现 DisplayIDService 接口
+var _ DisplayIDService = (*DisplayIDServiceImpl)(nil)
+
+func NewDisplayIDService() DisplayIDService {
+	return &DisplayIDServiceImpl{
+		db: database.GetDB(),
+	}
+}
+
+// Renumber 重新编号，跳过保留区间及其他题目（含已删除题目）占用的编号
+func (s *DisplayIDServiceImpl) Renumber(req *dto.RenumberRequest) ([]dto.RenumberItem, error) {
+	if err := checkPrefix(req.Prefix); err != nil {
+		return nil, err
+	}
+	if req.Start < 0 {
+		return nil, errors.New("起始编号不能为负数")
+	}
+
+	var items []dto.RenumberItem
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		sequence, err := shareProblem.LockSequence(tx, req.Prefix)
+		if err != nil {
+			return err
+		}
+		ranges, err := shareProblem.ReservedRanges(tx, req.Prefix)
+		if err != nil {
+			return err
+		}
+
+		db := tx.Model(&models.ProblemInfo{})
+		if req.CategoryID != "" {
+			db = db.Where("category_id = ?", req.CategoryID)
+		}
+		if req.Source != "" {
+			db = db.Where("source = ?", req.Source)
+		}
+		var problems []models.ProblemInfo
+		if err := db.Select("id", "title", "display_id").
+			Order("create_time ASC, id ASC").
+			Find(&problems).Error; err != nil {
+			return err
+		}
+		if len(problems) == 0 {
+			return nil
+		}
+
+		targets := make(map[string]bool, len(problems))
+		ids := make([]string, 0, len(problems))
+		for i := range problems {
+			targets[problems[i].ID] = true
+			ids = append(ids, problems[i].ID)
+		}
+
+		var others []models.ProblemInfo
+		if err := tx.Unscoped().Model(&models.ProblemInfo{}).
+			Select("id", "display_id").
+			Where("display_id LIKE ?", req.Prefix+"%").
+			Find(&others).Error; err != nil {
+			return err
+		}
+		taken := make(map[string]bool, len(others))
+		for i := range others {
+			if !targets[others[i].ID] && others[i].DisplayID != nil {
+				taken[*others[i].DisplayID] = true
+			}
+		}
+		used := func(displayID string) (bool, error) {
+			return taken[displayID], nil
+		}
+
+		value := req.Start
+		for i := range problems {
+			problem := &problems[i]
+			if value, err = shareProblem.NextDisplayValue(req.Prefix, value, ranges, used); err != nil {
+				return err
+			}
+			items = append(items, dto.RenumberItem{
+				ProblemID:    problem.ID,
+				Title:        problem.Title,
+				OldDisplayID: problem.DisplayID,
+				NewDisplayID: shareProblem.FormatDisplayID(req.Prefix, value),
+			})
+			value++
+		}
+		if req.DryRun {
+			return errDryRun
+		}
+
+		// 先清空再写入，避免新旧编号互相冲突
+		if err := tx.Model(&models.ProblemInfo{}).Where("id IN ?", ids).Update("display_id", nil).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := tx.Model(&models.ProblemInfo{}).
+				Where("id = ?", item.ProblemID).
+				Update("display_id", item.NewDisplayID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(sequence).Update("next_value", value).Error
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Reserve 保留编号区间，不能与同前缀的已有区间重叠
+func (s *DisplayIDServiceImpl) Reserve(req *dto.ReserveRequest) (*models.ProblemReservedRange, error) {
+	if err := checkPrefix(req.Prefix); err != nil {
+		return nil, err
+	}
+	if req.Start < 0 || req.End < req.Start {
+		return nil, errors.New("编号区间无效")
+	}
+
+	reserved := &models.ProblemReservedRange{
+		Prefix:     req.Prefix,
+		StartValue: req.Start,
+		EndValue:   req.End,
+	}
+	if req.Remark != "" {
+		reserved.Remark = &req.Remark
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// 锁定序列，避免与编号分配并发
+		if _, err := shareProblem.LockSequence(tx, req.Prefix); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.ProblemReservedRange{}).
+			Where("prefix = ? AND start_value <= ? AND end_value >= ?", req.Prefix, req.End, req.Start).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errors.New("与已有的保留区间重叠")
+		}
+		return tx.Create(reserved).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reserved, nil
+}
+
+// Release 取消保留区间
+func (s *DisplayIDServiceImpl) Release(id string) error {
+	result := s.db.Where("id = ?", id).Delete(&models.ProblemReservedRange{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("保留区间不存在")
+	}
+	return nil
+}
+
+// ReservedRanges 获取保留区间列表，prefix 为空时返回全部
+func (s *DisplayIDServiceImpl) ReservedRanges(prefix string) ([]models.ProblemReservedRange, error) {
+	db := s.db
+	if prefix != "" {
+		db = db.Where("prefix = ?", prefix)
+	}
+	var ranges []models.ProblemReservedRange
+	if err := db.Order("prefix ASC, start_value ASC").Find(&ranges).Error; err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// checkPrefix 前缀为空或由字母、数字、下划线与连字符组成
+func checkPrefix(prefix string) error {
+	if len(prefix) > maxDisplayIDPrefix || (prefix != "" && !shareProblem.ValidDisplayID(prefix)) {
+		return errors.New("编号前缀只能包含字母、数字、下划线与连字符，且不超过16个字符")
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package problem
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/judge/checker"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const maxFPSFileSize = 256 << 20 // 单个 FPS 文件的大小上限，用例数据内嵌其中
+
+// fpsDocument FPS（Free Problem Set）文件，一个文件可包含多道题目
+type fpsDocument struct {
+	Items []fpsItem `xml:"item"`
+}
+
+type fpsItem struct {
+	Title        string       `xml:"title"`
+	TimeLimit    fpsLimit     `xml:"time_limit"`
+	MemoryLimit  fpsLimit     `xml:"memory_limit"`
+	Description  string       `xml:"description"`
+	Input        string       `xml:"input"`
+	Output       string       `xml:"output"`
+	SampleInput  []string     `xml:"sample_input"`
+	SampleOutput []string     `xml:"sample_output"`
+	TestInput    []string     `xml:"test_input"`
+	TestOutput   []string     `xml:"test_output"`
+	Hint         string       `xml:"hint"`
+	Source       string       `xml:"source"`
+	SpecialJudge []fpsProgram `xml:"spj"`
+	Template     []fpsProgram `xml:"template"`
+	Prepend      []fpsProgram `xml:"prepend"`
+	Append       []fpsProgram `xml:"append"`
+	Images       []fpsImage   `xml:"img"`
+}
+
+type fpsLimit struct {
+	Unit  string `xml:"unit,attr"`
+	Value string `xml:",chardata"`
+}
+
+type fpsProgram struct {
+	Language string `xml:"language,attr"`
+	Code     string `xml:",chardata"`
+}
+
+type fpsImage struct {
+	Src string `xml:"src"`
+}
+
+// fpsLanguages FPS 语言名到系统语言名的映射
+var fpsLanguages = map[string]string{
+	"c":          "C",
+	"c++":        "C++17",
+	"cpp":        "C++17",
+	"java":       "Java",
+	"python":     "Python3",
+	"python3":    "Python3",
+	"go":         "Go",
+	"golang":     "Go",
+	"rust":       "Rust",
+	"javascript": "JavaScript",
+	"js":         "JavaScript",
+}
+
+func fpsLanguage(name string) string {
+	if mapped, ok := fpsLanguages[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return mapped
+	}
+	return strings.TrimSpace(name)
+}
+
+// parseFPSArchive 解析压缩包中的全部 FPS 文件
+func parseFPSArchive(archive *zip.Reader) ([]*problemPackage, error) {
+	var packages []*problemPackage
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") ||
+			!strings.EqualFold(path.Ext(file.Name), ".xml") {
+			continue
+		}
+		if file.UncompressedSize64 > maxFPSFileSize {
+			return nil, fmt.Errorf("文件 %s 过大", file.Name)
+		}
+		src, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		items, err := parseFPS(src)
+		src.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		packages = append(packages, items...)
+	}
+	return packages, nil
+}
+
+// parseFPS 解析 FPS 文件，样例与测试数据均内嵌在 XML 中
+func parseFPS(r io.Reader) ([]*problemPackage, error) {
+	var doc fpsDocument
+	if err := xml.NewDecoder(io.LimitReader(r, maxFPSFileSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("FPS 文件格式错误: %w", err)
+	}
+
+	packages := make([]*problemPackage, 0, len(doc.Items))
+	for i := range doc.Items {
+		pkg, err := parseFPSItem(&doc.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+func parseFPSItem(item *fpsItem) (*problemPackage, error) {
+	title := strings.TrimSpace(item.Title)
+	if title == "" {
+		return nil, errors.New("FPS 题目缺少标题")
+	}
+	pkg := newProblemPackage(title)
+	problem := &pkg.problem
+
+	timeLimit, err := fpsTimeLimit(item.TimeLimit)
+	if err != nil {
+		return nil, fmt.Errorf("题目 %s 时间限制格式错误", title)
+	}
+	memoryLimit, err := fpsMemoryLimit(item.MemoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("题目 %s 内存限制格式错误", title)
+	}
+	problem.TimeLimit = timeLimit
+	problem.MemoryLimit = memoryLimit
+
+	if source := strings.TrimSpace(item.Source); source != "" {
+		problem.Source = &source
+	}
+	pkg.statement = &dto.Statement{
+		Description: strings.TrimSpace(item.Description),
+		Input:       strings.TrimSpace(item.Input),
+		Output:      strings.TrimSpace(item.Output),
+		Hint:        strings.TrimSpace(item.Hint),
+	}
+	if len(item.Images) > 0 {
+		pkg.warn("FPS 内嵌图片未导入，题面中的 %d 张图片需手动处理", len(item.Images))
+	}
+
+	if len(item.SampleInput) != len(item.SampleOutput) {
+		return nil, fmt.Errorf("题目 %s 的样例输入与输出数量不一致", title)
+	}
+	if len(item.TestInput) != len(item.TestOutput) {
+		return nil, fmt.Errorf("题目 %s 的测试输入与输出数量不一致", title)
+	}
+	for i := range item.SampleInput {
+		pair := pkg.addCase(fmt.Sprintf("sample%d", i+1),
+			bytesOpener([]byte(item.SampleInput[i])), bytesOpener([]byte(item.SampleOutput[i])), -1)
+		pair.sample = true
+	}
+	for i := range item.TestInput {
+		pkg.addCase(fmt.Sprintf("%d", i+1),
+			bytesOpener([]byte(item.TestInput[i])), bytesOpener([]byte(item.TestOutput[i])), -1)
+	}
+
+	// 只支持 testlib 风格的特殊判题程序
+	for _, spj := range item.SpecialJudge {
+		code := strings.TrimSpace(spj.Code)
+		if code == "" {
+			continue
+		}
+		if !strings.Contains(code, "testlib.h") {
+			pkg.warn("特殊判题程序不是 testlib 风格，未导入")
+			break
+		}
+		checkerLanguage := fpsLanguage(spj.Language)
+		problem.CheckerType = checker.TypeSpecial
+		problem.CheckerCode = &spj.Code
+		problem.CheckerLanguage = &checkerLanguage
+		break
+	}
+
+	for _, program := range item.Template {
+		name := fpsLanguage(program.Language)
+		template := pkg.templates[name]
+		template.Template = program.Code
+		pkg.templates[name] = template
+	}
+	for _, program := range item.Prepend {
+		name := fpsLanguage(program.Language)
+		template := pkg.templates[name]
+		template.Prepend = program.Code
+		pkg.templates[name] = template
+	}
+	for _, program := range item.Append {
+		name := fpsLanguage(program.Language)
+		template := pkg.templates[name]
+		template.Append = program.Code
+		pkg.templates[name] = template
+	}
+	problem.UseTemplate = len(pkg.templates) > 0
+	return pkg, nil
+}
+
+// fpsTimeLimit 时间限制转换为毫秒，默认单位为秒
+func fpsTimeLimit(limit fpsLimit) (int, error) {
+	value := strings.TrimSpace(limit.Value)
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if strings.ToLower(strings.TrimSpace(limit.Unit)) == "ms" {
+		return int(math.Round(number)), nil
+	}
+	return int(math.Round(number * 1000)), nil
+}
+
+// fpsMemoryLimit 内存限制转换为 MB，默认单位为 MB
+func fpsMemoryLimit(limit fpsLimit) (int, error) {
+	value := strings.TrimSpace(limit.Value)
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.ToLower(strings.TrimSpace(limit.Unit)) {
+	case "kb":
+		number /= 1024
+	case "gb":
+		number *= 1024
+	}
+	return int(math.Ceil(number)), nil
+}
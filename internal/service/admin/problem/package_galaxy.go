@@ -0,0 +1,367 @@
+package problem
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/judge/checker"
+	"galaxy/internal/judge/language"
+	"galaxy/internal/judge/testdata"
+	"galaxy/internal/models"
+	shareProblem "galaxy/internal/service/share/problem"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+const (
+	galaxyManifest  = "problem.yaml"
+	galaxyStatement = "statement.md"
+	galaxyTestDir   = "tests"
+)
+
+// PackageExport 待导出的题目包，题目数据在 Write 时才读取
+type PackageExport struct {
+	Filename string
+	write    func(w io.Writer) error
+}
+
+// Write 将题目包写入 w
+func (e *PackageExport) Write(w io.Writer) error {
+	return e.write(w)
+}
+
+// parseGalaxy 解析本系统导出的题目包
+func parseGalaxy(archive *zip.Reader) (*problemPackage, error) {
+	files, ok := openPackage(archive, galaxyManifest)
+	if !ok {
+		return nil, errors.New("缺少 problem.yaml")
+	}
+	data, err := files.read(galaxyManifest)
+	if err != nil {
+		return nil, err
+	}
+	var manifest dto.PackageManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("problem.yaml 格式错误: %w", err)
+	}
+	if strings.TrimSpace(manifest.Title) == "" {
+		return nil, errors.New("problem.yaml 缺少标题")
+	}
+
+	pkg := newProblemPackage(strings.TrimSpace(manifest.Title))
+	problem := &pkg.problem
+	if manifest.Source != "" {
+		problem.Source = &manifest.Source
+	}
+	if manifest.URL != "" {
+		problem.URL = &manifest.URL
+	}
+	problem.TimeLimit = manifest.TimeLimit
+	problem.MemoryLimit = manifest.MemoryLimit
+	if manifest.Difficulty > 0 {
+		problem.Difficulty = manifest.Difficulty
+	}
+	if manifest.Threshold > 0 {
+		problem.Threshold = manifest.Threshold
+	}
+	if statement, err := files.read(galaxyStatement); err == nil {
+		description := string(statement)
+		problem.Description = &description
+	}
+	pkg.statement = manifest.Statement
+	pkg.languages = manifest.Languages
+	pkg.tags = manifest.Tags
+
+	if manifest.Checker.Type != "" {
+		problem.CheckerType = manifest.Checker.Type
+	}
+	problem.CheckerAbsEps = manifest.Checker.AbsEps
+	problem.CheckerRelEps = manifest.Checker.RelEps
+	if problem.CheckerType == checker.TypeSpecial {
+		code, err := files.read(manifest.Checker.Source)
+		if err != nil {
+			return nil, err
+		}
+		source, checkerLanguage := string(code), manifest.Checker.Language
+		problem.CheckerCode = &source
+		problem.CheckerLanguage = &checkerLanguage
+	}
+	if manifest.Interactor != nil {
+		code, err := files.read(manifest.Interactor.Source)
+		if err != nil {
+			return nil, err
+		}
+		source, interactorLanguage := string(code), manifest.Interactor.Language
+		problem.IsInteractive = true
+		problem.InteractorCode = &source
+		problem.InteractorLanguage = &interactorLanguage
+	}
+
+	for name, template := range manifest.Templates {
+		pkg.templates[name] = template
+	}
+	problem.UseTemplate = manifest.UseTemplate
+
+	for _, subtask := range manifest.Subtasks {
+		pkg.subtasks = append(pkg.subtasks, packageSubtask{
+			title:        subtask.Title,
+			score:        subtask.Score,
+			aggregation:  subtask.Aggregation,
+			dependencies: subtask.Dependencies,
+		})
+	}
+	for _, item := range manifest.Cases {
+		if !validCaseName(item.Name) {
+			return nil, fmt.Errorf("用例名称 %q 无效，不能包含路径分隔符或 ..", item.Name)
+		}
+		input, err := files.opener(path.Join(galaxyTestDir, item.Name+".in"))
+		if err != nil {
+			return nil, err
+		}
+		output, err := files.opener(path.Join(galaxyTestDir, item.Name+".out"))
+		if err != nil {
+			return nil, err
+		}
+		subtask := -1
+		if item.Subtask != nil {
+			subtask = *item.Subtask
+		}
+		pair := pkg.addCase(item.Name, input, output, subtask)
+		pair.score = item.Score
+		pair.sample = item.Sample
+	}
+	return pkg, nil
+}
+
+// ExportPackage 读取题目配置，生成可重新导入的 Galaxy 题目包
+func (s *PackageServiceImpl) ExportPackage(problemID string) (*PackageExport, error) {
+	var problem models.ProblemInfo
+	if err := s.db.Where("id = ?", problemID).First(&problem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("题目不存在")
+		}
+		return nil, err
+	}
+
+	var tags []string
+	if err := s.db.Model(&models.ContentTag{}).
+		Joins("JOIN problem_tag_rel ON problem_tag_rel.tag_id = content_tag.id AND problem_tag_rel.delete_time IS NULL").
+		Where("problem_tag_rel.problem_id = ?", problem.ID).
+		Order("content_tag.name ASC").
+		Pluck("content_tag.name", &tags).Error; err != nil {
+		return nil, err
+	}
+
+	var subtasks []models.ProblemSubtask
+	if err := s.db.Where("problem_id = ?", problem.ID).
+		Order("sort ASC, create_time ASC").
+		Find(&subtasks).Error; err != nil {
+		return nil, err
+	}
+
+	var testCases []models.ProblemTestCase
+	if err := s.db.Where("problem_id = ?", problem.ID).
+		Order("create_time ASC, id ASC").
+		Find(&testCases).Error; err != nil {
+		return nil, err
+	}
+
+	manifest, err := buildManifest(&problem, tags, subtasks, testCases)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := problem.ID
+	if problem.DisplayID != nil && *problem.DisplayID != "" {
+		filename = *problem.DisplayID
+	}
+	return &PackageExport{
+		Filename: filename + ".zip",
+		write: func(w io.Writer) error {
+			return s.writePackage(w, &problem, manifest, testCases)
+		},
+	}, nil
+}
+
+// buildManifest 生成 problem.yaml，子任务与依赖改为按下标引用
+func buildManifest(problem *models.ProblemInfo, tags []string, subtasks []models.ProblemSubtask, testCases []models.ProblemTestCase) (*dto.PackageManifest, error) {
+	manifest := &dto.PackageManifest{
+		TimeLimit:   problem.TimeLimit,
+		MemoryLimit: problem.MemoryLimit,
+		Difficulty:  problem.Difficulty,
+		Threshold:   problem.Threshold,
+		Tags:        tags,
+		UseTemplate: problem.UseTemplate,
+		Checker: dto.ManifestChecker{
+			Type:   problem.CheckerType,
+			AbsEps: problem.CheckerAbsEps,
+			RelEps: problem.CheckerRelEps,
+		},
+	}
+	if problem.Title != nil {
+		manifest.Title = *problem.Title
+	}
+	if problem.Source != nil {
+		manifest.Source = *problem.Source
+	}
+	if problem.URL != nil {
+		manifest.URL = *problem.URL
+	}
+
+	statement, err := shareProblem.ParseStatement(problem.Statement)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Statement = statement
+
+	languages, err := language.ParseList(problem.Languages)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Languages = languages
+
+	if len(problem.CodeTemplate) > 0 && string(problem.CodeTemplate) != "null" {
+		if err := json.Unmarshal(problem.CodeTemplate, &manifest.Templates); err != nil {
+			return nil, fmt.Errorf("代码模板格式错误: %w", err)
+		}
+	}
+
+	if problem.CheckerType == checker.TypeSpecial && problem.CheckerLanguage != nil {
+		manifest.Checker.Language = *problem.CheckerLanguage
+		manifest.Checker.Source = "checker" + sourceExt(*problem.CheckerLanguage)
+	}
+	if problem.IsInteractive && problem.InteractorLanguage != nil {
+		manifest.Interactor = &dto.ManifestProgram{
+			Language: *problem.InteractorLanguage,
+			Source:   "interactor" + sourceExt(*problem.InteractorLanguage),
+		}
+	}
+
+	indexes := make(map[string]int, len(subtasks))
+	for i := range subtasks {
+		indexes[subtasks[i].ID] = i
+	}
+	for i := range subtasks {
+		subtask := &subtasks[i]
+		item := dto.ManifestSubtask{
+			Score:       subtask.Score,
+			Aggregation: subtask.Aggregation,
+		}
+		if subtask.Title != nil {
+			item.Title = *subtask.Title
+		}
+		var dependencies []string
+		if len(subtask.Dependencies) > 0 && string(subtask.Dependencies) != "null" {
+			if err := json.Unmarshal(subtask.Dependencies, &dependencies); err != nil {
+				return nil, fmt.Errorf("子任务 %s 依赖配置错误: %w", subtask.ID, err)
+			}
+		}
+		for _, dep := range dependencies {
+			if index, ok := indexes[dep]; ok {
+				item.Dependencies = append(item.Dependencies, index)
+			}
+		}
+		manifest.Subtasks = append(manifest.Subtasks, item)
+	}
+
+	for i := range testCases {
+		testCase := &testCases[i]
+		item := dto.ManifestCase{
+			Name:   exportCaseName(testCase, i),
+			Score:  testCase.Score,
+			Sample: testCase.IsSample,
+		}
+		if testCase.SubtaskID != nil {
+			if index, ok := indexes[*testCase.SubtaskID]; ok {
+				item.Subtask = &index
+			}
+		}
+		manifest.Cases = append(manifest.Cases, item)
+	}
+	return manifest, nil
+}
+
+// exportCaseName 用例在包内的文件名，缺少或含路径分隔符时按序号命名
+func exportCaseName(testCase *models.ProblemTestCase, index int) string {
+	if testCase.CaseSign != nil && *testCase.CaseSign != "" && !strings.ContainsAny(*testCase.CaseSign, `/\`) {
+		return *testCase.CaseSign
+	}
+	return fmt.Sprintf("%d", index+1)
+}
+
+// writePackage 写入 problem.yaml、题面、判题程序与测试数据
+func (s *PackageServiceImpl) writePackage(w io.Writer, problem *models.ProblemInfo, manifest *dto.PackageManifest, testCases []models.ProblemTestCase) error {
+	archive := zip.NewWriter(w)
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	writeFile := func(name string, content []byte) error {
+		file, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(content)
+		return err
+	}
+	if err := writeFile(galaxyManifest, data); err != nil {
+		return err
+	}
+	if problem.Description != nil {
+		if err := writeFile(galaxyStatement, []byte(*problem.Description)); err != nil {
+			return err
+		}
+	}
+	if manifest.Checker.Source != "" && problem.CheckerCode != nil {
+		if err := writeFile(manifest.Checker.Source, []byte(*problem.CheckerCode)); err != nil {
+			return err
+		}
+	}
+	if manifest.Interactor != nil && problem.InteractorCode != nil {
+		if err := writeFile(manifest.Interactor.Source, []byte(*problem.InteractorCode)); err != nil {
+			return err
+		}
+	}
+
+	for i := range testCases {
+		testCase := &testCases[i]
+		name := path.Join(galaxyTestDir, manifest.Cases[i].Name)
+		if err := s.writeCaseFile(archive, name+".in", testCase.InputFilePath, testCase.InputData); err != nil {
+			return err
+		}
+		if err := s.writeCaseFile(archive, name+".out", testCase.OutputFilePath, testCase.ExpectedOutput); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// writeCaseFile 写入一个用例文件，优先读取数据目录中的文件，否则使用表中保存的数据
+func (s *PackageServiceImpl) writeCaseFile(archive *zip.Writer, name string, filePath, data *string) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	if filePath == nil || *filePath == "" {
+		if data != nil {
+			_, err = io.WriteString(file, *data)
+		}
+		return err
+	}
+
+	src, err := os.Open(testdata.Path(s.cfg.DataRoot, *filePath))
+	if err != nil {
+		return fmt.Errorf("读取用例文件失败: %w", err)
+	}
+	defer src.Close()
+	_, err = io.Copy(file, src)
+	return err
+}
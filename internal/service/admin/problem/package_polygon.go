@@ -0,0 +1,339 @@
+package problem
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/judge/checker"
+	"galaxy/internal/models"
+	"path"
+	"strings"
+)
+
+const polygonManifest = "problem.xml"
+
+// polygonProblem Polygon 完整包中的 problem.xml
+type polygonProblem struct {
+	ShortName  string             `xml:"short-name,attr"`
+	Names      []polygonName      `xml:"names>name"`
+	Statements []polygonStatement `xml:"statements>statement"`
+	Testsets   []polygonTestset   `xml:"judging>testset"`
+	Checker    *polygonProgram    `xml:"assets>checker"`
+	Interactor *polygonProgram    `xml:"assets>interactor"`
+	Tags       []polygonTag       `xml:"tags>tag"`
+}
+
+type polygonName struct {
+	Language string `xml:"language,attr"`
+	Value    string `xml:"value,attr"`
+}
+
+type polygonStatement struct {
+	Language string `xml:"language,attr"`
+	Path     string `xml:"path,attr"`
+	Type     string `xml:"type,attr"`
+}
+
+type polygonTestset struct {
+	Name          string         `xml:"name,attr"`
+	TimeLimit     int            `xml:"time-limit"`   // 毫秒
+	MemoryLimit   int64          `xml:"memory-limit"` // 字节
+	InputPattern  string         `xml:"input-path-pattern"`
+	AnswerPattern string         `xml:"answer-path-pattern"`
+	Tests         []polygonTest  `xml:"tests>test"`
+	Groups        []polygonGroup `xml:"groups>group"`
+}
+
+type polygonTest struct {
+	Sample bool    `xml:"sample,attr"`
+	Points float64 `xml:"points,attr"`
+	Group  string  `xml:"group,attr"`
+}
+
+type polygonGroup struct {
+	Name         string              `xml:"name,attr"`
+	Points       float64             `xml:"points,attr"`
+	PointsPolicy string              `xml:"points-policy,attr"`
+	Dependencies []polygonDependency `xml:"dependencies>dependency"`
+}
+
+type polygonDependency struct {
+	Group string `xml:"group,attr"`
+}
+
+type polygonProgram struct {
+	Name   string        `xml:"name,attr"`
+	Source polygonSource `xml:"source"`
+}
+
+type polygonSource struct {
+	Path string `xml:"path,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type polygonTag struct {
+	Value string `xml:"value,attr"`
+}
+
+// polygonProperties statements/<language>/problem-properties.json 中的题面各部分
+type polygonProperties struct {
+	Name   string `json:"name"`
+	Legend string `json:"legend"`
+	Input  string `json:"input"`
+	Output string `json:"output"`
+	Notes  string `json:"notes"`
+}
+
+// polygonStatementLanguages 题面语言优先级
+var polygonStatementLanguages = []string{"chinese", "english"}
+
+// polygonCheckers Polygon 标准比较器到内置比较方式的映射，未列出的标准比较器按特殊判题导入
+var polygonCheckers = map[string]struct {
+	checkerType string
+	eps         float64
+}{
+	"wcmp":  {checker.TypeTrailing, 0},
+	"lcmp":  {checker.TypeTrailing, 0},
+	"ncmp":  {checker.TypeTrailing, 0},
+	"fcmp":  {checker.TypeTrailing, 0},
+	"uncmp": {checker.TypeUnordered, 0},
+	"rcmp4": {checker.TypeFloat, 1e-4},
+	"rcmp6": {checker.TypeFloat, 1e-6},
+	"rcmp9": {checker.TypeFloat, 1e-9},
+}
+
+// polygonLanguage Polygon 源码类型（如 cpp.g++17）到系统语言名的映射
+func polygonLanguage(sourceType string) string {
+	switch {
+	case strings.HasPrefix(sourceType, "cpp."):
+		if strings.Contains(sourceType, "20") || strings.Contains(sourceType, "23") {
+			return "C++20"
+		}
+		return "C++17"
+	case strings.HasPrefix(sourceType, "c."):
+		return "C"
+	case strings.HasPrefix(sourceType, "java"):
+		return "Java"
+	case strings.HasPrefix(sourceType, "python.3"), strings.HasPrefix(sourceType, "python.pypy3"):
+		return "Python3"
+	default:
+		return sourceType
+	}
+}
+
+// parsePolygon 解析 Polygon 完整包（含生成好的测试数据）
+func parsePolygon(archive *zip.Reader) (*problemPackage, error) {
+	files, ok := openPackage(archive, polygonManifest)
+	if !ok {
+		return nil, errors.New("缺少 problem.xml")
+	}
+	data, err := files.read(polygonManifest)
+	if err != nil {
+		return nil, err
+	}
+	var manifest polygonProblem
+	if err := xml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("problem.xml 格式错误: %w", err)
+	}
+	if len(manifest.Testsets) == 0 {
+		return nil, errors.New("problem.xml 中没有测试集")
+	}
+	// 只导入 tests 测试集
+	testset := &manifest.Testsets[0]
+	for i := range manifest.Testsets {
+		if manifest.Testsets[i].Name == "tests" {
+			testset = &manifest.Testsets[i]
+			break
+		}
+	}
+
+	statementLanguage := polygonStatementLanguage(&manifest)
+	title := polygonTitle(&manifest, statementLanguage)
+	if title == "" {
+		return nil, errors.New("题目缺少名称")
+	}
+
+	pkg := newProblemPackage(title)
+	problem := &pkg.problem
+	problem.TimeLimit = testset.TimeLimit
+	problem.MemoryLimit = int((testset.MemoryLimit + 1<<20 - 1) >> 20)
+	pkg.statement = readPolygonStatement(files, statementLanguage)
+	if pkg.statement.Empty() {
+		pkg.warn("未找到题面，需手动填写")
+	}
+	for _, tag := range manifest.Tags {
+		pkg.tags = append(pkg.tags, tag.Value)
+	}
+
+	if err := polygonTests(pkg, files, testset); err != nil {
+		return nil, err
+	}
+	if err := polygonChecker(pkg, files, manifest.Checker); err != nil {
+		return nil, err
+	}
+	if manifest.Interactor != nil {
+		code, err := files.read(manifest.Interactor.Source.Path)
+		if err != nil {
+			return nil, err
+		}
+		source, interactorLanguage := string(code), polygonLanguage(manifest.Interactor.Source.Type)
+		problem.IsInteractive = true
+		problem.InteractorCode = &source
+		problem.InteractorLanguage = &interactorLanguage
+	}
+	return pkg, nil
+}
+
+// polygonStatementLanguage 按优先级选择题面语言
+func polygonStatementLanguage(manifest *polygonProblem) string {
+	for _, preferred := range polygonStatementLanguages {
+		for _, statement := range manifest.Statements {
+			if statement.Language == preferred {
+				return preferred
+			}
+		}
+	}
+	if len(manifest.Statements) > 0 {
+		return manifest.Statements[0].Language
+	}
+	if len(manifest.Names) > 0 {
+		return manifest.Names[0].Language
+	}
+	return ""
+}
+
+// polygonTitle 优先使用题面语言的名称，其次为第一个名称与短名称
+func polygonTitle(manifest *polygonProblem, statementLanguage string) string {
+	for _, name := range manifest.Names {
+		if name.Language == statementLanguage && name.Value != "" {
+			return name.Value
+		}
+	}
+	if len(manifest.Names) > 0 && manifest.Names[0].Value != "" {
+		return manifest.Names[0].Value
+	}
+	return manifest.ShortName
+}
+
+// readPolygonStatement 优先读取 problem-properties.json，否则读取 statement-sections 中的各部分
+func readPolygonStatement(files packageFiles, statementLanguage string) *dto.Statement {
+	if statementLanguage == "" {
+		return &dto.Statement{}
+	}
+	if data, err := files.read(path.Join("statements", statementLanguage, "problem-properties.json")); err == nil {
+		var properties polygonProperties
+		if json.Unmarshal(data, &properties) == nil {
+			return &dto.Statement{
+				Description: strings.TrimSpace(properties.Legend),
+				Input:       strings.TrimSpace(properties.Input),
+				Output:      strings.TrimSpace(properties.Output),
+				Hint:        strings.TrimSpace(properties.Notes),
+			}
+		}
+	}
+
+	section := func(name string) string {
+		data, err := files.read(path.Join("statement-sections", statementLanguage, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+	return &dto.Statement{
+		Description: section("legend.tex"),
+		Input:       section("input.tex"),
+		Output:      section("output.tex"),
+		Hint:        section("notes.tex"),
+	}
+}
+
+// polygonTests 读取测试点与分组，complete-group 分组按最差用例计分，each-test 分组按用例求和
+func polygonTests(pkg *problemPackage, files packageFiles, testset *polygonTestset) error {
+	if testset.InputPattern == "" || testset.AnswerPattern == "" {
+		return errors.New("problem.xml 缺少测试数据路径")
+	}
+
+	groups := make(map[string]int, len(testset.Groups))
+	for _, group := range testset.Groups {
+		aggregation := models.SubtaskAggregationMin
+		if group.PointsPolicy == "each-test" {
+			aggregation = models.SubtaskAggregationSum
+		}
+		subtask := packageSubtask{
+			title:       group.Name,
+			score:       group.Points,
+			aggregation: aggregation,
+		}
+		for _, dep := range group.Dependencies {
+			if index, ok := groups[dep.Group]; ok {
+				subtask.dependencies = append(subtask.dependencies, index)
+			} else {
+				pkg.warn("分组 %s 的依赖 %s 不在其之前，已忽略", group.Name, dep.Group)
+			}
+		}
+		groups[group.Name] = len(pkg.subtasks)
+		pkg.subtasks = append(pkg.subtasks, subtask)
+	}
+
+	for i, test := range testset.Tests {
+		input, err := files.opener(fmt.Sprintf(testset.InputPattern, i+1))
+		if err != nil {
+			return fmt.Errorf("%w，请上传包含测试数据的完整包", err)
+		}
+		answer, err := files.opener(fmt.Sprintf(testset.AnswerPattern, i+1))
+		if err != nil {
+			return fmt.Errorf("%w，请上传包含测试数据的完整包", err)
+		}
+
+		subtask := -1
+		if test.Group != "" {
+			index, ok := groups[test.Group]
+			if !ok {
+				// 未声明的分组按各用例得分求和
+				index = len(pkg.subtasks)
+				groups[test.Group] = index
+				pkg.subtasks = append(pkg.subtasks, packageSubtask{
+					title:       test.Group,
+					aggregation: models.SubtaskAggregationSum,
+				})
+			}
+			subtask = index
+		}
+		pair := pkg.addCase("", input, answer, subtask)
+		pair.sample = test.Sample
+		pair.score = test.Points
+	}
+	return nil
+}
+
+// polygonChecker 标准比较器映射为内置比较方式，其余按 testlib 特殊判题导入
+func polygonChecker(pkg *problemPackage, files packageFiles, program *polygonProgram) error {
+	if program == nil {
+		return nil
+	}
+	problem := &pkg.problem
+	name := strings.TrimSuffix(strings.TrimPrefix(program.Name, "std::"), ".cpp")
+	if strings.HasPrefix(program.Name, "std::") {
+		if builtin, ok := polygonCheckers[name]; ok {
+			problem.CheckerType = builtin.checkerType
+			if builtin.eps > 0 {
+				problem.CheckerAbsEps = builtin.eps
+				problem.CheckerRelEps = builtin.eps
+			}
+			return nil
+		}
+	}
+
+	code, err := files.read(program.Source.Path)
+	if err != nil {
+		return err
+	}
+	source, checkerLanguage := string(code), polygonLanguage(program.Source.Type)
+	problem.CheckerType = checker.TypeSpecial
+	problem.CheckerCode = &source
+	problem.CheckerLanguage = &checkerLanguage
+	return nil
+}
@@ -0,0 +1,464 @@
+package problem
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/judge/checker"
+	"galaxy/internal/judge/language"
+	"galaxy/internal/models"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/utils"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const maxPackageFileSize = 1 << 20 // 题目包中元数据、题面与程序源码的大小上限
+
+// PackageService 题目包导入导出服务接口定义
+type PackageService interface {
+	// ImportPackage 导入 FPS / Polygon / Galaxy 题目包，format 为空时按内容识别
+	ImportPackage(format string, r io.ReaderAt, size int64, operator string) ([]dto.ImportResult, error)
+	// ExportPackage 准备导出 Galaxy 题目包，可被 ImportPackage 重新导入
+	ExportPackage(problemID string) (*PackageExport, error)
+}
+
+// PackageServiceImpl 题目包服务实现
+type PackageServiceImpl struct {
+	db  *gorm.DB
+	cfg config.JudgeConfig
+}
+
+// 确保 PackageServiceImpl 实现 PackageService 接口
+var _ PackageService = (*PackageServiceImpl)(nil)
+
+func NewPackageService() PackageService {
+	return &PackageServiceImpl{
+		db:  database.GetDB(),
+		cfg: config.Get().Judge,
+	}
+}
+
+// problemPackage 从题目包解析出的单个题目
+type problemPackage struct {
+	problem   models.ProblemInfo
+	statement *dto.Statement
+	languages []string
+	tags      []string
+	templates map[string]dto.CodeTemplate
+	subtasks  []packageSubtask
+	cases     []packageCase
+	warnings  []string
+}
+
+// packageSubtask 子任务，依赖为前面子任务的下标
+type packageSubtask struct {
+	title        string
+	score        float64
+	aggregation  string
+	dependencies []int
+}
+
+// packageCase 用例及其所属子任务下标，-1 表示不分组
+type packageCase struct {
+	pair    *casePair
+	subtask int
+}
+
+// newProblemPackage 按表默认值初始化题目
+func newProblemPackage(title string) *problemPackage {
+	return &problemPackage{
+		problem: models.ProblemInfo{
+			CategoryID:    "0",
+			Title:         &title,
+			Difficulty:    1,
+			Threshold:     0.5,
+			IsVisible:     true,
+			CheckerType:   checker.TypeTrailing,
+			CheckerAbsEps: defaultCheckerEps,
+			CheckerRelEps: defaultCheckerEps,
+		},
+		templates: make(map[string]dto.CodeTemplate),
+	}
+}
+
+func (p *problemPackage) warn(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+// addCase 追加一个用例，name 为空时按序号命名
+func (p *problemPackage) addCase(name string, input, output opener, subtask int) *casePair {
+	if name == "" {
+		name = fmt.Sprintf("%d", len(p.cases)+1)
+	}
+	pair := &casePair{name: name, input: input, output: output}
+	p.cases = append(p.cases, packageCase{pair: pair, subtask: subtask})
+	return pair
+}
+
+// ImportPackage 导入题目包
+func (s *PackageServiceImpl) ImportPackage(format string, r io.ReaderAt, size int64, operator string) ([]dto.ImportResult, error) {
+	// 非压缩包时只能是单个 FPS 文件
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		archive = nil
+	}
+	if format == "" {
+		format = detectFormat(archive)
+	}
+
+	var packages []*problemPackage
+	switch format {
+	case dto.PackageFormatFPS:
+		if archive == nil {
+			packages, err = parseFPS(io.NewSectionReader(r, 0, size))
+		} else {
+			packages, err = parseFPSArchive(archive)
+		}
+	case dto.PackageFormatPolygon, dto.PackageFormatGalaxy:
+		if archive == nil {
+			return nil, errors.New("无法解析压缩包")
+		}
+		var pkg *problemPackage
+		if format == dto.PackageFormatPolygon {
+			pkg, err = parsePolygon(archive)
+		} else {
+			pkg, err = parseGalaxy(archive)
+		}
+		packages = []*problemPackage{pkg}
+	default:
+		return nil, errors.New("无法识别的题目包格式")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(packages) == 0 {
+		return nil, errors.New("题目包中没有题目")
+	}
+
+	// 逐题保存，已保存的题目不因后续题目失败而回滚
+	results := make([]dto.ImportResult, 0, len(packages))
+	for _, pkg := range packages {
+		result, err := s.save(pkg, operator)
+		if err != nil {
+			return nil, fmt.Errorf("导入题目 %s 失败: %w", *pkg.problem.Title, err)
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// detectFormat 按压缩包内容识别格式：problem.yaml 为 Galaxy，problem.xml 为 Polygon，其余 XML 为 FPS
+func detectFormat(archive *zip.Reader) string {
+	if archive == nil {
+		return dto.PackageFormatFPS
+	}
+	if _, ok := openPackage(archive, galaxyManifest); ok {
+		return dto.PackageFormatGalaxy
+	}
+	if _, ok := openPackage(archive, polygonManifest); ok {
+		return dto.PackageFormatPolygon
+	}
+	for _, file := range archive.File {
+		if strings.EqualFold(path.Ext(file.Name), ".xml") {
+			return dto.PackageFormatFPS
+		}
+	}
+	return ""
+}
+
+// save 写入测试数据并在事务中创建题目、标签、子任务与用例
+func (s *PackageServiceImpl) save(pkg *problemPackage, operator string) (*dto.ImportResult, error) {
+	s.normalize(pkg)
+
+	problem := &pkg.problem
+	problem.ID = utils.GenerateID()
+	problem.CreateUser = &operator
+	if pkg.statement != nil && !pkg.statement.Empty() {
+		data, err := json.Marshal(pkg.statement)
+		if err != nil {
+			return nil, err
+		}
+		problem.Statement = data
+	}
+	if len(pkg.languages) > 0 {
+		data, err := json.Marshal(pkg.languages)
+		if err != nil {
+			return nil, err
+		}
+		problem.Languages = data
+	}
+	if len(pkg.templates) > 0 {
+		data, err := json.Marshal(pkg.templates)
+		if err != nil {
+			return nil, err
+		}
+		problem.CodeTemplate = data
+	} else {
+		problem.UseTemplate = false
+	}
+
+	subtasks := make([]models.ProblemSubtask, len(pkg.subtasks))
+	for i, item := range pkg.subtasks {
+		subtask := &subtasks[i]
+		subtask.ID = utils.GenerateID()
+		subtask.ProblemID = problem.ID
+		subtask.Score = item.score
+		subtask.Aggregation = item.aggregation
+		subtask.Sort = i
+		subtask.CreateUser = &operator
+		if item.title != "" {
+			title := item.title
+			subtask.Title = &title
+		}
+		var dependencies []string
+		for _, dep := range item.dependencies {
+			dependencies = append(dependencies, subtasks[dep].ID)
+		}
+		if len(dependencies) > 0 {
+			data, err := json.Marshal(dependencies)
+			if err != nil {
+				return nil, err
+			}
+			subtask.Dependencies = data
+		}
+	}
+
+	pairs := make([]*casePair, 0, len(pkg.cases))
+	for _, item := range pkg.cases {
+		if item.subtask >= 0 {
+			subtaskID := subtasks[item.subtask].ID
+			item.pair.subtaskID = &subtaskID
+		}
+		pairs = append(pairs, item.pair)
+	}
+
+	problemDir := filepath.Join(s.cfg.DataRoot, problem.ID)
+	testCases, err := saveCaseFiles(s.cfg, pairs, problem.ID, utils.GenerateID())
+	if err != nil {
+		os.RemoveAll(problemDir)
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := shareProblem.AllocateDisplayID(tx, problem); err != nil {
+			return err
+		}
+		// 显式写入全部字段，避免 is_visible 等零值被表默认值覆盖
+		if err := tx.Select("*").Create(problem).Error; err != nil {
+			return err
+		}
+		if err := saveTagNames(tx, problem.ID, pkg.tags); err != nil {
+			return err
+		}
+		for i := range subtasks {
+			if err := tx.Create(&subtasks[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range testCases {
+			testCases[i].CreateUser = &operator
+			if err := tx.Create(&testCases[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		os.RemoveAll(problemDir)
+		return nil, err
+	}
+
+	warnings := pkg.warnings
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return &dto.ImportResult{
+		ProblemID: problem.ID,
+		DisplayID: problem.DisplayID,
+		Title:     *problem.Title,
+		CaseCount: len(testCases),
+		Warnings:  warnings,
+	}, nil
+}
+
+// normalize 去掉不支持的语言、无效的子任务引用，判题程序无法使用时回退到默认比较方式
+func (s *PackageServiceImpl) normalize(pkg *problemPackage) {
+	registry := language.Get()
+	supported := func(name string) bool {
+		_, ok := registry.Get(name)
+		return ok
+	}
+
+	languages := pkg.languages[:0]
+	for _, name := range pkg.languages {
+		if supported(name) {
+			languages = append(languages, name)
+		} else {
+			pkg.warn("忽略不支持的语言 %s", name)
+		}
+	}
+	pkg.languages = languages
+
+	for name := range pkg.templates {
+		if !supported(name) {
+			pkg.warn("忽略不支持语言 %s 的代码模板", name)
+			delete(pkg.templates, name)
+		}
+	}
+
+	problem := &pkg.problem
+	if problem.CheckerType == checker.TypeSpecial {
+		if problem.CheckerCode == nil || *problem.CheckerCode == "" ||
+			problem.CheckerLanguage == nil || !supported(*problem.CheckerLanguage) {
+			pkg.warn("特殊判题程序不可用，改为默认比较方式")
+			problem.CheckerType = checker.TypeTrailing
+			problem.CheckerCode = nil
+			problem.CheckerLanguage = nil
+		}
+	} else if _, err := checker.New(problem); err != nil {
+		pkg.warn("%s，改为默认比较方式", err.Error())
+		problem.CheckerType = checker.TypeTrailing
+	}
+	if problem.IsInteractive && (problem.InteractorCode == nil || *problem.InteractorCode == "" ||
+		problem.InteractorLanguage == nil || !supported(*problem.InteractorLanguage)) {
+		pkg.warn("交互程序不可用，已导入为非交互题")
+		problem.IsInteractive = false
+		problem.InteractorCode = nil
+		problem.InteractorLanguage = nil
+	}
+	if problem.CheckerAbsEps <= 0 {
+		problem.CheckerAbsEps = defaultCheckerEps
+	}
+	if problem.CheckerRelEps <= 0 {
+		problem.CheckerRelEps = defaultCheckerEps
+	}
+
+	// 依赖只能指向前面的子任务
+	for i := range pkg.subtasks {
+		subtask := &pkg.subtasks[i]
+		if subtask.aggregation != models.SubtaskAggregationSum {
+			subtask.aggregation = models.SubtaskAggregationMin
+		}
+		dependencies := subtask.dependencies[:0]
+		for _, dep := range subtask.dependencies {
+			if dep >= 0 && dep < i {
+				dependencies = append(dependencies, dep)
+			} else {
+				pkg.warn("忽略子任务 %d 的无效依赖 %d", i+1, dep+1)
+			}
+		}
+		subtask.dependencies = dependencies
+	}
+	for i := range pkg.cases {
+		item := &pkg.cases[i]
+		if item.subtask >= len(pkg.subtasks) {
+			pkg.warn("用例 %s 所属的子任务不存在，已改为不分组", item.pair.name)
+			item.subtask = -1
+		}
+	}
+}
+
+// saveTagNames 按名称关联题目标签，不存在的标签自动创建
+func saveTagNames(tx *gorm.DB, problemID string, names []string) error {
+	tagIDs := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		var tag models.ContentTag
+		err := tx.Where("name = ? AND module_type = ?", name, models.ModuleTypeProblem).First(&tag).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			tag = models.ContentTag{
+				Name:       name,
+				IsVisible:  true,
+				ModuleType: models.ModuleTypeProblem,
+				ParentID:   "0",
+			}
+			err = tx.Create(&tag).Error
+		}
+		if err != nil {
+			return err
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+	return saveTags(tx, problemID, tagIDs)
+}
+
+// packageFiles 压缩包内的文件，路径相对于标志文件所在目录
+type packageFiles map[string]*zip.File
+
+// openPackage 以最浅的 marker 文件所在目录为根目录，兼容整体打包在一个目录下的压缩包
+func openPackage(archive *zip.Reader, marker string) (packageFiles, bool) {
+	root, found := "", false
+	for _, file := range archive.File {
+		if path.Base(file.Name) != marker || strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+		dir := strings.TrimSuffix(file.Name, marker)
+		if !found || len(dir) < len(root) {
+			root, found = dir, true
+		}
+	}
+	if !found {
+		return nil, false
+	}
+
+	files := make(packageFiles)
+	for _, file := range archive.File {
+		if !file.FileInfo().IsDir() && strings.HasPrefix(file.Name, root) {
+			files[strings.TrimPrefix(file.Name, root)] = file
+		}
+	}
+	return files, true
+}
+
+// read 读取包内的小文件
+func (f packageFiles) read(name string) ([]byte, error) {
+	file, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("缺少文件 %s", name)
+	}
+	return readZipFile(file)
+}
+
+// opener 返回包内文件的打开函数
+func (f packageFiles) opener(name string) (opener, error) {
+	file, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("缺少文件 %s", name)
+	}
+	return file.Open, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > maxPackageFileSize {
+		return nil, fmt.Errorf("文件 %s 过大", file.Name)
+	}
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return io.ReadAll(io.LimitReader(src, maxPackageFileSize))
+}
+
+// sourceExt 语言源文件的扩展名
+func sourceExt(name string) string {
+	if lang, ok := language.Get().Get(name); ok && lang.SourceFile != "" {
+		if ext := path.Ext(lang.SourceFile); ext != "" {
+			return ext
+		}
+	}
+	return ".txt"
+}
@@ -0,0 +1,333 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/judge/checker"
+	"galaxy/internal/judge/language"
+	"galaxy/internal/judge/template"
+	"galaxy/internal/models"
+	problemQuery "galaxy/internal/query/problem"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/database"
+	"galaxy/pkg/query"
+
+	"gorm.io/gorm"
+)
+
+// defaultCheckerEps 浮点比较默认误差，与表默认值一致
+const defaultCheckerEps = 1e-6
+
+// ProblemService 题目管理服务接口定义
+type ProblemService interface {
+	CreateProblem(req *dto.ProblemRequest, operator string) (*dto.ProblemAdminDetail, error)
+	GetProblem(id string) (*dto.ProblemAdminDetail, error)
+	UpdateProblem(id string, req *dto.ProblemRequest, operator string) (*dto.ProblemAdminDetail, error)
+	DeleteProblem(id, operator string) error
+	ProblemList(req *problemQuery.ProblemQueryRequest) (*query.PaginationResponse[dto.ProblemItem], error)
+}
+
+// ProblemServiceImpl 题目管理服务实现
+type ProblemServiceImpl struct {
+	db *gorm.DB
+}
+
+// 确保 ProblemServiceImpl 实现 ProblemService 接口
+var _ ProblemService = (*ProblemServiceImpl)(nil)
+
+func NewProblemService() ProblemService {
+	return &ProblemServiceImpl{
+		db: database.GetDB(),
+	}
+}
+
+// CreateProblem 创建题目，返回与详情相同的结构
+func (s *ProblemServiceImpl) CreateProblem(req *dto.ProblemRequest, operator string) (*dto.ProblemAdminDetail, error) {
+	problem := &models.ProblemInfo{}
+	if err := applyRequest(problem, req); err != nil {
+		return nil, err
+	}
+	problem.CreateUser = &operator
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := assignDisplayID(tx, problem); err != nil {
+			return err
+		}
+		// 显式写入全部字段，避免 is_visible 等零值被表默认值覆盖
+		if err := tx.Select("*").Create(problem).Error; err != nil {
+			return err
+		}
+		return saveTags(tx, problem.ID, req.TagIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProblem(problem.ID)
+}
+
+// GetProblem 获取题目详情，包含全部用例与子任务
+func (s *ProblemServiceImpl) GetProblem(id string) (*dto.ProblemAdminDetail, error) {
+	problem, err := s.findProblem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := shareProblem.TagIDs(s.db, []string{problem.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	var testCases []models.ProblemTestCase
+	if err := s.db.Where("problem_id = ?", problem.ID).
+		Order("create_time ASC, id ASC").
+		Find(&testCases).Error; err != nil {
+		return nil, err
+	}
+
+	subtasks, err := problemSubtasks(s.db, problem.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := shareProblem.StatsMap(s.db, []string{problem.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := tags[problem.ID]
+	if tagIDs == nil {
+		tagIDs = []string{}
+	}
+	detail := dto.NewProblemAdminDetail(problem)
+	detail.TagIDs = tagIDs
+	detail.TestCases = toTestCaseItems(testCases)
+	detail.Subtasks = toSubtaskItems(subtasks)
+	detail.Stats = dto.NewProblemStatsItem(stats[problem.ID])
+	return detail, nil
+}
+
+// UpdateProblem 更新题目，标签整体替换，返回与详情相同的结构
+func (s *ProblemServiceImpl) UpdateProblem(id string, req *dto.ProblemRequest, operator string) (*dto.ProblemAdminDetail, error) {
+	problem, err := s.findProblem(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyRequest(problem, req); err != nil {
+		return nil, err
+	}
+	problem.UpdateUser = &operator
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := assignDisplayID(tx, problem); err != nil {
+			return err
+		}
+		if err := tx.Save(problem).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("problem_id = ?", problem.ID).Delete(&models.ProblemTagRel{}).Error; err != nil {
+			return err
+		}
+		return saveTags(tx, problem.ID, req.TagIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProblem(problem.ID)
+}
+
+// DeleteProblem 删除题目及其标签、用例与子任务
+func (s *ProblemServiceImpl) DeleteProblem(id, operator string) error {
+	problem, err := s.findProblem(id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(problem).Update("delete_user", operator).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(problem).Error; err != nil {
+			return err
+		}
+		for _, table := range []interface{}{&models.ProblemTagRel{}, &models.ProblemTestCase{}, &models.ProblemSubtask{}} {
+			if err := tx.Where("problem_id = ?", problem.ID).Delete(table).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ProblemList 获取题目列表，包含未公开与隐藏的题目
+func (s *ProblemServiceImpl) ProblemList(req *problemQuery.ProblemQueryRequest) (*query.PaginationResponse[dto.ProblemItem], error) {
+	db := s.db
+	if req != nil && req.IsPublic != nil {
+		db = db.Where("is_public = ?", *req.IsPublic)
+	}
+	return shareProblem.ProblemList(db, req)
+}
+
+func (s *ProblemServiceImpl) findProblem(id string) (*models.ProblemInfo, error) {
+	if id == "" {
+		return nil, errors.New("题目ID不能为空")
+	}
+
+	var problem models.ProblemInfo
+	if err := s.db.Where("id = ?", id).First(&problem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("题目不存在")
+		}
+		return nil, err
+	}
+	return &problem, nil
+}
+
+// applyRequest 校验请求并写入题目
+func applyRequest(problem *models.ProblemInfo, req *dto.ProblemRequest) error {
+	registry := language.Get()
+	for _, name := range req.Languages {
+		if _, ok := registry.Get(name); !ok {
+			return fmt.Errorf("不支持的语言: %s", name)
+		}
+	}
+	for _, name := range []*string{req.CheckerLanguage, req.InteractorLanguage} {
+		if name == nil || *name == "" {
+			continue
+		}
+		if _, ok := registry.Get(*name); !ok {
+			return fmt.Errorf("不支持的语言: %s", *name)
+		}
+	}
+
+	checkerType := req.CheckerType
+	if checkerType == "" {
+		checkerType = checker.TypeTrailing
+	}
+	if checkerType == checker.TypeSpecial {
+		if req.CheckerCode == nil || *req.CheckerCode == "" {
+			return errors.New("特殊判题程序源码不能为空")
+		}
+	} else if _, err := checker.New(&models.ProblemInfo{CheckerType: checkerType}); err != nil {
+		return err
+	}
+	if req.IsInteractive && (req.InteractorCode == nil || *req.InteractorCode == "") {
+		return errors.New("交互程序源码不能为空")
+	}
+
+	templates, err := template.Parse(req.CodeTemplate)
+	if err != nil {
+		return err
+	}
+	for name := range templates {
+		if _, ok := registry.Get(name); !ok {
+			return fmt.Errorf("代码模板中的语言不支持: %s", name)
+		}
+	}
+	if req.UseTemplate && len(templates) == 0 {
+		return errors.New("启用代码模板时至少需要配置一个语言的模板")
+	}
+
+	var statement []byte
+	if req.Statement != nil && !req.Statement.Empty() {
+		data, err := json.Marshal(req.Statement)
+		if err != nil {
+			return err
+		}
+		statement = data
+	}
+
+	var languages []byte
+	if len(req.Languages) > 0 {
+		data, err := json.Marshal(req.Languages)
+		if err != nil {
+			return err
+		}
+		languages = data
+	}
+
+	// 未指定展示编号时保留原编号，保证编号稳定
+	if req.DisplayID != nil && *req.DisplayID != "" {
+		problem.DisplayID = req.DisplayID
+	}
+	problem.CategoryID = req.CategoryID
+	if problem.CategoryID == "" {
+		problem.CategoryID = "0"
+	}
+	problem.Title = &req.Title
+	problem.Source = req.Source
+	problem.URL = req.URL
+	problem.TimeLimit = req.TimeLimit
+	problem.MemoryLimit = req.MemoryLimit
+	problem.Description = req.Description
+	problem.Statement = statement
+	problem.Languages = languages
+	problem.Difficulty = req.Difficulty
+	if problem.Difficulty <= 0 {
+		problem.Difficulty = 1
+	}
+	problem.Threshold = req.Threshold
+	problem.UseTemplate = req.UseTemplate
+	problem.CodeTemplate = req.CodeTemplate
+	problem.IsPublic = req.IsPublic
+	problem.IsVisible = req.IsVisible
+	problem.UseAI = req.UseAI
+	problem.CheckerType = checkerType
+	problem.CheckerAbsEps = req.CheckerAbsEps
+	if problem.CheckerAbsEps <= 0 {
+		problem.CheckerAbsEps = defaultCheckerEps
+	}
+	problem.CheckerRelEps = req.CheckerRelEps
+	if problem.CheckerRelEps <= 0 {
+		problem.CheckerRelEps = defaultCheckerEps
+	}
+	problem.CheckerCode = req.CheckerCode
+	problem.CheckerLanguage = req.CheckerLanguage
+	problem.IsInteractive = req.IsInteractive
+	problem.InteractorCode = req.InteractorCode
+	problem.InteractorLanguage = req.InteractorLanguage
+	return nil
+}
+
+// assignDisplayID 校验手动指定的展示编号，未指定时自动分配
+func assignDisplayID(tx *gorm.DB, problem *models.ProblemInfo) error {
+	if problem.DisplayID == nil || *problem.DisplayID == "" {
+		return shareProblem.AllocateDisplayID(tx, problem)
+	}
+	return checkDisplayID(tx, problem)
+}
+
+// checkDisplayID 展示编号格式合法且不能与其他题目重复
+func checkDisplayID(tx *gorm.DB, problem *models.ProblemInfo) error {
+	if !shareProblem.ValidDisplayID(*problem.DisplayID) {
+		return errors.New("题目编号只能包含字母、数字、下划线与连字符，且不超过32个字符")
+	}
+
+	db := tx.Model(&models.ProblemInfo{}).Where("display_id = ?", *problem.DisplayID)
+	if problem.ID != "" {
+		db = db.Where("id <> ?", problem.ID)
+	}
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("题目编号已存在")
+	}
+	return nil
+}
+
+// saveTags 关联题目标签，标签须为已存在的题目标签
+func saveTags(tx *gorm.DB, problemID string, tagIDs []string) error {
+	tagIDs = shareProblem.UniqueIDs(tagIDs)
+	if err := shareProblem.CheckTags(tx, tagIDs); err != nil {
+		return err
+	}
+	for _, tagID := range tagIDs {
+		if err := tx.Create(&models.ProblemTagRel{ProblemID: problemID, TagID: tagID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/models"
+	"galaxy/pkg/database"
+	"galaxy/pkg/utils"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// SubtaskService 题目子任务管理服务接口定义
+type SubtaskService interface {
+	SubtaskList(problemID string) ([]dto.SubtaskItem, error)
+	CreateSubtask(problemID string, req *dto.SubtaskRequest, operator string) (*dto.SubtaskItem, error)
+	UpdateSubtask(problemID, id string, req *dto.SubtaskRequest, operator string) (*dto.SubtaskItem, error)
+	// DeleteSubtask 删除子任务，所属用例改为不分组，其他子任务对它的依赖一并移除
+	DeleteSubtask(problemID, id, operator string) error
+}
+
+// SubtaskServiceImpl 题目子任务管理服务实现
+type SubtaskServiceImpl struct {
+	db *gorm.DB
+}
+
+// 确保 SubtaskServiceImpl 实现 SubtaskService 接口
+var _ SubtaskService = (*SubtaskServiceImpl)(nil)
+
+func NewSubtaskService() SubtaskService {
+	return &SubtaskServiceImpl{
+		db: database.GetDB(),
+	}
+}
+
+// SubtaskList 获取题目的子任务列表
+func (s *SubtaskServiceImpl) SubtaskList(problemID string) ([]dto.SubtaskItem, error) {
+	subtasks, err := problemSubtasks(s.db, problemID)
+	if err != nil {
+		return nil, err
+	}
+	return toSubtaskItems(subtasks), nil
+}
+
+// CreateSubtask 创建子任务
+func (s *SubtaskServiceImpl) CreateSubtask(problemID string, req *dto.SubtaskRequest, operator string) (*dto.SubtaskItem, error) {
+	var count int64
+	if err := s.db.Model(&models.ProblemInfo{}).Where("id = ?", problemID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("题目不存在")
+	}
+
+	subtask := &models.ProblemSubtask{ProblemID: problemID}
+	subtask.ID = utils.GenerateID()
+	subtask.CreateUser = &operator
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := applySubtaskRequest(tx, subtask, req); err != nil {
+			return err
+		}
+		return tx.Create(subtask).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	item := dto.NewSubtaskItem(subtask)
+	return &item, nil
+}
+
+// UpdateSubtask 更新子任务
+func (s *SubtaskServiceImpl) UpdateSubtask(problemID, id string, req *dto.SubtaskRequest, operator string) (*dto.SubtaskItem, error) {
+	subtask, err := s.findSubtask(problemID, id)
+	if err != nil {
+		return nil, err
+	}
+	subtask.UpdateUser = &operator
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := applySubtaskRequest(tx, subtask, req); err != nil {
+			return err
+		}
+		return tx.Save(subtask).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	item := dto.NewSubtaskItem(subtask)
+	return &item, nil
+}
+
+// DeleteSubtask 删除子任务
+func (s *SubtaskServiceImpl) DeleteSubtask(problemID, id, operator string) error {
+	subtask, err := s.findSubtask(problemID, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(subtask).Update("delete_user", operator).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(subtask).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ProblemTestCase{}).
+			Where("problem_id = ? AND subtask_id = ?", problemID, id).
+			Update("subtask_id", nil).Error; err != nil {
+			return err
+		}
+
+		others, err := problemSubtasks(tx, problemID)
+		if err != nil {
+			return err
+		}
+		for i := range others {
+			dependencies, err := parseDependencies(&others[i])
+			if err != nil {
+				return err
+			}
+			kept := dependencies[:0]
+			for _, dep := range dependencies {
+				if dep != id {
+					kept = append(kept, dep)
+				}
+			}
+			if len(kept) == len(dependencies) {
+				continue
+			}
+			data, err := marshalDependencies(kept)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&others[i]).Update("dependencies", data).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *SubtaskServiceImpl) findSubtask(problemID, id string) (*models.ProblemSubtask, error) {
+	var subtask models.ProblemSubtask
+	if err := s.db.Where("id = ? AND problem_id = ?", id, problemID).First(&subtask).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("子任务不存在")
+		}
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+// applySubtaskRequest 校验请求并写入子任务，依赖须为同一题目的其他子任务且不能成环
+func applySubtaskRequest(tx *gorm.DB, subtask *models.ProblemSubtask, req *dto.SubtaskRequest) error {
+	siblings, err := problemSubtasks(tx, subtask.ProblemID)
+	if err != nil {
+		return err
+	}
+	graph := make(map[string][]string, len(siblings)+1)
+	for i := range siblings {
+		if siblings[i].ID == subtask.ID {
+			continue
+		}
+		if graph[siblings[i].ID], err = parseDependencies(&siblings[i]); err != nil {
+			return err
+		}
+	}
+
+	dependencies := make([]string, 0, len(req.Dependencies))
+	seen := make(map[string]bool, len(req.Dependencies))
+	for _, dep := range req.Dependencies {
+		if seen[dep] {
+			continue
+		}
+		seen[dep] = true
+		if dep == subtask.ID {
+			return errors.New("子任务不能依赖自身")
+		}
+		if _, ok := graph[dep]; !ok {
+			return fmt.Errorf("依赖的子任务 %s 不存在", dep)
+		}
+		dependencies = append(dependencies, dep)
+	}
+	graph[subtask.ID] = dependencies
+	if dependsOn(graph, dependencies, subtask.ID, make(map[string]bool)) {
+		return errors.New("子任务依赖不能成环")
+	}
+
+	data, err := marshalDependencies(dependencies)
+	if err != nil {
+		return err
+	}
+	subtask.Title = req.Title
+	subtask.Score = req.Score
+	subtask.Aggregation = req.Aggregation
+	if subtask.Aggregation == "" {
+		subtask.Aggregation = models.SubtaskAggregationMin
+	}
+	subtask.Dependencies = data
+	subtask.Sort = req.Sort
+	return nil
+}
+
+// dependsOn 从 from 出发沿依赖能否到达 target
+func dependsOn(graph map[string][]string, from []string, target string, visited map[string]bool) bool {
+	for _, id := range from {
+		if id == target {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		if dependsOn(graph, graph[id], target, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// problemSubtasks 题目的全部子任务，按排序值排列
+func problemSubtasks(db *gorm.DB, problemID string) ([]models.ProblemSubtask, error) {
+	var subtasks []models.ProblemSubtask
+	err := db.Where("problem_id = ?", problemID).
+		Order("sort ASC, create_time ASC").
+		Find(&subtasks).Error
+	return subtasks, err
+}
+
+func parseDependencies(subtask *models.ProblemSubtask) ([]string, error) {
+	var dependencies []string
+	if len(subtask.Dependencies) == 0 || string(subtask.Dependencies) == "null" {
+		return dependencies, nil
+	}
+	if err := json.Unmarshal(subtask.Dependencies, &dependencies); err != nil {
+		return nil, fmt.Errorf("子任务 %s 依赖配置错误: %w", subtask.ID, err)
+	}
+	return dependencies, nil
+}
+
+// marshalDependencies 没有依赖时写入空值，与导入题目包一致
+func marshalDependencies(dependencies []string) (datatypes.JSON, error) {
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(dependencies)
+}
+
+func toSubtaskItems(subtasks []models.ProblemSubtask) []dto.SubtaskItem {
+	items := make([]dto.SubtaskItem, 0, len(subtasks))
+	for i := range subtasks {
+		items = append(items, dto.NewSubtaskItem(&subtasks[i]))
+	}
+	return items
+}
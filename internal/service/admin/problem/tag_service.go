@@ -0,0 +1,252 @@
+package problem
+
+import (
+	"errors"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/models"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/database"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// TagService 题目标签管理服务接口定义
+type TagService interface {
+	CreateTag(req *dto.TagRequest, operator string) (*dto.TagItem, error)
+	UpdateTag(id string, req *dto.TagRequest, operator string) (*dto.TagItem, error)
+	DeleteTag(id, operator string) error
+	// TagTree 全部标签（含隐藏标签）的树形结构及题目数
+	TagTree() ([]*dto.TagNode, error)
+	// AssignTags 批量追加、移除或替换题目标签
+	AssignTags(req *dto.AssignTagsRequest, operator string) (*dto.AssignTagsResult, error)
+}
+
+// TagServiceImpl 题目标签管理服务实现
+type TagServiceImpl struct {
+	db *gorm.DB
+}
+
+// 确保 TagServiceImpl 实现 TagService 接口
+var _ TagService = (*TagServiceImpl)(nil)
+
+func NewTagService() TagService {
+	return &TagServiceImpl{
+		db: database.GetDB(),
+	}
+}
+
+// CreateTag 创建标签，题目标签名称不可重复
+func (s *TagServiceImpl) CreateTag(req *dto.TagRequest, operator string) (*dto.TagItem, error) {
+	tag := &models.ContentTag{
+		IsVisible:  true,
+		ModuleType: models.ModuleTypeProblem,
+	}
+	if err := s.applyTagRequest(tag, req); err != nil {
+		return nil, err
+	}
+	tag.CreateUser = &operator
+
+	// 显式写入全部字段，避免 is_visible 为 false 时被表默认值覆盖
+	if err := s.db.Select("*").Create(tag).Error; err != nil {
+		return nil, err
+	}
+	return dto.NewTagItem(tag), nil
+}
+
+// UpdateTag 更新标签，可移动到其他父标签下
+func (s *TagServiceImpl) UpdateTag(id string, req *dto.TagRequest, operator string) (*dto.TagItem, error) {
+	tag, err := s.findTag(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyTagRequest(tag, req); err != nil {
+		return nil, err
+	}
+	tag.UpdateUser = &operator
+
+	if err := s.db.Save(tag).Error; err != nil {
+		return nil, err
+	}
+	return dto.NewTagItem(tag), nil
+}
+
+// DeleteTag 删除标签及其题目关联，存在子标签时不允许删除
+func (s *TagServiceImpl) DeleteTag(id, operator string) error {
+	tag, err := s.findTag(id)
+	if err != nil {
+		return err
+	}
+
+	var children int64
+	if err := s.db.Model(&models.ContentTag{}).
+		Where("parent_id = ? AND module_type = ?", tag.ID, models.ModuleTypeProblem).
+		Count(&children).Error; err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("请先删除或移动子标签")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(tag).Update("delete_user", operator).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(tag).Error; err != nil {
+			return err
+		}
+		return tx.Where("tag_id = ?", tag.ID).Delete(&models.ProblemTagRel{}).Error
+	})
+}
+
+// TagTree 全部标签的树形结构，题目数包含未公开题目
+func (s *TagServiceImpl) TagTree() ([]*dto.TagNode, error) {
+	forest, err := shareProblem.LoadTags(s.db)
+	if err != nil {
+		return nil, err
+	}
+	problemIDs, err := shareProblem.TagProblemIDs(s.db, s.db.Model(&models.ProblemInfo{}).Select("id"))
+	if err != nil {
+		return nil, err
+	}
+	return shareProblem.TagTree(forest, problemIDs, nil, false), nil
+}
+
+// AssignTags 批量设置题目标签，已存在的关联不会重复创建
+func (s *TagServiceImpl) AssignTags(req *dto.AssignTagsRequest, operator string) (*dto.AssignTagsResult, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = dto.TagAssignAdd
+	}
+	if mode != dto.TagAssignAdd && mode != dto.TagAssignRemove && mode != dto.TagAssignReplace {
+		return nil, errors.New("不支持的设置方式: " + mode)
+	}
+	if mode != dto.TagAssignReplace && len(req.TagIDs) == 0 {
+		return nil, errors.New("标签不能为空")
+	}
+	if err := shareProblem.CheckTags(s.db, req.TagIDs); err != nil {
+		return nil, err
+	}
+
+	var problemIDs []string
+	if err := s.db.Model(&models.ProblemInfo{}).
+		Where("id IN ?", req.ProblemIDs).
+		Pluck("id", &problemIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(problemIDs) != len(shareProblem.UniqueIDs(req.ProblemIDs)) {
+		return nil, errors.New("题目不存在")
+	}
+
+	result := &dto.AssignTagsResult{ProblemCount: len(problemIDs)}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if mode != dto.TagAssignAdd {
+			db := tx.Where("problem_id IN ?", problemIDs)
+			if mode == dto.TagAssignRemove {
+				db = db.Where("tag_id IN ?", req.TagIDs)
+			}
+			removed := db.Delete(&models.ProblemTagRel{})
+			if removed.Error != nil {
+				return removed.Error
+			}
+			result.Removed = int(removed.RowsAffected)
+		}
+		if mode == dto.TagAssignRemove {
+			return nil
+		}
+
+		var existing []models.ProblemTagRel
+		if err := tx.Select("problem_id", "tag_id").
+			Where("problem_id IN ?", problemIDs).
+			Find(&existing).Error; err != nil {
+			return err
+		}
+		linked := make(map[[2]string]bool, len(existing))
+		for _, rel := range existing {
+			linked[[2]string{rel.ProblemID, rel.TagID}] = true
+		}
+
+		var rels []models.ProblemTagRel
+		for _, problemID := range problemIDs {
+			for _, tagID := range shareProblem.UniqueIDs(req.TagIDs) {
+				if linked[[2]string{problemID, tagID}] {
+					continue
+				}
+				rels = append(rels, models.ProblemTagRel{ProblemID: problemID, TagID: tagID})
+				rels[len(rels)-1].CreateUser = &operator
+			}
+		}
+		if len(rels) == 0 {
+			return nil
+		}
+		result.Added = len(rels)
+		return tx.CreateInBatches(rels, 200).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *TagServiceImpl) findTag(id string) (*models.ContentTag, error) {
+	if id == "" {
+		return nil, errors.New("标签ID不能为空")
+	}
+
+	var tag models.ContentTag
+	if err := s.db.Where("id = ? AND module_type = ?", id, models.ModuleTypeProblem).First(&tag).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("标签不存在")
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// applyTagRequest 校验名称与父标签并写入标签
+func (s *TagServiceImpl) applyTagRequest(tag *models.ContentTag, req *dto.TagRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("标签名称不能为空")
+	}
+	parentID := req.ParentID
+	if parentID == "" {
+		parentID = shareProblem.RootTagID
+	}
+
+	db := s.db.Model(&models.ContentTag{}).Where("name = ? AND module_type = ?", name, models.ModuleTypeProblem)
+	if tag.ID != "" {
+		db = db.Where("id <> ?", tag.ID)
+	}
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("标签名称已存在")
+	}
+
+	if parentID != shareProblem.RootTagID {
+		forest, err := shareProblem.LoadTags(s.db)
+		if err != nil {
+			return err
+		}
+		if _, ok := forest.Get(parentID); !ok {
+			return errors.New("父标签不存在")
+		}
+		if tag.ID != "" {
+			for _, id := range forest.Subtree(tag.ID) {
+				if id == parentID {
+					return errors.New("不能将标签移动到自身或其子标签下")
+				}
+			}
+		}
+	}
+
+	tag.Name = name
+	tag.ParentID = parentID
+	if req.IsVisible != nil {
+		tag.IsVisible = *req.IsVisible
+	}
+	return nil
+}
@@ -0,0 +1,383 @@
+package problem
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/judge/testdata"
+	"galaxy/internal/models"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/utils"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+const defaultMaxDataSize = 1024 // 默认测试数据大小上限（MB）
+
+// TestCaseService 测试用例服务接口定义
+type TestCaseService interface {
+	// UploadTestCases 上传 N.in / N.out 成对的压缩包，替换题目现有的全部用例
+	UploadTestCases(problemID string, r io.ReaderAt, size int64, operator string) ([]dto.TestCaseItem, error)
+	TestCaseList(problemID string) ([]dto.TestCaseItem, error)
+}
+
+// TestCaseServiceImpl 测试用例服务实现
+type TestCaseServiceImpl struct {
+	db  *gorm.DB
+	cfg config.JudgeConfig
+}
+
+// 确保 TestCaseServiceImpl 实现 TestCaseService 接口
+var _ TestCaseService = (*TestCaseServiceImpl)(nil)
+
+func NewTestCaseService() TestCaseService {
+	return &TestCaseServiceImpl{
+		db:  database.GetDB(),
+		cfg: config.Get().Judge,
+	}
+}
+
+// opener 打开用例文件内容
+type opener func() (io.ReadCloser, error)
+
+// casePair 一对用例文件及其配置
+type casePair struct {
+	name      string
+	input     opener
+	output    opener
+	score     float64
+	sample    bool
+	subtaskID *string
+}
+
+// bytesOpener 内存中的用例数据
+func bytesOpener(data []byte) opener {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+// UploadTestCases 上传测试数据
+func (s *TestCaseServiceImpl) UploadTestCases(problemID string, r io.ReaderAt, size int64, operator string) ([]dto.TestCaseItem, error) {
+	var count int64
+	if err := s.db.Model(&models.ProblemInfo{}).Where("id = ?", problemID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("题目不存在")
+	}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.New("无法解析压缩包")
+	}
+	pairs, err := parseArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	subtasks, err := problemSubtasks(s.db, problemID)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveSubtasks(pairs, subtasks); err != nil {
+		return nil, err
+	}
+
+	// 每次上传写入新的版本目录，数据库更新成功后再删除旧目录
+	version := utils.GenerateID()
+	problemDir := filepath.Join(s.cfg.DataRoot, problemID)
+	versionDir := filepath.Join(problemDir, version)
+
+	testCases, err := saveCaseFiles(s.cfg, pairs, problemID, version)
+	if err != nil {
+		os.RemoveAll(versionDir)
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("problem_id = ?", problemID).Delete(&models.ProblemTestCase{}).Error; err != nil {
+			return err
+		}
+		for i := range testCases {
+			testCases[i].CreateUser = &operator
+			if err := tx.Create(&testCases[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		os.RemoveAll(versionDir)
+		return nil, err
+	}
+
+	s.removeOldVersions(problemID, problemDir, version)
+	return toTestCaseItems(testCases), nil
+}
+
+// resolveSubtasks 将 config.yaml 中的子任务引用解析为题目子任务的 ID，可填写子任务 ID 或标题
+func resolveSubtasks(pairs []*casePair, subtasks []models.ProblemSubtask) error {
+	ids := make(map[string]string, len(subtasks)*2)
+	ambiguous := make(map[string]bool)
+	for i := range subtasks {
+		if title := subtasks[i].Title; title != nil && *title != "" {
+			if _, ok := ids[*title]; ok {
+				ambiguous[*title] = true
+			}
+			ids[*title] = subtasks[i].ID
+		}
+	}
+	// ID 优先于同名的标题
+	for i := range subtasks {
+		ids[subtasks[i].ID] = subtasks[i].ID
+		delete(ambiguous, subtasks[i].ID)
+	}
+
+	for _, pair := range pairs {
+		if pair.subtaskID == nil {
+			continue
+		}
+		if ambiguous[*pair.subtaskID] {
+			return fmt.Errorf("用例 %s 的子任务标题 %s 不唯一，请填写子任务 ID", pair.name, *pair.subtaskID)
+		}
+		id, ok := ids[*pair.subtaskID]
+		if !ok {
+			return fmt.Errorf("用例 %s 的子任务 %s 不存在", pair.name, *pair.subtaskID)
+		}
+		pair.subtaskID = &id
+	}
+	return nil
+}
+
+// removeOldVersions 删除题目数据目录下除 version 外的旧版本。
+// 判题开始时已读取旧版本的用例记录，题目仍有未完成的提交时保留旧版本，留待下次上传时清理
+func (s *TestCaseServiceImpl) removeOldVersions(problemID, problemDir, version string) {
+	var pending int64
+	if err := s.db.Model(&models.JudgeSubmit{}).
+		Where("problem_id = ? AND is_finish = ?", problemID, false).
+		Count(&pending).Error; err != nil || pending > 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(problemDir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != version {
+			os.RemoveAll(filepath.Join(problemDir, entry.Name()))
+		}
+	}
+}
+
+// validCaseName 用例名称会作为数据文件名，不能包含路径分隔符或 ..
+func validCaseName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\`) && !strings.Contains(name, "..")
+}
+
+// saveCaseFiles 将用例文件写入数据目录，返回待写入的用例记录
+func saveCaseFiles(cfg config.JudgeConfig, pairs []*casePair, problemID, version string) ([]models.ProblemTestCase, error) {
+	maxDataSize := cfg.MaxDataSize
+	if maxDataSize <= 0 {
+		maxDataSize = defaultMaxDataSize
+	}
+	remain := int64(maxDataSize) << 20
+
+	save := func(open opener, name string) (string, int64, string, error) {
+		relPath := filepath.Join(problemID, version, name)
+		src, err := open()
+		if err != nil {
+			return "", 0, "", err
+		}
+		defer src.Close()
+
+		size, hash, err := testdata.Save(testdata.Path(cfg.DataRoot, relPath), src, remain)
+		if err != nil {
+			return "", 0, "", err
+		}
+		remain -= size
+		return relPath, size, hash, nil
+	}
+
+	// 用例按顺序插入，创建时间依次递增以保证判题顺序
+	base := time.Now()
+	testCases := make([]models.ProblemTestCase, 0, len(pairs))
+	for i, pair := range pairs {
+		if !validCaseName(pair.name) {
+			return nil, fmt.Errorf("用例名称 %q 无效", pair.name)
+		}
+		inputPath, inputSize, inputHash, err := save(pair.input, pair.name+".in")
+		if err != nil {
+			return nil, fmt.Errorf("保存用例 %s 失败: %w", pair.name, err)
+		}
+		outputPath, outputSize, outputHash, err := save(pair.output, pair.name+".out")
+		if err != nil {
+			return nil, fmt.Errorf("保存用例 %s 失败: %w", pair.name, err)
+		}
+
+		testCase := models.ProblemTestCase{
+			ProblemID:      problemID,
+			CaseSign:       &pair.name,
+			InputFilePath:  &inputPath,
+			InputFileSize:  inputSize,
+			InputFileHash:  &inputHash,
+			OutputFilePath: &outputPath,
+			OutputFileSize: outputSize,
+			OutputFileHash: &outputHash,
+			IsSample:       pair.sample,
+			Score:          pair.score,
+			SubtaskID:      pair.subtaskID,
+		}
+		testCase.CreatedAt = base.Add(time.Duration(i) * time.Microsecond)
+		testCases = append(testCases, testCase)
+	}
+	return testCases, nil
+}
+
+// parseArchive 按文件名配对 .in 与 .out（或 .ans），并应用可选的 config.yaml
+func parseArchive(archive *zip.Reader) ([]*casePair, error) {
+	caseConfig := &dto.TestCaseConfig{}
+	pairs := make(map[string]*casePair)
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") {
+			continue
+		}
+		base := path.Base(file.Name)
+		if strings.HasPrefix(base, ".") {
+			continue
+		}
+
+		if base == "config.yaml" || base == "config.yml" {
+			if err := readConfig(file, caseConfig); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		ext := path.Ext(base)
+		name := strings.TrimSuffix(base, ext)
+		pair := pairs[name]
+		if pair == nil {
+			pair = &casePair{name: name}
+		}
+		switch ext {
+		case ".in":
+			if pair.input != nil {
+				return nil, fmt.Errorf("用例 %s 的输入文件重复", name)
+			}
+			pair.input = file.Open
+		case ".out", ".ans":
+			if pair.output != nil {
+				return nil, fmt.Errorf("用例 %s 的输出文件重复", name)
+			}
+			pair.output = file.Open
+		default:
+			continue
+		}
+		pairs[name] = pair
+	}
+
+	if len(pairs) == 0 {
+		return nil, errors.New("压缩包中没有测试用例")
+	}
+
+	result := make([]*casePair, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair.input == nil {
+			return nil, fmt.Errorf("用例 %s 缺少输入文件", pair.name)
+		}
+		if pair.output == nil {
+			return nil, fmt.Errorf("用例 %s 缺少输出文件", pair.name)
+		}
+		if option, ok := caseConfig.Cases[pair.name]; ok {
+			pair.score = option.Score
+			pair.sample = option.Sample
+			if option.Subtask != "" {
+				subtask := option.Subtask
+				pair.subtaskID = &subtask
+			}
+		}
+		result = append(result, pair)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return lessCaseName(result[i].name, result[j].name)
+	})
+	return result, nil
+}
+
+func readConfig(file *zip.File, caseConfig *dto.TestCaseConfig) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, 1<<20))
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(data, caseConfig); err != nil {
+		return fmt.Errorf("config.yaml 格式错误: %w", err)
+	}
+	return nil
+}
+
+// lessCaseName 数字用例名按数值排序，其余按字符串排序
+func lessCaseName(a, b string) bool {
+	x, errX := strconv.Atoi(a)
+	y, errY := strconv.Atoi(b)
+	switch {
+	case errX == nil && errY == nil:
+		return x < y
+	case errX == nil:
+		return true
+	case errY == nil:
+		return false
+	default:
+		return a < b
+	}
+}
+
+// TestCaseList 获取题目的用例列表
+func (s *TestCaseServiceImpl) TestCaseList(problemID string) ([]dto.TestCaseItem, error) {
+	var testCases []models.ProblemTestCase
+	if err := s.db.Where("problem_id = ?", problemID).
+		Order("create_time ASC, id ASC").
+		Find(&testCases).Error; err != nil {
+		return nil, err
+	}
+	return toTestCaseItems(testCases), nil
+}
+
+func toTestCaseItems(testCases []models.ProblemTestCase) []dto.TestCaseItem {
+	items := make([]dto.TestCaseItem, 0, len(testCases))
+	for i := range testCases {
+		testCase := &testCases[i]
+		items = append(items, dto.TestCaseItem{
+			ID:             testCase.ID,
+			CaseSign:       testCase.CaseSign,
+			InputFilePath:  testCase.InputFilePath,
+			InputFileSize:  testCase.InputFileSize,
+			InputFileHash:  testCase.InputFileHash,
+			OutputFilePath: testCase.OutputFilePath,
+			OutputFileSize: testCase.OutputFileSize,
+			OutputFileHash: testCase.OutputFileHash,
+			IsSample:       testCase.IsSample,
+			Score:          testCase.Score,
+			SubtaskID:      testCase.SubtaskID,
+		})
+	}
+	return items
+}
@@ -0,0 +1,70 @@
+package contest
+
+import (
+	"errors"
+	"galaxy/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPasswordRequired = errors.New("该竞赛需要输入密码")
+	ErrNotInvited       = errors.New("该竞赛仅限受邀用户参加")
+	ErrWrongPassword    = errors.New("竞赛密码错误")
+)
+
+// AccessDenied err 是否为无权访问竞赛的错误
+func AccessDenied(err error) bool {
+	return errors.Is(err, ErrPasswordRequired) || errors.Is(err, ErrNotInvited)
+}
+
+// HasPassword 竞赛是否设置了密码
+func HasPassword(contest *models.ContestInfo) bool {
+	return contest.Password != nil && *contest.Password != ""
+}
+
+// CheckAccess 校验用户能否访问竞赛的题目、提交与榜单：公开竞赛无需授权，非公开竞赛须持有授权。
+// 设置了密码的竞赛可凭密码获得授权，未设置密码的为私有竞赛，仅限管理员邀请的用户
+func CheckAccess(db *gorm.DB, contest *models.ContestInfo, userID string) error {
+	if contest.IsPublic {
+		return nil
+	}
+	if userID != "" {
+		var count int64
+		if err := db.Model(&models.ContestAuth{}).
+			Where("contest_id = ? AND user_id = ? AND is_auth = ?", contest.ID, userID, true).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+	}
+	if HasPassword(contest) {
+		return ErrPasswordRequired
+	}
+	return ErrNotInvited
+}
+
+// DeniedContests 用户无权访问的非公开竞赛 ID 的子查询，未登录时为全部非公开竞赛
+func DeniedContests(db *gorm.DB, userID string) *gorm.DB {
+	db = db.Session(&gorm.Session{NewDB: true})
+	granted := db.Model(&models.ContestAuth{}).
+		Select("contest_id").
+		Where("user_id = ? AND is_auth = ?", userID, true)
+	return db.Model(&models.ContestInfo{}).
+		Select("id").
+		Where("is_public = ? AND id NOT IN (?)", false, granted)
+}
+
+// Grant 授权用户访问竞赛，已有记录时恢复授权
+func Grant(db *gorm.DB, contestID, userID, operator string) error {
+	auth := &models.ContestAuth{ContestID: contestID, UserID: userID, IsAuth: true}
+	auth.CreateUser = &operator
+	return db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "contest_id"}, {Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "delete_time IS NULL"}}},
+		DoUpdates:   clause.Assignments(map[string]interface{}{"is_auth": true, "update_user": operator}),
+	}).Create(auth).Error
+}
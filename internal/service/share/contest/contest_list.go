@@ -0,0 +1,153 @@
+package contest
+
+import (
+	"encoding/json"
+	"fmt"
+	dto "galaxy/internal/dto/contest"
+	"galaxy/internal/models"
+	contestQuery "galaxy/internal/query/contest"
+	"galaxy/pkg/query"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// contestSortFields 竞赛列表允许的排序字段
+var contestSortFields = []string{"create_time", "title", "sort", "contest_start_time", "contest_end_time"}
+
+// ContestList 竞赛分页列表，db 可预先附加可见性等条件
+func ContestList(db *gorm.DB, req *contestQuery.ContestQueryRequest) (*query.PaginationResponse[dto.ContestItem], error) {
+	if req == nil {
+		req = &contestQuery.ContestQueryRequest{}
+	}
+	req.Normalize()
+
+	db = db.Model(&models.ContestInfo{})
+	if req.Keyword != "" {
+		db = db.Where("title LIKE ?", "%"+req.Keyword+"%")
+	}
+	if req.Status != "" {
+		db = db.Where("status = ?", req.Status)
+	}
+	if req.RuleType != "" {
+		db = db.Where("rule_type = ?", req.RuleType)
+	}
+	if req.Category != "" {
+		db = db.Where("category = ?", req.Category)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if sort := req.GetSortIn(contestSortFields...); sort != "" {
+		db = db.Order(sort)
+	} else {
+		// 默认置顶排序值大的竞赛，其余按开始时间倒序
+		db = db.Order("sort DESC, contest_start_time DESC, id ASC")
+	}
+
+	var records []models.ContestInfo
+	if err := db.Omit("description", "password").
+		Offset(req.GetOffset()).Limit(req.Size).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(records))
+	for i := range records {
+		ids = append(ids, records[i].ID)
+	}
+	counts, err := ParticipantCounts(db.Session(&gorm.Session{NewDB: true}), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	items := make([]dto.ContestItem, 0, len(records))
+	for i := range records {
+		item := dto.NewContestItem(&records[i], StatusAt(&records[i], now))
+		item.ParticipantCount = counts[records[i].ID]
+		items = append(items, item)
+	}
+	return query.BuildPaginationResponse(&req.PaginationRequest, items, total), nil
+}
+
+// ParticipantCounts 批量统计竞赛报名人数
+func ParticipantCounts(db *gorm.DB, contestIDs []string) (map[string]int64, error) {
+	result := make(map[string]int64, len(contestIDs))
+	if len(contestIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		ContestID string
+		Count     int64
+	}
+	if err := db.Model(&models.ContestParticipant{}).
+		Select("contest_id, COUNT(*) AS count").
+		Where("contest_id IN ?", contestIDs).
+		Group("contest_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.ContestID] = row.Count
+	}
+	return result, nil
+}
+
+// ContestProblems 竞赛题目，按排序值排列
+func ContestProblems(db *gorm.DB, contestID string) ([]models.ContestProblem, error) {
+	problems := []models.ContestProblem{}
+	err := db.Where("contest_id = ?", contestID).
+		Order("sort ASC, problem_code ASC").
+		Find(&problems).Error
+	return problems, err
+}
+
+// ProblemItems 竞赛题目及其标题，按排序值排列
+func ProblemItems(db *gorm.DB, contestID string) ([]dto.ContestProblemItem, error) {
+	problems, err := ContestProblems(db, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(problems))
+	for i := range problems {
+		ids = append(ids, problems[i].ProblemID)
+	}
+	var infos []models.ProblemInfo
+	if err := db.Select("id", "title").Where("id IN ?", ids).Find(&infos).Error; err != nil {
+		return nil, err
+	}
+	titles := make(map[string]*string, len(infos))
+	for i := range infos {
+		titles[infos[i].ID] = infos[i].Title
+	}
+
+	items := make([]dto.ContestProblemItem, 0, len(problems))
+	for i := range problems {
+		items = append(items, dto.ContestProblemItem{
+			ProblemCode: problems[i].ProblemCode,
+			ProblemID:   problems[i].ProblemID,
+			DisplayID:   problems[i].DisplayID,
+			Title:       titles[problems[i].ProblemID],
+			Score:       problems[i].Score,
+		})
+	}
+	return items, nil
+}
+
+// ParseLanguages 解析竞赛允许的语言，空值返回空切片
+func ParseLanguages(data datatypes.JSON) ([]string, error) {
+	languages := []string{}
+	if len(data) == 0 || string(data) == "null" {
+		return languages, nil
+	}
+	if err := json.Unmarshal(data, &languages); err != nil {
+		return nil, fmt.Errorf("竞赛语言格式错误: %w", err)
+	}
+	return languages, nil
+}
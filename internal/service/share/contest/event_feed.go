@@ -0,0 +1,265 @@
+package contest
+
+import (
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/contest"
+	"galaxy/internal/models"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrContestNotEnded = errors.New("竞赛尚未结束")
+
+// feedTimeLayout 事件流中的绝对时间格式
+const feedTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// feedGroup 全部队伍所在的分组
+const feedGroup = "participants"
+
+// feedJudgementTypes 事件流使用的判题结果类型
+var feedJudgementTypes = []dto.FeedJudgementType{
+	{ID: "AC", Name: "Accepted", Penalty: false, Solved: true},
+	{ID: "WA", Name: "Wrong Answer", Penalty: true},
+	{ID: "PE", Name: "Presentation Error", Penalty: true},
+	{ID: "TLE", Name: "Time Limit Exceeded", Penalty: true},
+	{ID: "MLE", Name: "Memory Limit Exceeded", Penalty: true},
+	{ID: "OLE", Name: "Output Limit Exceeded", Penalty: true},
+	{ID: "RTE", Name: "Run-Time Error", Penalty: true},
+	{ID: "CE", Name: "Compile Error", Penalty: false},
+	{ID: "RE", Name: "Rejected", Penalty: true},
+}
+
+// feedJudgements 判题状态对应的结果类型，未列出的状态视为 RE
+var feedJudgements = map[string]string{
+	models.JudgeStatusAccepted:            "AC",
+	models.JudgeStatusWrongAnswer:         "WA",
+	models.JudgeStatusPartialAccepted:     "WA",
+	models.JudgeStatusPresentationError:   "PE",
+	models.JudgeStatusTimeLimitExceeded:   "TLE",
+	models.JudgeStatusMemoryLimitExceeded: "MLE",
+	models.JudgeStatusOutputLimitExceeded: "OLE",
+	models.JudgeStatusRuntimeError:        "RTE",
+	models.JudgeStatusCompileError:        "CE",
+}
+
+// EventFeed 生成 ICPC 赛制竞赛的 CDS 事件流，供 Resolver 等工具在颁奖时逐步揭晓封榜期间的结果。
+// 只能在比赛结束后导出；系统错误的提交不计入榜单，也不输出
+func EventFeed(db *gorm.DB, contest *models.ContestInfo) ([]dto.FeedEvent, error) {
+	if contest.ContestStartTime == nil || contest.ContestEndTime == nil {
+		return nil, errors.New("竞赛时间未设置")
+	}
+	now := time.Now()
+	if now.Before(*contest.ContestEndTime) {
+		return nil, ErrContestNotEnded
+	}
+	if ruleType := RuleType(contest); ruleType != models.ContestRuleICPC {
+		return nil, fmt.Errorf("仅 ICPC 赛制支持导出事件流: %s", ruleType)
+	}
+	start, end := *contest.ContestStartTime, *contest.ContestEndTime
+
+	problems, err := ContestProblems(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	submits, err := feedSubmits(db, contest, problems)
+	if err != nil {
+		return nil, err
+	}
+	board := make([]boardSubmit, 0, len(submits))
+	for i := range submits {
+		board = append(board, newBoardSubmit(&submits[i], "", 0, nil))
+	}
+	rows, err := boardRows(db, contest.ID, board)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []dto.FeedEvent
+	add := func(typ string, data interface{}) {
+		events = append(events, dto.FeedEvent{Type: typ, ID: strconv.Itoa(len(events) + 1), Op: "create", Data: data})
+	}
+
+	feedContest := dto.FeedContest{
+		ID:          contest.ID,
+		Name:        contest.Title,
+		FormalName:  contest.Title,
+		StartTime:   feedTime(start),
+		Duration:    contestTime(end.Sub(start)),
+		PenaltyTime: contest.PenaltyTime,
+	}
+	freeze := FreezeTime(contest)
+	if freeze != nil {
+		duration := contestTime(end.Sub(*freeze))
+		feedContest.ScoreboardFreezeDuration = &duration
+	}
+	add("contests", feedContest)
+
+	for _, judgementType := range feedJudgementTypes {
+		add("judgement-types", judgementType)
+	}
+
+	languages, err := ParseLanguages(contest.AllowedLanguages)
+	if err != nil {
+		return nil, err
+	}
+	for i := range submits {
+		if submits[i].Language != nil {
+			languages = append(languages, *submits[i].Language)
+		}
+	}
+	seen := make(map[string]bool, len(languages))
+	for _, language := range languages {
+		if language == "" || seen[language] {
+			continue
+		}
+		seen[language] = true
+		add("languages", dto.FeedLanguage{ID: language, Name: language})
+	}
+
+	titles, err := problemTitles(db, problems)
+	if err != nil {
+		return nil, err
+	}
+	for i := range problems {
+		add("problems", dto.FeedProblem{
+			ID:      problems[i].ProblemID,
+			Label:   problems[i].ProblemCode,
+			Name:    titles[problems[i].ProblemID],
+			Ordinal: i,
+		})
+	}
+
+	add("groups", dto.FeedGroup{ID: feedGroup, Name: "参赛选手"})
+	for i := range rows {
+		add("teams", dto.FeedTeam{ID: rows[i].UserID, Name: teamName(&rows[i]), GroupIDs: []string{feedGroup}})
+	}
+
+	// 提交与判题结果按提交时间依次输出，判题结束时间取提交的最后更新时间
+	for i := range submits {
+		submit := &submits[i]
+		language := ""
+		if submit.Language != nil {
+			language = *submit.Language
+		}
+		add("submissions", dto.FeedSubmission{
+			ID:          submit.ID,
+			LanguageID:  language,
+			ProblemID:   *submit.ProblemID,
+			TeamID:      *submit.UserID,
+			Time:        feedTime(submit.CreatedAt),
+			ContestTime: contestTime(submit.CreatedAt.Sub(start)),
+		})
+		if !submit.IsFinish || submit.Status == nil {
+			continue
+		}
+		judgementType, ok := feedJudgements[*submit.Status]
+		if !ok {
+			judgementType = "RE"
+		}
+		add("judgements", dto.FeedJudgement{
+			ID:               submit.ID,
+			SubmissionID:     submit.ID,
+			JudgementTypeID:  judgementType,
+			StartTime:        feedTime(submit.CreatedAt),
+			StartContestTime: contestTime(submit.CreatedAt.Sub(start)),
+			EndTime:          feedTime(submit.UpdatedAt),
+			EndContestTime:   contestTime(submit.UpdatedAt.Sub(start)),
+		})
+	}
+
+	state := dto.FeedState{
+		Started:      feedTimePtr(&start),
+		Frozen:       feedTimePtr(freeze),
+		Ended:        feedTimePtr(&end),
+		Thawed:       feedTimePtr(contest.UnfreezeTime),
+		Finalized:    feedTimePtr(&now),
+		EndOfUpdates: feedTimePtr(&now),
+	}
+	add("state", state)
+	return events, nil
+}
+
+// feedSubmits 计入榜单的提交，按提交时间排序；忽略比赛时间外、不在竞赛题目中以及系统错误的提交
+func feedSubmits(db *gorm.DB, contest *models.ContestInfo, problems []models.ContestProblem) ([]models.JudgeSubmit, error) {
+	problemIDs := make([]string, 0, len(problems))
+	for i := range problems {
+		problemIDs = append(problemIDs, problems[i].ProblemID)
+	}
+	if len(problemIDs) == 0 {
+		return nil, nil
+	}
+
+	var submits []models.JudgeSubmit
+	if err := db.Select("id", "user_id", "problem_id", "language", "status", "is_finish", "create_time", "update_time").
+		Where("module_type = ? AND module_id = ?", models.ModuleTypeContest, contest.ID).
+		Where("is_test_submit = ? AND is_admin_submit = ?", false, false).
+		Where("user_id IS NOT NULL AND problem_id IN ?", problemIDs).
+		Where("create_time >= ? AND create_time < ?", *contest.ContestStartTime, *contest.ContestEndTime).
+		Where("status IS NULL OR status <> ?", models.JudgeStatusSystemError).
+		Find(&submits).Error; err != nil {
+		return nil, err
+	}
+	sort.Slice(submits, func(i, j int) bool {
+		if !submits[i].CreatedAt.Equal(submits[j].CreatedAt) {
+			return submits[i].CreatedAt.Before(submits[j].CreatedAt)
+		}
+		return submits[i].ID < submits[j].ID
+	})
+	return submits, nil
+}
+
+// problemTitles 竞赛题目的标题
+func problemTitles(db *gorm.DB, problems []models.ContestProblem) (map[string]string, error) {
+	ids := make([]string, 0, len(problems))
+	for i := range problems {
+		ids = append(ids, problems[i].ProblemID)
+	}
+	var infos []models.ProblemInfo
+	if err := db.Select("id", "title").Where("id IN ?", ids).Find(&infos).Error; err != nil {
+		return nil, err
+	}
+	titles := make(map[string]string, len(infos))
+	for i := range infos {
+		if infos[i].Title != nil {
+			titles[infos[i].ID] = *infos[i].Title
+		}
+	}
+	return titles, nil
+}
+
+// teamName 队伍展示名称：团队赛为队伍名称，否则依次取昵称与用户名
+func teamName(row *dto.ScoreboardRow) string {
+	switch {
+	case row.TeamName != nil && *row.TeamName != "":
+		return *row.TeamName
+	case row.Nickname != "":
+		return row.Nickname
+	}
+	return row.Username
+}
+
+func feedTime(t time.Time) string {
+	return t.Format(feedTimeLayout)
+}
+
+func feedTimePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := feedTime(*t)
+	return &s
+}
+
+// contestTime 相对比赛开始的时间，格式为 h:mm:ss.sss
+func contestTime(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%s%d:%02d:%02d.%03d", sign, ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,300 @@
+package contest
+
+import (
+	"errors"
+	"fmt"
+	dto "galaxy/internal/dto/contest"
+	"galaxy/internal/models"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrContestNotStarted = errors.New("竞赛尚未开始")
+
+// penaltyFree 不计罚时的判题结果
+var penaltyFree = map[string]bool{
+	models.JudgeStatusCompileError: true,
+	models.JudgeStatusSystemError:  true,
+}
+
+// BuildScoreboard 计算竞赛榜单。frozen 为 true 时封榜后的提交显示为待定，用于向选手展示
+func BuildScoreboard(db *gorm.DB, contest *models.ContestInfo, frozen bool) (*dto.Scoreboard, error) {
+	if contest.ContestStartTime == nil || contest.ContestEndTime == nil {
+		return nil, errors.New("竞赛时间未设置")
+	}
+	if !Started(contest, time.Now()) {
+		return nil, ErrContestNotStarted
+	}
+
+	problems, err := ContestProblems(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	submits, err := boardSubmits(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := boardRows(db, contest.ID, submits)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleType := RuleType(contest)
+	board := &dto.Scoreboard{
+		ContestID:   contest.ID,
+		RuleType:    ruleType,
+		PenaltyTime: contest.PenaltyTime,
+		FreezeTime:  FreezeTime(contest),
+		UpdateTime:  time.Now(),
+		Problems:    make([]dto.ScoreboardProblem, 0, len(problems)),
+		Rows:        rows,
+	}
+	for i := range problems {
+		board.Problems = append(board.Problems, dto.ScoreboardProblem{
+			ProblemCode: problems[i].ProblemCode,
+			ProblemID:   problems[i].ProblemID,
+			Score:       problems[i].Score,
+		})
+	}
+
+	switch ruleType {
+	case models.ContestRuleICPC:
+		board.Frozen = frozen && board.FreezeTime != nil
+		icpcBoard(board, contest, submits)
+	case models.ContestRuleOI, models.ContestRuleIOI:
+		scores, err := problemScores(db, problems)
+		if err != nil {
+			return nil, err
+		}
+		if ruleType == models.ContestRuleOI {
+			board.Frozen = frozen
+			oiBoard(board, contest, submits, scores)
+		} else {
+			ioiBoard(board, contest, submits, scores)
+		}
+	default:
+		return nil, fmt.Errorf("不支持的赛制: %s", ruleType)
+	}
+	return board, nil
+}
+
+// boardRows 榜单行：全部报名用户及在竞赛中有提交的用户
+func boardRows(db *gorm.DB, contestID string, submits []boardSubmit) ([]dto.ScoreboardRow, error) {
+	var participants []models.ContestParticipant
+	if err := db.Select("user_id", "team_name").
+		Where("contest_id = ?", contestID).
+		Find(&participants).Error; err != nil {
+		return nil, err
+	}
+
+	teams := make(map[string]*string, len(participants))
+	userIDs := make([]string, 0, len(participants))
+	for i := range participants {
+		if _, ok := teams[participants[i].UserID]; !ok {
+			userIDs = append(userIDs, participants[i].UserID)
+		}
+		teams[participants[i].UserID] = participants[i].TeamName
+	}
+	for _, submit := range submits {
+		if _, ok := teams[submit.UserID]; !ok {
+			teams[submit.UserID] = nil
+			userIDs = append(userIDs, submit.UserID)
+		}
+	}
+
+	var accounts []models.AuthAccount
+	if err := db.Unscoped().Select("id", "username").Where("id IN ?", userIDs).Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	usernames := make(map[string]string, len(accounts))
+	for i := range accounts {
+		usernames[accounts[i].ID] = accounts[i].Username
+	}
+	var infos []models.UserInfo
+	if err := db.Select("account_id", "nickname").Where("account_id IN ?", userIDs).Find(&infos).Error; err != nil {
+		return nil, err
+	}
+	nicknames := make(map[string]string, len(infos))
+	for i := range infos {
+		nicknames[infos[i].AccountID] = infos[i].Nickname
+	}
+
+	rows := make([]dto.ScoreboardRow, 0, len(userIDs))
+	for _, userID := range userIDs {
+		rows = append(rows, dto.ScoreboardRow{
+			UserID:   userID,
+			Username: usernames[userID],
+			Nickname: nicknames[userID],
+			TeamName: teams[userID],
+		})
+	}
+	return rows, nil
+}
+
+// cellKey 用户在一道题上的提交
+type cellKey struct {
+	userID, problemID string
+}
+
+// groupSubmits 按用户与题目分组，组内按提交时间排序，忽略比赛时间外的提交
+func groupSubmits(contest *models.ContestInfo, submits []boardSubmit) map[cellKey][]boardSubmit {
+	groups := make(map[cellKey][]boardSubmit)
+	for _, submit := range submits {
+		if submit.Time.Before(*contest.ContestStartTime) || !submit.Time.Before(*contest.ContestEndTime) {
+			continue
+		}
+		key := cellKey{submit.UserID, submit.ProblemID}
+		groups[key] = append(groups[key], submit)
+	}
+	for _, list := range groups {
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].Time.Equal(list[j].Time) {
+				return list[i].Time.Before(list[j].Time)
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+	return groups
+}
+
+// icpcBoard 按 ICPC 规则计算：通过后的提交不再计入；出现未公布结果的提交后，其后的提交也视为未公布，
+// 避免在前面的提交结果未知时提前显示通过。罚时为各题通过时间加上通过前错误提交次数乘以罚时
+func icpcBoard(board *dto.Scoreboard, contest *models.ContestInfo, submits []boardSubmit) {
+	groups := groupSubmits(contest, submits)
+	start := *contest.ContestStartTime
+
+	firsts := make([]*firstSolve, len(board.Problems))
+	lastSolve := make([]time.Time, len(board.Rows))
+
+	for r := range board.Rows {
+		row := &board.Rows[r]
+		row.Cells = make([]dto.ScoreboardCell, len(board.Problems))
+		for p := range board.Problems {
+			cell := &row.Cells[p]
+			cell.ProblemCode = board.Problems[p].ProblemCode
+
+			list := groups[cellKey{row.UserID, board.Problems[p].ProblemID}]
+			var solvedAt time.Time
+			for _, submit := range list {
+				hidden := submit.Status == "" || (board.Frozen && !submit.Time.Before(*board.FreezeTime))
+				if hidden || cell.Pending > 0 {
+					cell.Pending++
+					continue
+				}
+				if penaltyFree[submit.Status] {
+					continue
+				}
+				cell.Attempts++
+				if submit.Status == models.JudgeStatusAccepted {
+					solvedAt = submit.Time
+					break
+				}
+			}
+			if len(list) > 0 {
+				board.Problems[p].TriedCount++
+			}
+
+			switch {
+			case !solvedAt.IsZero():
+				cell.Result = dto.CellAccepted
+				cell.SolveTime = int(solvedAt.Sub(start) / time.Minute)
+				cell.Penalty = cell.SolveTime + (cell.Attempts-1)*contest.PenaltyTime
+				row.Solved++
+				row.Penalty += cell.Penalty
+				board.Problems[p].SolvedCount++
+				if solvedAt.After(lastSolve[r]) {
+					lastSolve[r] = solvedAt
+				}
+				firsts[p] = firsts[p].earlier(r, solvedAt)
+			case cell.Pending > 0:
+				cell.Result = dto.CellPending
+			case cell.Attempts > 0:
+				cell.Result = dto.CellRejected
+			}
+			cell.Display = cellDisplay(cell)
+		}
+	}
+
+	markFirstBlood(board, firsts)
+
+	// 通过题数多者在前，其次罚时少者在前，再次最后一题通过早者在前
+	rankRows(board, func(a, b int) int {
+		ra, rb := &board.Rows[a], &board.Rows[b]
+		switch {
+		case ra.Solved != rb.Solved:
+			return rb.Solved - ra.Solved
+		case ra.Penalty != rb.Penalty:
+			return ra.Penalty - rb.Penalty
+		case !lastSolve[a].Equal(lastSolve[b]):
+			return lastSolve[a].Compare(lastSolve[b])
+		}
+		return 0
+	})
+}
+
+// firstSolve 一道题最早的通过
+type firstSolve struct {
+	row  int
+	time time.Time
+}
+
+// earlier 与第 row 行在 t 时刻的通过比较，返回较早的一个
+func (f *firstSolve) earlier(row int, t time.Time) *firstSolve {
+	if f == nil || t.Before(f.time) {
+		return &firstSolve{row: row, time: t}
+	}
+	return f
+}
+
+// markFirstBlood 标记各题一血
+func markFirstBlood(board *dto.Scoreboard, firsts []*firstSolve) {
+	for p, first := range firsts {
+		if first == nil {
+			continue
+		}
+		board.Rows[first.row].Cells[p].FirstBlood = true
+		board.Problems[p].FirstSolver = board.Rows[first.row].UserID
+	}
+}
+
+// rankRows 按 compare 排序并计算名次，compare 相等的行名次相同，展示时按用户名排列
+func rankRows(board *dto.Scoreboard, compare func(a, b int) int) {
+	order := make([]int, len(board.Rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if c := compare(order[i], order[j]); c != 0 {
+			return c < 0
+		}
+		return board.Rows[order[i]].Username < board.Rows[order[j]].Username
+	})
+
+	rows := make([]dto.ScoreboardRow, len(order))
+	for i, index := range order {
+		rows[i] = board.Rows[index]
+		rows[i].Rank = i + 1
+		if i > 0 && compare(order[i-1], index) == 0 {
+			rows[i].Rank = rows[i-1].Rank
+		}
+	}
+	board.Rows = rows
+}
+
+// cellDisplay 单元格展示文本：通过为 + 与错误次数，待定为 ?，未通过为 - 与错误次数
+func cellDisplay(cell *dto.ScoreboardCell) string {
+	switch cell.Result {
+	case dto.CellAccepted:
+		if cell.Attempts > 1 {
+			return fmt.Sprintf("+%d", cell.Attempts-1)
+		}
+		return "+"
+	case dto.CellPending:
+		return "?"
+	case dto.CellRejected:
+		return fmt.Sprintf("-%d", cell.Attempts)
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package contest
+
+import (
+	"encoding/json"
+	"galaxy/internal/models"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/redis"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 榜单缓存：每个竞赛一个哈希，字段为提交 ID，值为提交摘要。判题完成时只覆盖对应字段，
+// 读取时由摘要计算榜单；readyField 标记哈希已由数据库完整重建，缺失时重新加载
+const (
+	scoreboardKeyPrefix = "contest:scoreboard:"
+	readyField          = "_ready"
+	scoreboardTTL       = 7 * 24 * time.Hour
+)
+
+// boardSubmit 榜单使用的提交摘要
+type boardSubmit struct {
+	ID        string             `json:"i"`
+	UserID    string             `json:"u"`
+	ProblemID string             `json:"p"`
+	Time      time.Time          `json:"t"`
+	Status    string             `json:"s"` // 未完成时为空
+	Score     float64            `json:"c,omitempty"`
+	Subtasks  map[string]float64 `json:"k,omitempty"` // 各子任务得分，键为子任务 ID
+}
+
+func scoreboardKey(contestID string) string {
+	return scoreboardKeyPrefix + contestID
+}
+
+// onBoard 提交是否计入榜单，测试提交与管理员提交不计入
+func onBoard(submit *models.JudgeSubmit) bool {
+	return submit.ModuleType != nil && *submit.ModuleType == models.ModuleTypeContest &&
+		submit.ModuleID != nil && submit.UserID != nil && submit.ProblemID != nil &&
+		!submit.IsTestSubmit && !submit.IsAdminSubmit
+}
+
+func newBoardSubmit(submit *models.JudgeSubmit, status string, score float64, subtasks map[string]float64) boardSubmit {
+	return boardSubmit{
+		ID:        submit.ID,
+		UserID:    *submit.UserID,
+		ProblemID: *submit.ProblemID,
+		Time:      submit.CreatedAt,
+		Status:    status,
+		Score:     score,
+		Subtasks:  subtasks,
+	}
+}
+
+// RecordPending 新的竞赛提交以待判状态加入榜单。判题可能先于此处完成，因此只在字段不存在时写入
+func RecordPending(submit *models.JudgeSubmit) error {
+	if !onBoard(submit) {
+		return nil
+	}
+	data, err := json.Marshal(newBoardSubmit(submit, "", 0, nil))
+	if err != nil {
+		return err
+	}
+	_, err = redis.HSetNX(scoreboardKey(*submit.ModuleID), submit.ID, data)
+	return err
+}
+
+// RecordFinished 判题完成后更新榜单中的提交结果，subtasks 为各子任务得分
+func RecordFinished(submit *models.JudgeSubmit, status string, score float64, subtasks map[string]float64) error {
+	if !onBoard(submit) {
+		return nil
+	}
+	data, err := json.Marshal(newBoardSubmit(submit, status, score, subtasks))
+	if err != nil {
+		return err
+	}
+	return redis.HSet(scoreboardKey(*submit.ModuleID), submit.ID, data)
+}
+
+// InvalidateScoreboard 清除榜单缓存，下次读取时由数据库重建，用于重判等批量变更之后
+func InvalidateScoreboard(contestID string) error {
+	return redis.Delete(scoreboardKey(contestID))
+}
+
+// boardSubmits 读取竞赛的提交摘要，缓存未就绪时由数据库重建；Redis 不可用时直接查询数据库
+func boardSubmits(db *gorm.DB, contestID string) ([]boardSubmit, error) {
+	key := scoreboardKey(contestID)
+	values, err := redis.HGetAll(key)
+	if err != nil {
+		logger.Warn().Str("contest_id", contestID).Err(err).Msg("Read scoreboard cache failed")
+		return loadBoardSubmits(db, contestID)
+	}
+
+	if values[readyField] == "" {
+		submits, err := loadBoardSubmits(db, contestID)
+		if err != nil {
+			return nil, err
+		}
+		if err := fillCache(key, submits); err != nil {
+			logger.Warn().Str("contest_id", contestID).Err(err).Msg("Rebuild scoreboard cache failed")
+			return submits, nil
+		}
+		if values, err = redis.HGetAll(key); err != nil {
+			return submits, nil
+		}
+	}
+
+	submits := make([]boardSubmit, 0, len(values))
+	for field, value := range values {
+		if field == readyField {
+			continue
+		}
+		var submit boardSubmit
+		if err := json.Unmarshal([]byte(value), &submit); err != nil {
+			return nil, err
+		}
+		submits = append(submits, submit)
+	}
+	return submits, nil
+}
+
+// fillCache 写入由数据库加载的摘要。已存在的字段由判题端在加载之后写入，结果更新，不覆盖
+func fillCache(key string, submits []boardSubmit) error {
+	for _, submit := range submits {
+		data, err := json.Marshal(submit)
+		if err != nil {
+			return err
+		}
+		if _, err := redis.HSetNX(key, submit.ID, data); err != nil {
+			return err
+		}
+	}
+	if err := redis.HSet(key, readyField, 1); err != nil {
+		return err
+	}
+	return redis.Expire(key, scoreboardTTL)
+}
+
+// loadBoardSubmits 由数据库加载竞赛的全部提交摘要及子任务得分
+func loadBoardSubmits(db *gorm.DB, contestID string) ([]boardSubmit, error) {
+	contestSubmits := db.Model(&models.JudgeSubmit{}).
+		Where("module_type = ? AND module_id = ?", models.ModuleTypeContest, contestID).
+		Where("is_test_submit = ? AND is_admin_submit = ?", false, false).
+		Where("user_id IS NOT NULL AND problem_id IS NOT NULL")
+
+	var submits []models.JudgeSubmit
+	if err := contestSubmits.Session(&gorm.Session{}).
+		Select("id", "user_id", "problem_id", "status", "score", "is_finish", "create_time").
+		Find(&submits).Error; err != nil {
+		return nil, err
+	}
+	var judgeSubtasks []models.JudgeSubtask
+	if err := db.Select("submit_id", "subtask_id", "score").
+		Where("submit_id IN (?)", contestSubmits.Session(&gorm.Session{}).Select("id")).
+		Find(&judgeSubtasks).Error; err != nil {
+		return nil, err
+	}
+	subtasks := make(map[string]map[string]float64)
+	for i := range judgeSubtasks {
+		item := &judgeSubtasks[i]
+		if subtasks[item.SubmitID] == nil {
+			subtasks[item.SubmitID] = make(map[string]float64)
+		}
+		subtasks[item.SubmitID][item.SubtaskID] = item.Score
+	}
+
+	result := make([]boardSubmit, 0, len(submits))
+	for i := range submits {
+		submit := &submits[i]
+		if !submit.IsFinish || submit.Status == nil {
+			result = append(result, newBoardSubmit(submit, "", 0, nil))
+			continue
+		}
+		result = append(result, newBoardSubmit(submit, *submit.Status, submit.Score, subtasks[submit.ID]))
+	}
+	return result, nil
+}
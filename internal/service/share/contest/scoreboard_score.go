@@ -0,0 +1,198 @@
+package contest
+
+import (
+	"cmp"
+	dto "galaxy/internal/dto/contest"
+	"galaxy/internal/models"
+	"math"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultFullScore 题目未设置任何分值时判题使用的总分
+const defaultFullScore = 100
+
+// problemScore 题目计分：判题得分按 full 折算为竞赛设置的分值 score，未设置分值时使用判题得分
+type problemScore struct {
+	full  float64 // 判题满分
+	score float64 // 竞赛中的分值
+}
+
+// scale 将判题得分折算为竞赛得分，保留两位小数
+func (s problemScore) scale(raw float64) float64 {
+	if s.score > 0 && s.full > 0 {
+		raw = raw / s.full * s.score
+	}
+	return math.Round(raw*100) / 100
+}
+
+// max 竞赛中的满分
+func (s problemScore) max() float64 {
+	if s.score > 0 {
+		return s.score
+	}
+	return s.full
+}
+
+// problemScores 竞赛各题的计分，判题满分与判题端的计算一致：min 方式且设置了分值的子任务计子任务分值，
+// 其余用例计用例分值之和；题目未设置任何分值时为 100
+func problemScores(db *gorm.DB, problems []models.ContestProblem) ([]problemScore, error) {
+	ids := make([]string, 0, len(problems))
+	for i := range problems {
+		ids = append(ids, problems[i].ProblemID)
+	}
+	var testCases []models.ProblemTestCase
+	if err := db.Select("problem_id", "score", "subtask_id").Where("problem_id IN ?", ids).Find(&testCases).Error; err != nil {
+		return nil, err
+	}
+	var subtasks []models.ProblemSubtask
+	if err := db.Select("id", "problem_id", "score", "aggregation").Where("problem_id IN ?", ids).Find(&subtasks).Error; err != nil {
+		return nil, err
+	}
+	fixed := make(map[string]*models.ProblemSubtask, len(subtasks))
+	for i := range subtasks {
+		if subtasks[i].Aggregation != models.SubtaskAggregationSum && subtasks[i].Score > 0 {
+			fixed[subtasks[i].ID] = &subtasks[i]
+		}
+	}
+
+	full := make(map[string]float64, len(ids))
+	counted := make(map[string]bool, len(fixed))
+	for i := range testCases {
+		testCase := &testCases[i]
+		if testCase.SubtaskID != nil {
+			if subtask, ok := fixed[*testCase.SubtaskID]; ok && subtask.ProblemID == testCase.ProblemID {
+				if !counted[subtask.ID] {
+					counted[subtask.ID] = true
+					full[testCase.ProblemID] += subtask.Score
+				}
+				continue
+			}
+		}
+		full[testCase.ProblemID] += testCase.Score
+	}
+
+	scores := make([]problemScore, len(problems))
+	for i := range problems {
+		scores[i] = problemScore{full: full[problems[i].ProblemID], score: float64(problems[i].Score)}
+		if scores[i].full <= 0 {
+			scores[i].full = defaultFullScore
+		}
+	}
+	return scores, nil
+}
+
+// oiBoard 按 OI 规则计算：每题以最后一次提交的得分计，系统错误的提交不计入；封榜视图隐藏全部结果
+func oiBoard(board *dto.Scoreboard, contest *models.ContestInfo, submits []boardSubmit, scores []problemScore) {
+	groups := groupSubmits(contest, submits)
+	scoreBoard(board, contest, groups, scores, func(cell *dto.ScoreboardCell, list []boardSubmit) (float64, time.Time, bool) {
+		if board.Frozen {
+			cell.Pending = len(list)
+			return 0, time.Time{}, false
+		}
+		var last *boardSubmit
+		for i := range list {
+			if list[i].Status == models.JudgeStatusSystemError {
+				continue
+			}
+			cell.Attempts++
+			last = &list[i]
+		}
+		if last == nil {
+			return 0, time.Time{}, false
+		}
+		if last.Status == "" {
+			cell.Pending = 1
+			return 0, time.Time{}, false
+		}
+		return last.Score, last.Time, true
+	})
+}
+
+// ioiBoard 按 IOI 规则计算：每个子任务取各次提交的最高分，不属于子任务的用例得分同样取最高；
+// 判题中的提交计入待定次数，不影响已公布的得分
+func ioiBoard(board *dto.Scoreboard, contest *models.ContestInfo, submits []boardSubmit, scores []problemScore) {
+	groups := groupSubmits(contest, submits)
+	scoreBoard(board, contest, groups, scores, func(cell *dto.ScoreboardCell, list []boardSubmit) (float64, time.Time, bool) {
+		best := make(map[string]float64)
+		var bestRest, total float64
+		var scoredAt time.Time
+		for _, submit := range list {
+			if submit.Status == "" {
+				cell.Pending++
+				continue
+			}
+			if submit.Status == models.JudgeStatusSystemError {
+				continue
+			}
+			cell.Attempts++
+
+			rest := submit.Score
+			for id, score := range submit.Subtasks {
+				rest -= score
+				best[id] = math.Max(best[id], score)
+			}
+			bestRest = math.Max(bestRest, rest)
+
+			sum := bestRest
+			for _, score := range best {
+				sum += score
+			}
+			if sum > total || scoredAt.IsZero() {
+				total, scoredAt = sum, submit.Time
+			}
+		}
+		return total, scoredAt, cell.Attempts > 0
+	})
+}
+
+// scoreBoard 按得分排名的赛制共用的计算。settle 计算一个单元格的判题得分与取得该得分的时间，
+// ok 为 false 表示没有已公布的得分；满分的单元格视为通过
+func scoreBoard(board *dto.Scoreboard, contest *models.ContestInfo, groups map[cellKey][]boardSubmit, scores []problemScore,
+	settle func(cell *dto.ScoreboardCell, list []boardSubmit) (raw float64, at time.Time, ok bool)) {
+	start := *contest.ContestStartTime
+	firsts := make([]*firstSolve, len(board.Problems))
+
+	for r := range board.Rows {
+		row := &board.Rows[r]
+		row.Cells = make([]dto.ScoreboardCell, len(board.Problems))
+		for p := range board.Problems {
+			cell := &row.Cells[p]
+			cell.ProblemCode = board.Problems[p].ProblemCode
+
+			list := groups[cellKey{row.UserID, board.Problems[p].ProblemID}]
+			if len(list) > 0 {
+				board.Problems[p].TriedCount++
+			}
+			raw, at, ok := settle(cell, list)
+
+			switch {
+			case ok:
+				cell.Score = scores[p].scale(raw)
+				cell.SolveTime = int(at.Sub(start) / time.Minute)
+				row.Score += cell.Score
+				cell.Result = dto.CellRejected
+				if cell.Score >= scores[p].max() {
+					cell.Result = dto.CellAccepted
+					row.Solved++
+					board.Problems[p].SolvedCount++
+					firsts[p] = firsts[p].earlier(r, at)
+				}
+				cell.Display = strconv.FormatFloat(cell.Score, 'f', -1, 64)
+			case cell.Pending > 0:
+				cell.Result = dto.CellPending
+				cell.Display = "?"
+			}
+		}
+		row.Score = math.Round(row.Score*100) / 100
+	}
+
+	markFirstBlood(board, firsts)
+
+	// 总分高者在前，同分名次相同
+	rankRows(board, func(a, b int) int {
+		return cmp.Compare(board.Rows[b].Score, board.Rows[a].Score)
+	})
+}
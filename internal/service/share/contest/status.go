@@ -0,0 +1,237 @@
+package contest
+
+import (
+	"errors"
+	"galaxy/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrContestNotFound   = errors.New("竞赛不存在")
+	ErrRegisterNotOpen   = errors.New("竞赛尚未开放报名")
+	ErrRegisterClosed    = errors.New("竞赛报名已截止")
+	ErrAlreadyRegistered = errors.New("已报名该竞赛")
+	ErrNotRegistered     = errors.New("未报名该竞赛")
+)
+
+// FindContest 查询竞赛，不存在时返回 ErrContestNotFound
+func FindContest(db *gorm.DB, id string) (*models.ContestInfo, error) {
+	if id == "" {
+		return nil, ErrContestNotFound
+	}
+	var contest models.ContestInfo
+	if err := db.Where("id = ?", id).First(&contest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrContestNotFound
+		}
+		return nil, err
+	}
+	return &contest, nil
+}
+
+// RuleType 竞赛赛制，未设置时为 ICPC
+func RuleType(contest *models.ContestInfo) string {
+	if contest.RuleType == nil || *contest.RuleType == "" {
+		return models.ContestRuleICPC
+	}
+	return *contest.RuleType
+}
+
+// FreezeTime 封榜开始时间，未设置封榜或不是 ICPC 赛制时为 nil。FrozenTime 为比赛结束前封榜的分钟数
+func FreezeTime(contest *models.ContestInfo) *time.Time {
+	if contest.FrozenTime <= 0 || contest.ContestEndTime == nil || RuleType(contest) != models.ContestRuleICPC {
+		return nil
+	}
+	t := contest.ContestEndTime.Add(-time.Duration(contest.FrozenTime) * time.Minute)
+	if contest.ContestStartTime != nil && t.Before(*contest.ContestStartTime) {
+		t = *contest.ContestStartTime
+	}
+	return &t
+}
+
+// RegisterWindow 报名时间窗口，未设置开始时间表示创建后即可报名，未设置截止时间表示比赛结束前均可报名
+func RegisterWindow(contest *models.ContestInfo) (start, end *time.Time) {
+	end = contest.RegisterEndTime
+	if end == nil {
+		end = contest.ContestEndTime
+	}
+	return contest.RegisterStartTime, end
+}
+
+// CheckRegister 校验当前是否可以报名
+func CheckRegister(contest *models.ContestInfo, now time.Time) error {
+	start, end := RegisterWindow(contest)
+	if start != nil && now.Before(*start) {
+		return ErrRegisterNotOpen
+	}
+	if end != nil && !now.Before(*end) {
+		return ErrRegisterClosed
+	}
+	return nil
+}
+
+// StatusAt 计算竞赛在 now 时刻的状态：开赛前按报名窗口为 registering 或 scheduled，
+// 比赛中进入封榜时间后为 frozen，结束后为 ended
+func StatusAt(contest *models.ContestInfo, now time.Time) string {
+	switch {
+	case contest.ContestEndTime != nil && !now.Before(*contest.ContestEndTime):
+		return models.ContestStatusEnded
+	case contest.ContestStartTime != nil && !now.Before(*contest.ContestStartTime):
+		if freeze := FreezeTime(contest); freeze != nil && !now.Before(*freeze) {
+			return models.ContestStatusFrozen
+		}
+		return models.ContestStatusRunning
+	case CheckRegister(contest, now) == nil:
+		return models.ContestStatusRegistering
+	default:
+		return models.ContestStatusScheduled
+	}
+}
+
+// Frozen 选手看到的榜单在 now 时刻是否隐藏部分结果：ICPC 赛制从封榜开始直到管理员解除封榜，
+// OI 赛制在比赛结束前隐藏全部结果，IOI 赛制实时公布
+func Frozen(contest *models.ContestInfo, now time.Time) bool {
+	switch RuleType(contest) {
+	case models.ContestRuleOI:
+		return contest.ContestEndTime != nil && now.Before(*contest.ContestEndTime)
+	case models.ContestRuleICPC:
+		freeze := FreezeTime(contest)
+		return freeze != nil && contest.UnfreezeTime == nil && !now.Before(*freeze)
+	}
+	return false
+}
+
+// ResultHidden 竞赛提交的判题结果在 now 时刻是否对 viewerID 隐藏：OI 赛制比赛结束前对所有人隐藏，
+// ICPC 赛制封榜期间封榜后的提交只对提交者本人公开
+func ResultHidden(contest *models.ContestInfo, submit *models.JudgeSubmit, viewerID string, now time.Time) bool {
+	if !Frozen(contest, now) {
+		return false
+	}
+	if RuleType(contest) == models.ContestRuleOI {
+		return true
+	}
+	if submit.UserID != nil && *submit.UserID == viewerID {
+		return false
+	}
+	return !submit.CreatedAt.Before(*FreezeTime(contest))
+}
+
+// HiddenContests now 时刻隐藏部分结果的竞赛
+func HiddenContests(db *gorm.DB, now time.Time) ([]string, error) {
+	contests, err := hiddenContests(db, now)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(contests))
+	for i := range contests {
+		ids = append(ids, contests[i].ID)
+	}
+	return ids, nil
+}
+
+func hiddenContests(db *gorm.DB, now time.Time) ([]models.ContestInfo, error) {
+	var contests []models.ContestInfo
+	if err := db.Model(&models.ContestInfo{}).
+		Select("id", "rule_type", "contest_start_time", "contest_end_time", "frozen_time", "unfreeze_time").
+		Where("contest_start_time IS NULL OR contest_start_time <= ?", now).
+		Where("contest_end_time > ? OR (frozen_time > 0 AND unfreeze_time IS NULL)", now).
+		Find(&contests).Error; err != nil {
+		return nil, err
+	}
+
+	hidden := contests[:0]
+	for i := range contests {
+		if Frozen(&contests[i], now) {
+			hidden = append(hidden, contests[i])
+		}
+	}
+	return hidden, nil
+}
+
+// ExcludeHidden 附加条件，排除 now 时刻结果对公众隐藏的竞赛提交：OI 赛制比赛中的全部提交，
+// ICPC 赛制封榜后的提交。用于题目统计与用户做题统计，结果公布后再补计
+func ExcludeHidden(db *gorm.DB, now time.Time) (*gorm.DB, error) {
+	contests, err := hiddenContests(db.Session(&gorm.Session{NewDB: true}), now)
+	if err != nil {
+		return nil, err
+	}
+	if len(contests) == 0 {
+		return db, nil
+	}
+
+	conds := make([]string, 0, len(contests))
+	args := []interface{}{models.ModuleTypeContest}
+	for i := range contests {
+		since := time.Time{}
+		if freeze := FreezeTime(&contests[i]); freeze != nil {
+			since = *freeze
+		}
+		conds = append(conds, "(module_id = ? AND create_time >= ?)")
+		args = append(args, contests[i].ID, since)
+	}
+	return db.Where("module_type IS DISTINCT FROM ? OR NOT ("+strings.Join(conds, " OR ")+")", args...), nil
+}
+
+// Unsettled 结果已公布但尚未补计统计的竞赛：OI 赛制与设置了封榜的 ICPC 赛制在结果隐藏期间不计入统计，
+// 比赛结束且解除封榜后需要补计一次
+func Unsettled(db *gorm.DB, now time.Time) ([]models.ContestInfo, error) {
+	var contests []models.ContestInfo
+	if err := db.Model(&models.ContestInfo{}).
+		Select("id", "rule_type", "contest_start_time", "contest_end_time", "frozen_time", "unfreeze_time").
+		Where("settle_time IS NULL AND contest_end_time <= ?", now).
+		Where("rule_type = ? OR frozen_time > 0", models.ContestRuleOI).
+		Find(&contests).Error; err != nil {
+		return nil, err
+	}
+
+	unsettled := contests[:0]
+	for i := range contests {
+		contest := &contests[i]
+		hides := RuleType(contest) == models.ContestRuleOI || FreezeTime(contest) != nil
+		if hides && !Frozen(contest, now) {
+			unsettled = append(unsettled, *contest)
+		}
+	}
+	return unsettled, nil
+}
+
+// Started 竞赛是否已开始
+func Started(contest *models.ContestInfo, now time.Time) bool {
+	return contest.ContestStartTime == nil || !now.Before(*contest.ContestStartTime)
+}
+
+// UpdateStatuses 将未结束竞赛的状态更新为当前时刻应处的状态，返回状态变化的竞赛数
+func UpdateStatuses(db *gorm.DB, now time.Time) (int, error) {
+	var contests []models.ContestInfo
+	if err := db.Select("id", "status", "rule_type", "register_start_time", "register_end_time",
+		"contest_start_time", "contest_end_time", "frozen_time").
+		Where("status IS NULL OR status <> ?", models.ContestStatusEnded).
+		Find(&contests).Error; err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for i := range contests {
+		contest := &contests[i]
+		status := StatusAt(contest, now)
+		if contest.Status != nil && *contest.Status == status {
+			continue
+		}
+		// 以旧状态为条件更新，避免覆盖管理员同时修改时间后写入的新状态
+		db := db.Model(&models.ContestInfo{}).Where("id = ?", contest.ID)
+		if contest.Status == nil {
+			db = db.Where("status IS NULL")
+		} else {
+			db = db.Where("status = ?", *contest.Status)
+		}
+		result := db.UpdateColumn("status", status)
+		if result.Error != nil {
+			return changed, result.Error
+		}
+		changed += int(result.RowsAffected)
+	}
+	return changed, nil
+}
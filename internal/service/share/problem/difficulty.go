@@ -0,0 +1,180 @@
+package problem
+
+import (
+	"galaxy/internal/models"
+	"galaxy/pkg/config"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ratingScale 逻辑斯蒂模型的斜率：评分比题目难度高 1 级时通过概率约为 73%
+const ratingScale = 1.0
+
+// 未配置时的默认参数
+const (
+	defaultTopSolved = 20
+	defaultMaxLevel  = 10
+)
+
+// attempt 用户对题目的尝试结果
+type attempt struct {
+	UserID    string
+	ProblemID string
+	Solved    bool
+}
+
+// sample 参与估计的尝试者
+type sample struct {
+	rating float64
+	solved bool
+}
+
+// EstimateDifficulty 根据尝试者评分估计各题目的经验难度并写入 problem_stats，返回给出建议的题目数
+func EstimateDifficulty(db *gorm.DB, cfg config.DifficultyConfig) (int, error) {
+	if cfg.TopSolved <= 0 {
+		cfg.TopSolved = defaultTopSolved
+	}
+	if cfg.MaxLevel <= 0 {
+		cfg.MaxLevel = defaultMaxLevel
+	}
+
+	var attempts []attempt
+	if err := db.Model(&models.JudgeSubmit{}).
+		Select("user_id, problem_id, BOOL_OR(status = ?) AS solved", models.JudgeStatusAccepted).
+		Where("is_finish = ? AND is_test_submit = ? AND is_admin_submit = ?", true, false, false).
+		Where("user_id IS NOT NULL AND problem_id IS NOT NULL").
+		Group("user_id, problem_id").
+		Scan(&attempts).Error; err != nil {
+		return 0, err
+	}
+
+	var problems []models.ProblemInfo
+	if err := db.Select("id", "difficulty").Find(&problems).Error; err != nil {
+		return 0, err
+	}
+	difficulties := make(map[string]int, len(problems))
+	for i := range problems {
+		difficulties[problems[i].ID] = problems[i].Difficulty
+	}
+
+	ratings := userRatings(attempts, difficulties, cfg)
+	samples := make(map[string][]sample)
+	for _, a := range attempts {
+		if _, ok := difficulties[a.ProblemID]; !ok {
+			continue
+		}
+		// 没有有评分尝试者的题目也需要清除旧的建议
+		if _, ok := samples[a.ProblemID]; !ok {
+			samples[a.ProblemID] = nil
+		}
+		rating, ok := ratings[a.UserID]
+		if !ok {
+			continue
+		}
+		samples[a.ProblemID] = append(samples[a.ProblemID], sample{rating: rating, solved: a.Solved})
+	}
+
+	problemIDs := make([]string, 0, len(samples))
+	for problemID := range samples {
+		problemIDs = append(problemIDs, problemID)
+	}
+	existing, err := StatsMap(db, problemIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	suggested := 0
+	for _, problemID := range problemIDs {
+		// 功能上线前已有提交的题目先补齐计数
+		if existing[problemID] == nil {
+			if err := RecomputeStats(db, problemID); err != nil {
+				return suggested, err
+			}
+		}
+
+		stats := models.ProblemStats{
+			ProblemID:     problemID,
+			RatingSamples: len(samples[problemID]),
+			EstimateTime:  &now,
+		}
+		if stats.RatingSamples >= max(cfg.MinSamples, 1) {
+			stats.Rating = fitDifficulty(samples[problemID], 0, float64(cfg.MaxLevel+1))
+			stats.SuggestedDifficulty = min(max(int(math.Round(stats.Rating)), 1), cfg.MaxLevel)
+			suggested++
+		}
+		if err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "problem_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rating", "rating_samples", "suggested_difficulty", "estimate_time"}),
+		}).Create(&stats).Error; err != nil {
+			return suggested, err
+		}
+	}
+	return suggested, nil
+}
+
+// userRatings 用户评分：通过的最难 TopSolved 道题的平均难度，通过题数不足 MinSolved 的用户没有评分
+func userRatings(attempts []attempt, difficulties map[string]int, cfg config.DifficultyConfig) map[string]float64 {
+	solved := make(map[string][]int)
+	for _, a := range attempts {
+		difficulty, ok := difficulties[a.ProblemID]
+		if !a.Solved || !ok {
+			continue
+		}
+		solved[a.UserID] = append(solved[a.UserID], difficulty)
+	}
+
+	ratings := make(map[string]float64, len(solved))
+	for userID, list := range solved {
+		if len(list) < max(cfg.MinSolved, 1) {
+			continue
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(list)))
+		top := list[:min(len(list), cfg.TopSolved)]
+		sum := 0
+		for _, difficulty := range top {
+			sum += difficulty
+		}
+		ratings[userID] = float64(sum) / float64(len(top))
+	}
+	return ratings
+}
+
+// fitDifficulty 求使 Σ P(通过) 等于实际通过人数的难度，即逻辑斯蒂模型下难度的极大似然估计；
+// 全部通过或全部未通过时没有有限解，分别取下界与上界
+func fitDifficulty(samples []sample, low, high float64) float64 {
+	solved := 0
+	for _, s := range samples {
+		if s.solved {
+			solved++
+		}
+	}
+	if solved == 0 {
+		return high
+	}
+	if solved == len(samples) {
+		return low
+	}
+
+	// 期望通过人数随难度单调递减，二分求解
+	expected := func(difficulty float64) float64 {
+		sum := 0.0
+		for _, s := range samples {
+			sum += 1 / (1 + math.Exp(-ratingScale*(s.rating-difficulty)))
+		}
+		return sum
+	}
+	for i := 0; i < 60; i++ {
+		mid := (low + high) / 2
+		if expected(mid) > float64(solved) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2
+}
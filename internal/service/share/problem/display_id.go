@@ -0,0 +1,154 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"galaxy/internal/models"
+	"galaxy/pkg/config"
+	"regexp"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultDisplayIDStart 未配置起始编号时每个前缀的起始编号
+const defaultDisplayIDStart = 1000
+
+// displayIDPattern 展示编号会出现在 URL 中，只允许字母、数字、下划线与连字符
+var displayIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// ValidDisplayID 展示编号格式是否合法
+func ValidDisplayID(displayID string) bool {
+	return displayIDPattern.MatchString(displayID)
+}
+
+// FindProblem 按题目 ID 或展示编号查找题目，ID 优先；db 可预先附加可见性等条件
+func FindProblem(db *gorm.DB, key string) (*models.ProblemInfo, error) {
+	db = db.Session(&gorm.Session{})
+
+	var problem models.ProblemInfo
+	err := db.Where("id = ?", key).First(&problem).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && ValidDisplayID(key) {
+		err = db.Where("display_id = ?", key).First(&problem).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &problem, nil
+}
+
+// ProblemIDs 按题目 ID 或展示编号匹配题目 ID 的子查询
+func ProblemIDs(db *gorm.DB, key string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Model(&models.ProblemInfo{}).
+		Select("id").
+		Where("(id = ? OR display_id = ?)", key, key)
+}
+
+// DisplayIDPrefix 按配置规则确定题目的展示编号前缀
+func DisplayIDPrefix(problem *models.ProblemInfo) string {
+	cfg := config.Get().Problem.DisplayID
+	for _, rule := range cfg.Rules {
+		if rule.CategoryID == "" && rule.Source == "" {
+			continue
+		}
+		if rule.CategoryID != "" && rule.CategoryID != problem.CategoryID {
+			continue
+		}
+		if rule.Source != "" && (problem.Source == nil || rule.Source != *problem.Source) {
+			continue
+		}
+		return rule.Prefix
+	}
+	return cfg.Prefix
+}
+
+// AllocateDisplayID 为题目分配所属前缀的下一个可用编号，跳过保留区间与已占用的编号，须在事务中调用
+func AllocateDisplayID(tx *gorm.DB, problem *models.ProblemInfo) error {
+	prefix := DisplayIDPrefix(problem)
+	sequence, err := LockSequence(tx, prefix)
+	if err != nil {
+		return err
+	}
+	ranges, err := ReservedRanges(tx, prefix)
+	if err != nil {
+		return err
+	}
+
+	// 已删除题目的编号也不再复用，避免旧链接指向新题目
+	value, err := NextDisplayValue(prefix, sequence.NextValue, ranges, func(displayID string) (bool, error) {
+		var count int64
+		err := tx.Unscoped().Model(&models.ProblemInfo{}).
+			Where("display_id = ?", displayID).
+			Count(&count).Error
+		return count > 0, err
+	})
+	if err != nil {
+		return err
+	}
+	displayID := FormatDisplayID(prefix, value)
+	problem.DisplayID = &displayID
+
+	return tx.Model(sequence).Update("next_value", value+1).Error
+}
+
+// NextDisplayValue 从 value 开始查找第一个不在保留区间内且未被占用的编号
+func NextDisplayValue(prefix string, value int, ranges []models.ProblemReservedRange, used func(displayID string) (bool, error)) (int, error) {
+	for {
+		value = skipReserved(value, ranges)
+		taken, err := used(FormatDisplayID(prefix, value))
+		if err != nil {
+			return 0, err
+		}
+		if !taken {
+			return value, nil
+		}
+		value++
+	}
+}
+
+// LockSequence 锁定前缀的编号序列，不存在时按起始编号创建
+func LockSequence(tx *gorm.DB, prefix string) (*models.ProblemSequence, error) {
+	start := config.Get().Problem.DisplayID.Start
+	if start <= 0 {
+		start = defaultDisplayIDStart
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ProblemSequence{Prefix: prefix, NextValue: start}).Error; err != nil {
+		return nil, err
+	}
+
+	var sequence models.ProblemSequence
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("prefix = ?", prefix).
+		First(&sequence).Error; err != nil {
+		return nil, err
+	}
+	return &sequence, nil
+}
+
+// ReservedRanges 前缀的保留区间，按起始编号排序
+func ReservedRanges(db *gorm.DB, prefix string) ([]models.ProblemReservedRange, error) {
+	var ranges []models.ProblemReservedRange
+	if err := db.Where("prefix = ?", prefix).
+		Order("start_value ASC").
+		Find(&ranges).Error; err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// FormatDisplayID 拼接前缀与编号
+func FormatDisplayID(prefix string, value int) string {
+	return fmt.Sprintf("%s%d", prefix, value)
+}
+
+// skipReserved 若 value 落在保留区间内，返回区间之后的第一个编号
+func skipReserved(value int, ranges []models.ProblemReservedRange) int {
+	for _, reserved := range ranges {
+		if value >= reserved.StartValue && value <= reserved.EndValue {
+			value = reserved.EndValue + 1
+		}
+	}
+	return value
+}
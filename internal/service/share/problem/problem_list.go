@@ -0,0 +1,118 @@
+package problem
+
+import (
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/models"
+	problemQuery "galaxy/internal/query/problem"
+	"galaxy/pkg/query"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Public 附加公开题目的查询条件：公开、可见，且不属于尚未结束的竞赛，竞赛题目在比赛期间只能在竞赛中查看
+func Public(db *gorm.DB, now time.Time) *gorm.DB {
+	session := db.Session(&gorm.Session{NewDB: true})
+	contests := session.Model(&models.ContestInfo{}).
+		Select("id").
+		Where("contest_end_time IS NULL OR contest_end_time > ?", now)
+	contestProblems := session.Model(&models.ContestProblem{}).
+		Select("problem_id").
+		Where("contest_id IN (?)", contests)
+	return db.Where("is_public = ? AND is_visible = ? AND id NOT IN (?)", true, true, contestProblems)
+}
+
+// problemSortFields 题目列表允许的排序字段
+var problemSortFields = []string{"create_time", "update_time", "display_id", "title", "difficulty", "time_limit", "memory_limit"}
+
+// ProblemList 题目分页列表，db 可预先附加可见性等条件
+func ProblemList(db *gorm.DB, req *problemQuery.ProblemQueryRequest) (*query.PaginationResponse[dto.ProblemItem], error) {
+	if req == nil {
+		req = &problemQuery.ProblemQueryRequest{}
+	}
+	req.Normalize()
+
+	// 构建查询
+	db = db.Model(&models.ProblemInfo{})
+
+	// 应用查询条件
+	if req.Keyword != "" {
+		keyword := "%" + req.Keyword + "%"
+		db = db.Where("(title LIKE ? OR display_id LIKE ?)", keyword, keyword)
+	}
+	if req.CategoryID != "" {
+		db = db.Where("category_id = ?", req.CategoryID)
+	}
+	if req.Difficulty > 0 {
+		db = db.Where("difficulty = ?", req.Difficulty)
+	}
+	db, err := FilterByTags(db, req.Tags(), req.MatchAllTags())
+	if err != nil {
+		return nil, err
+	}
+
+	// 获取总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// 应用排序
+	if sort := req.GetSortIn(problemSortFields...); sort != "" {
+		db = db.Order(sort)
+	} else {
+		// 默认排序
+		db = db.Order("create_time ASC, id ASC")
+	}
+
+	// 应用分页
+	offset := req.GetOffset()
+	var records []models.ProblemInfo
+	if err := db.Omit("description", "checker_code", "interactor_code", "code_template").
+		Offset(offset).Limit(req.Size).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(records))
+	for i := range records {
+		ids = append(ids, records[i].ID)
+	}
+	tags, err := TagIDs(db.Session(&gorm.Session{NewDB: true}), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := StatsMap(db.Session(&gorm.Session{NewDB: true}), ids)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]dto.ProblemItem, 0, len(records))
+	for i := range records {
+		item := dto.NewProblemItem(&records[i], tags[records[i].ID])
+		item.ApplyStats(stats[records[i].ID])
+		items = append(items, item)
+	}
+
+	// 构建响应
+	return query.BuildPaginationResponse(&req.PaginationRequest, items, total), nil
+}
+
+// TagIDs 批量查询题目的标签 ID
+func TagIDs(db *gorm.DB, problemIDs []string) (map[string][]string, error) {
+	result := make(map[string][]string, len(problemIDs))
+	if len(problemIDs) == 0 {
+		return result, nil
+	}
+
+	var rels []models.ProblemTagRel
+	if err := db.Where("problem_id IN ?", problemIDs).
+		Order("create_time ASC").
+		Find(&rels).Error; err != nil {
+		return nil, err
+	}
+	for _, rel := range rels {
+		result[rel.ProblemID] = append(result[rel.ProblemID], rel.TagID)
+	}
+	return result, nil
+}
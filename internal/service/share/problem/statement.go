@@ -0,0 +1,178 @@
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/judge/template"
+	"galaxy/internal/judge/testdata"
+	"galaxy/internal/models"
+	"galaxy/pkg/markdown"
+	"io"
+	"os"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// sampleSizeLimit 样例文件展示的最大字节数
+const sampleSizeLimit = 64 << 10
+
+// ParseStatement 解析结构化题面，未设置时返回 nil
+func ParseStatement(data datatypes.JSON) (*dto.Statement, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var statement dto.Statement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return nil, fmt.Errorf("题面格式错误: %w", err)
+	}
+	return &statement, nil
+}
+
+// BuildDetail 面向用户的题目详情，题面渲染为 HTML，只返回样例与代码模板的可编辑部分
+func BuildDetail(db *gorm.DB, dataRoot string, problem *models.ProblemInfo) (*dto.ProblemDetail, error) {
+	tags, err := TagIDs(db, []string{problem.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	statement, samples, err := RenderStatement(db, dataRoot, problem)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只返回可编辑部分，前置与后置代码不对用户公开
+	var codeTemplate map[string]string
+	if problem.UseTemplate {
+		if codeTemplate, err = template.Editable(problem.CodeTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	stats, err := StatsMap(db, []string{problem.ID})
+	if err != nil {
+		return nil, err
+	}
+	verdicts := map[string]int64{}
+	if stats[problem.ID] != nil {
+		if verdicts, err = ParseVerdicts(stats[problem.ID].Verdicts); err != nil {
+			return nil, err
+		}
+	}
+
+	item := dto.NewProblemItem(problem, tags[problem.ID])
+	item.ApplyStats(stats[problem.ID])
+	return &dto.ProblemDetail{
+		ProblemItem:   item,
+		Description:   problem.Description,
+		Statement:     statement,
+		URL:           problem.URL,
+		Languages:     problem.Languages,
+		UseTemplate:   problem.UseTemplate,
+		CodeTemplate:  codeTemplate,
+		IsInteractive: problem.IsInteractive,
+		Samples:       samples,
+		Verdicts:      verdicts,
+	}, nil
+}
+
+// RenderStatement 渲染题面并生成样例。没有结构化题面时将 Description 作为题目描述渲染；
+// 样例数据取自样例用例，没有样例用例时使用题面中的样例
+func RenderStatement(db *gorm.DB, dataRoot string, problem *models.ProblemInfo) (*dto.RenderedStatement, []dto.SampleCase, error) {
+	statement, err := ParseStatement(problem.Statement)
+	if err != nil {
+		return nil, nil, err
+	}
+	if statement == nil {
+		statement = &dto.Statement{}
+		if problem.Description != nil {
+			statement.Description = *problem.Description
+		}
+	}
+
+	rendered := &dto.RenderedStatement{}
+	for _, section := range []struct {
+		source string
+		target *string
+	}{
+		{statement.Background, &rendered.Background},
+		{statement.Description, &rendered.Description},
+		{statement.Input, &rendered.Input},
+		{statement.Output, &rendered.Output},
+		{statement.Hint, &rendered.Hint},
+	} {
+		if *section.target, err = markdown.Render(section.source); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	samples, err := Samples(db, dataRoot, problem.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(samples) == 0 {
+		for _, sample := range statement.Samples {
+			samples = append(samples, dto.SampleCase{Input: sample.Input, Output: sample.Output})
+		}
+	}
+	for i := range samples {
+		if i >= len(statement.Samples) {
+			break
+		}
+		if samples[i].Explanation, err = markdown.Render(statement.Samples[i].Explanation); err != nil {
+			return nil, nil, err
+		}
+	}
+	return rendered, samples, nil
+}
+
+// Samples 读取样例用例，文件优先于内联数据
+func Samples(db *gorm.DB, dataRoot, problemID string) ([]dto.SampleCase, error) {
+	var testCases []models.ProblemTestCase
+	if err := db.Where("problem_id = ? AND is_sample = ?", problemID, true).
+		Order("create_time ASC, id ASC").
+		Find(&testCases).Error; err != nil {
+		return nil, err
+	}
+
+	samples := make([]dto.SampleCase, 0, len(testCases))
+	for i := range testCases {
+		testCase := &testCases[i]
+		input, err := readSample(dataRoot, testCase.InputFilePath, testCase.InputData)
+		if err != nil {
+			return nil, err
+		}
+		output, err := readSample(dataRoot, testCase.OutputFilePath, testCase.ExpectedOutput)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, dto.SampleCase{
+			CaseSign: testCase.CaseSign,
+			Input:    input,
+			Output:   output,
+		})
+	}
+	return samples, nil
+}
+
+func readSample(dataRoot string, path, data *string) (string, error) {
+	if path == nil || *path == "" {
+		if data == nil {
+			return "", nil
+		}
+		return *data, nil
+	}
+
+	file, err := os.Open(testdata.Path(dataRoot, *path))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, sampleSizeLimit))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
@@ -0,0 +1,154 @@
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"galaxy/internal/models"
+	shareContest "galaxy/internal/service/share/contest"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CountedSubmit 提交是否计入题目统计，测试提交与管理员提交不计入
+func CountedSubmit(submit *models.JudgeSubmit) bool {
+	return submit.ProblemID != nil && *submit.ProblemID != "" && !submit.IsTestSubmit && !submit.IsAdminSubmit
+}
+
+// countedSubmits 计入统计的已完成提交，结果尚未公布的竞赛提交不计入
+func countedSubmits(tx *gorm.DB, problemID string) (*gorm.DB, error) {
+	return shareContest.ExcludeHidden(tx.Model(&models.JudgeSubmit{}).
+		Where("problem_id = ? AND is_finish = ?", problemID, true).
+		Where("is_test_submit = ? AND is_admin_submit = ?", false, false), time.Now())
+}
+
+// LockStats 锁定题目统计行，不存在时创建，须在事务中调用
+func LockStats(tx *gorm.DB, problemID string) (*models.ProblemStats, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.ProblemStats{ProblemID: problemID}).Error; err != nil {
+		return nil, err
+	}
+
+	var stats models.ProblemStats
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("problem_id = ?", problemID).
+		First(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// RecordVerdict 累加一次提交的最终结果。须在事务中先 LockStats 再写入提交结果，
+// 之后调用本函数，这样并发判题时同一用户的多次通过只会计入一次通过人数
+func RecordVerdict(tx *gorm.DB, stats *models.ProblemStats, submit *models.JudgeSubmit, status string) error {
+	verdicts, err := ParseVerdicts(stats.Verdicts)
+	if err != nil {
+		return err
+	}
+	verdicts[status]++
+
+	stats.SubmitCount++
+	if status == models.JudgeStatusAccepted {
+		stats.AcceptedCount++
+		if submit.UserID != nil {
+			submits, err := countedSubmits(tx, stats.ProblemID)
+			if err != nil {
+				return err
+			}
+			var solved int64
+			if err := submits.
+				Where("user_id = ? AND status = ? AND id <> ?", *submit.UserID, models.JudgeStatusAccepted, submit.ID).
+				Count(&solved).Error; err != nil {
+				return err
+			}
+			if solved == 0 {
+				stats.SolverCount++
+			}
+		}
+	}
+	return saveCounters(tx, stats, verdicts)
+}
+
+// RecomputeStats 根据提交历史重新统计题目，用于重判完成后
+func RecomputeStats(db *gorm.DB, problemID string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		stats, err := LockStats(tx, problemID)
+		if err != nil {
+			return err
+		}
+
+		submits, err := countedSubmits(tx, problemID)
+		if err != nil {
+			return err
+		}
+		var rows []struct {
+			Status string
+			Count  int64
+		}
+		if err := submits.Session(&gorm.Session{}).
+			Select("status, COUNT(*) AS count").
+			Where("status IS NOT NULL").
+			Group("status").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		verdicts := make(map[string]int64, len(rows))
+		stats.SubmitCount, stats.AcceptedCount = 0, 0
+		for _, row := range rows {
+			verdicts[row.Status] = row.Count
+			stats.SubmitCount += row.Count
+			if row.Status == models.JudgeStatusAccepted {
+				stats.AcceptedCount = row.Count
+			}
+		}
+
+		if err := submits.
+			Where("status = ? AND user_id IS NOT NULL", models.JudgeStatusAccepted).
+			Distinct("user_id").
+			Count(&stats.SolverCount).Error; err != nil {
+			return err
+		}
+		return saveCounters(tx, stats, verdicts)
+	})
+}
+
+func saveCounters(tx *gorm.DB, stats *models.ProblemStats, verdicts map[string]int64) error {
+	data, err := json.Marshal(verdicts)
+	if err != nil {
+		return err
+	}
+	stats.Verdicts = data
+	return tx.Model(stats).
+		Select("submit_count", "accepted_count", "solver_count", "verdicts").
+		Updates(stats).Error
+}
+
+// ParseVerdicts 解析各判题状态的次数
+func ParseVerdicts(data []byte) (map[string]int64, error) {
+	verdicts := make(map[string]int64)
+	if len(data) == 0 || string(data) == "null" {
+		return verdicts, nil
+	}
+	if err := json.Unmarshal(data, &verdicts); err != nil {
+		return nil, fmt.Errorf("判题状态统计格式错误: %w", err)
+	}
+	return verdicts, nil
+}
+
+// StatsMap 批量查询题目统计，没有提交的题目不在结果中
+func StatsMap(db *gorm.DB, problemIDs []string) (map[string]*models.ProblemStats, error) {
+	result := make(map[string]*models.ProblemStats, len(problemIDs))
+	if len(problemIDs) == 0 {
+		return result, nil
+	}
+
+	var stats []models.ProblemStats
+	if err := db.Where("problem_id IN ?", problemIDs).Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	for i := range stats {
+		result[stats[i].ProblemID] = &stats[i]
+	}
+	return result, nil
+}
@@ -0,0 +1,203 @@
+package problem
+
+import (
+	"errors"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RootTagID 顶级标签的 ParentID，与 content_tag.parent_id 默认值一致
+const RootTagID = "0"
+
+// TagForest 题目标签的父子关系
+type TagForest struct {
+	Tags     []models.ContentTag
+	children map[string][]string
+	index    map[string]int
+}
+
+// LoadTags 加载全部题目标签，按创建顺序排列
+func LoadTags(db *gorm.DB) (*TagForest, error) {
+	var tags []models.ContentTag
+	if err := db.Where("module_type = ?", models.ModuleTypeProblem).
+		Order("create_time ASC, id ASC").
+		Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	forest := &TagForest{
+		Tags:     tags,
+		children: make(map[string][]string),
+		index:    make(map[string]int, len(tags)),
+	}
+	for i := range tags {
+		forest.index[tags[i].ID] = i
+	}
+	for i := range tags {
+		parentID := tags[i].ParentID
+		// 父标签不存在时视为顶级标签
+		if _, ok := forest.index[parentID]; !ok {
+			parentID = RootTagID
+		}
+		forest.children[parentID] = append(forest.children[parentID], tags[i].ID)
+	}
+	return forest, nil
+}
+
+// Get 按 ID 获取标签
+func (f *TagForest) Get(id string) (*models.ContentTag, bool) {
+	i, ok := f.index[id]
+	if !ok {
+		return nil, false
+	}
+	return &f.Tags[i], true
+}
+
+// Children 直接子标签 ID，RootTagID 返回顶级标签
+func (f *TagForest) Children(id string) []string {
+	return f.children[id]
+}
+
+// Subtree 标签自身及全部后代标签的 ID
+func (f *TagForest) Subtree(id string) []string {
+	ids := []string{id}
+	seen := map[string]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		// 跳过已访问的标签，避免数据异常形成环时死循环
+		for _, child := range f.children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
+
+// CheckTags 校验标签均为已存在的题目标签
+func CheckTags(db *gorm.DB, tagIDs []string) error {
+	tagIDs = UniqueIDs(tagIDs)
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	var count int64
+	if err := db.Model(&models.ContentTag{}).
+		Where("id IN ? AND module_type = ?", tagIDs, models.ModuleTypeProblem).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(tagIDs) {
+		return errors.New("标签不存在")
+	}
+	return nil
+}
+
+// FilterByTags 按标签筛选题目，选中父标签时同时匹配其子标签；
+// matchAll 为 true 时题目须命中每个选中的标签，否则命中任一即可
+func FilterByTags(db *gorm.DB, tagIDs []string, matchAll bool) (*gorm.DB, error) {
+	tagIDs = UniqueIDs(tagIDs)
+	if len(tagIDs) == 0 {
+		return db, nil
+	}
+
+	forest, err := LoadTags(db.Session(&gorm.Session{NewDB: true}))
+	if err != nil {
+		return nil, err
+	}
+	rels := func(ids []string) *gorm.DB {
+		return db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.ProblemTagRel{}).
+			Select("problem_id").
+			Where("tag_id IN ?", ids)
+	}
+
+	if !matchAll {
+		var ids []string
+		for _, tagID := range tagIDs {
+			ids = append(ids, forest.Subtree(tagID)...)
+		}
+		return db.Where("id IN (?)", rels(ids)), nil
+	}
+	for _, tagID := range tagIDs {
+		db = db.Where("id IN (?)", rels(forest.Subtree(tagID)))
+	}
+	return db, nil
+}
+
+// TagProblemIDs 各标签关联的题目 ID，problems 为题目范围的子查询，如只统计公开题目
+func TagProblemIDs(db *gorm.DB, problems *gorm.DB) (map[string][]string, error) {
+	var rels []models.ProblemTagRel
+	if err := db.Select("problem_id", "tag_id").
+		Where("problem_id IN (?)", problems).
+		Find(&rels).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[string][]string)
+	for _, rel := range rels {
+		result[rel.TagID] = append(result[rel.TagID], rel.ProblemID)
+	}
+	return result, nil
+}
+
+// SolvedProblemIDs 用户通过的题目 ID 子查询，包含在竞赛中通过的题目
+func SolvedProblemIDs(db *gorm.DB, userID string) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Model(&models.RecordSolved{}).
+		Distinct("problem_id").
+		Where("user_id = ? AND is_solved = ?", userID, true)
+}
+
+// TagTree 构建标签树并统计各标签（含子标签）的题目数与已通过题目数；
+// problemIDs 为各标签直接关联的题目，solved 为已通过的题目，visibleOnly 时跳过隐藏标签及其子标签
+func TagTree(forest *TagForest, problemIDs map[string][]string, solved map[string]bool, visibleOnly bool) []*dto.TagNode {
+	var build func(parentID string) []*dto.TagNode
+	build = func(parentID string) []*dto.TagNode {
+		nodes := []*dto.TagNode{}
+		for _, id := range forest.Children(parentID) {
+			tag, _ := forest.Get(id)
+			if visibleOnly && !tag.IsVisible {
+				continue
+			}
+
+			problems := make(map[string]bool)
+			for _, tagID := range forest.Subtree(id) {
+				for _, problemID := range problemIDs[tagID] {
+					problems[problemID] = true
+				}
+			}
+			node := &dto.TagNode{
+				ID:           tag.ID,
+				Name:         tag.Name,
+				ParentID:     tag.ParentID,
+				IsVisible:    tag.IsVisible,
+				ProblemCount: len(problems),
+				Children:     build(id),
+			}
+			for problemID := range problems {
+				if solved[problemID] {
+					node.SolvedCount++
+				}
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	return build(RootTagID)
+}
+
+// UniqueIDs 去掉空值与重复值，保持原有顺序
+func UniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
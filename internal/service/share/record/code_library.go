@@ -0,0 +1,54 @@
+package record
+
+import (
+	"encoding/json"
+	"galaxy/internal/models"
+	"galaxy/pkg/lexer"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// archiveCode 将通过的代码连同词法序列写入代码库，同一提交只保存一次
+func archiveCode(tx *gorm.DB, submit *models.JudgeSubmit, sourceFile string) error {
+	if submit.Code == nil {
+		return nil
+	}
+	norms, kinds, texts := lexer.Streams(lexer.Tokenize(sourceFile, *submit.Code))
+
+	entry := models.RecordCodeLibrary{
+		UserID:     submit.UserID,
+		ModuleType: submit.ModuleType,
+		ModuleID:   submit.ModuleID,
+		ProblemID:  submit.ProblemID,
+		SubmitID:   &submit.ID,
+		SubmitTime: &submit.CreatedAt,
+		Language:   submit.Language,
+		Code:       submit.Code,
+		CodeLength: len(*submit.Code),
+	}
+	var err error
+	if entry.CodeToken, err = toJSON(norms); err != nil {
+		return err
+	}
+	if entry.CodeTokenName, err = toJSON(kinds); err != nil {
+		return err
+	}
+	if entry.CodeTokenTexts, err = toJSON(texts); err != nil {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "submit_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "delete_time IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&entry).Error
+}
+
+func toJSON(values []string) (datatypes.JSON, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
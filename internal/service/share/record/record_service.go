@@ -0,0 +1,260 @@
+package record
+
+import (
+	"galaxy/internal/models"
+	shareContest "galaxy/internal/service/share/contest"
+	"galaxy/pkg/database"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordService 用户学习记录服务接口定义
+type RecordService interface {
+	// RecordSubmit 在判题完成的事务中更新解决记录与用户统计，须在写入提交结果之后调用
+	RecordSubmit(tx *gorm.DB, submit *models.JudgeSubmit, status string) error
+	// ArchiveSubmit 在判题完成的事务中维护代码库：通过的代码连同词法序列入库，重判后不再通过的代码移出
+	ArchiveSubmit(tx *gorm.DB, submit *models.JudgeSubmit, status, sourceFile string) error
+	// RecomputeSolved 根据提交历史重新生成用户在某模块下某题的解决记录
+	RecomputeSolved(moduleType, moduleID, userID, problemID string) error
+	// RecomputeUserStats 根据提交历史与解决记录重新统计用户的做题数据
+	RecomputeUserStats(userID string) error
+}
+
+// RecordServiceImpl 用户学习记录服务实现
+type RecordServiceImpl struct {
+	db *gorm.DB
+}
+
+// 确保 RecordServiceImpl 实现 RecordService 接口
+var _ RecordService = (*RecordServiceImpl)(nil)
+
+func NewRecordService() RecordService {
+	return &RecordServiceImpl{
+		db: database.GetDB(),
+	}
+}
+
+// solvedKey 解决记录的唯一键
+type solvedKey struct {
+	moduleType, moduleID, userID, problemID string
+}
+
+func (k solvedKey) where(db *gorm.DB) *gorm.DB {
+	return db.Where("module_type = ? AND module_id = ? AND user_id = ? AND problem_id = ?",
+		k.moduleType, k.moduleID, k.userID, k.problemID)
+}
+
+// lockSolved 锁定解决记录，不存在时创建。加锁顺序为解决记录、用户统计，各处须保持一致以免死锁
+func lockSolved(tx *gorm.DB, key solvedKey) (*models.RecordSolved, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.RecordSolved{
+			ModuleType: &key.moduleType,
+			ModuleID:   &key.moduleID,
+			UserID:     &key.userID,
+			ProblemID:  &key.problemID,
+		}).Error; err != nil {
+		return nil, err
+	}
+
+	var solved models.RecordSolved
+	if err := key.where(tx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&solved).Error; err != nil {
+		return nil, err
+	}
+	return &solved, nil
+}
+
+// otherRecords 用户在其他模块下同一题目的解决记录
+func otherRecords(tx *gorm.DB, solved *models.RecordSolved) ([]models.RecordSolved, error) {
+	var records []models.RecordSolved
+	err := tx.Select("id", "is_solved").
+		Where("user_id = ? AND problem_id = ? AND id <> ?", *solved.UserID, *solved.ProblemID, solved.ID).
+		Find(&records).Error
+	return records, err
+}
+
+// RecordSubmit 按一次提交的结果增量更新解决记录与用户统计。解决记录按最早时间合并，
+// 重复处理同一提交结果不变；用户统计行加锁后再判断是否首次尝试、首次通过，并发判题时不会重复计数
+func (s *RecordServiceImpl) RecordSubmit(tx *gorm.DB, submit *models.JudgeSubmit, status string) error {
+	if submit.IsTestSubmit || submit.UserID == nil || submit.ProblemID == nil ||
+		submit.ModuleType == nil || submit.ModuleID == nil {
+		return nil
+	}
+	key := solvedKey{*submit.ModuleType, *submit.ModuleID, *submit.UserID, *submit.ProblemID}
+
+	solved, err := lockSolved(tx, key)
+	if err != nil {
+		return err
+	}
+	stats, err := lockUserStats(tx, key.userID)
+	if err != nil {
+		return err
+	}
+	others, err := otherRecords(tx, solved)
+	if err != nil {
+		return err
+	}
+
+	attemptedBefore := solved.FirstSubmitTime != nil || len(others) > 0
+	solvedBefore := solved.IsSolved
+	for i := range others {
+		solvedBefore = solvedBefore || others[i].IsSolved
+	}
+
+	submitTime := submit.CreatedAt
+	if solved.FirstSubmitTime == nil || submitTime.Before(*solved.FirstSubmitTime) {
+		solved.FirstSubmitTime = &submitTime
+	}
+	accepted := status == models.JudgeStatusAccepted
+	switch {
+	case accepted && (!solved.IsSolved || solved.FirstSolvedTime == nil || submitTime.Before(*solved.FirstSolvedTime)):
+		// 首次通过记录首次解决时间与对应提交
+		solved.IsSolved = true
+		solved.FirstSolvedTime = &submitTime
+		solved.SubmitID = &submit.ID
+	case !solved.IsSolved:
+		// 尚未通过时指向最近一次提交
+		solved.SubmitID = &submit.ID
+	}
+	if accepted && (solved.SolvedTime == nil || submitTime.After(*solved.SolvedTime)) {
+		solved.SolvedTime = &submitTime
+	}
+	if err := tx.Save(solved).Error; err != nil {
+		return err
+	}
+
+	stats.SubmitCount++
+	if !attemptedBefore {
+		stats.AttemptedCount++
+	}
+	if accepted {
+		stats.AcceptedCount++
+		if !solvedBefore {
+			stats.SolvedCount++
+			if err := awardSolve(tx, stats, key.problemID); err != nil {
+				return err
+			}
+		}
+	}
+	return saveUserStats(tx, stats)
+}
+
+// ArchiveSubmit 按判题结果维护代码库，测试提交不入库；sourceFile 为语言的源文件名，用于选择词法规则
+func (s *RecordServiceImpl) ArchiveSubmit(tx *gorm.DB, submit *models.JudgeSubmit, status, sourceFile string) error {
+	if submit.IsTestSubmit {
+		return nil
+	}
+	if status != models.JudgeStatusAccepted {
+		return tx.Where("submit_id = ?", submit.ID).Delete(&models.RecordCodeLibrary{}).Error
+	}
+	return archiveCode(tx, submit, sourceFile)
+}
+
+// RecomputeSolved 重新生成解决记录，没有已完成的提交时删除记录；
+// 题目因此首次被该用户通过时发放经验，经验不会因重判扣除
+func (s *RecordServiceImpl) RecomputeSolved(moduleType, moduleID, userID, problemID string) error {
+	key := solvedKey{moduleType, moduleID, userID, problemID}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		solved, err := lockSolved(tx, key)
+		if err != nil {
+			return err
+		}
+		stats, err := lockUserStats(tx, userID)
+		if err != nil {
+			return err
+		}
+		others, err := otherRecords(tx, solved)
+		if err != nil {
+			return err
+		}
+		solvedElsewhere := false
+		for i := range others {
+			solvedElsewhere = solvedElsewhere || others[i].IsSolved
+		}
+		solvedBefore := solved.IsSolved || solvedElsewhere
+
+		// 结果尚未公布的竞赛提交不计入，公布后再补计
+		query, err := shareContest.ExcludeHidden(key.where(tx.Select("id", "status", "create_time")).
+			Where("is_finish = ? AND is_test_submit = ?", true, false), time.Now())
+		if err != nil {
+			return err
+		}
+		var submits []models.JudgeSubmit
+		if err := query.
+			Order("create_time ASC, id ASC").
+			Find(&submits).Error; err != nil {
+			return err
+		}
+
+		if len(submits) == 0 {
+			return tx.Delete(solved).Error
+		}
+
+		solved.IsSolved = false
+		solved.FirstSubmitTime = &submits[0].CreatedAt
+		solved.FirstSolvedTime = nil
+		solved.SolvedTime = nil
+		solved.SubmitID = &submits[len(submits)-1].ID
+
+		// 首次通过记录首次解决时间与对应提交，最后一次通过记录解决时间
+		for i := range submits {
+			submit := &submits[i]
+			if submit.Status == nil || *submit.Status != models.JudgeStatusAccepted {
+				continue
+			}
+			if !solved.IsSolved {
+				solved.IsSolved = true
+				solved.FirstSolvedTime = &submit.CreatedAt
+				solved.SubmitID = &submit.ID
+			}
+			solved.SolvedTime = &submit.CreatedAt
+		}
+
+		if err := tx.Save(solved).Error; err != nil {
+			return err
+		}
+		if solvedBefore || !solved.IsSolved {
+			return nil
+		}
+		if err := awardSolve(tx, stats, problemID); err != nil {
+			return err
+		}
+		return saveUserStats(tx, stats)
+	})
+}
+
+// RecomputeUserStats 重新统计用户的提交、通过次数与尝试、通过的题目数，不影响经验与等级
+func (s *RecordServiceImpl) RecomputeUserStats(userID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		stats, err := lockUserStats(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		submits, err := shareContest.ExcludeHidden(tx.Model(&models.JudgeSubmit{}).
+			Where("user_id = ? AND is_finish = ? AND is_test_submit = ?", userID, true, false), time.Now())
+		if err != nil {
+			return err
+		}
+		if err := submits.Session(&gorm.Session{}).Count(&stats.SubmitCount).Error; err != nil {
+			return err
+		}
+		if err := submits.Where("status = ?", models.JudgeStatusAccepted).Count(&stats.AcceptedCount).Error; err != nil {
+			return err
+		}
+
+		records := func() *gorm.DB {
+			return tx.Model(&models.RecordSolved{}).Where("user_id = ?", userID).Distinct("problem_id")
+		}
+		if err := records().Count(&stats.AttemptedCount).Error; err != nil {
+			return err
+		}
+		if err := records().Where("is_solved = ?", true).Count(&stats.SolvedCount).Error; err != nil {
+			return err
+		}
+		return saveUserStats(tx, stats)
+	})
+}
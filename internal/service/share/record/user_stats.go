@@ -0,0 +1,85 @@
+package record
+
+import (
+	"galaxy/internal/models"
+	"galaxy/pkg/config"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 未配置时的默认等级参数
+const (
+	defaultSolveExp = 10
+	defaultBaseExp  = 100
+)
+
+// lockUserStats 锁定用户统计行，不存在时创建，须在事务中调用
+func lockUserStats(tx *gorm.DB, userID string) (*models.UserStats, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.UserStats{AccountID: userID}).Error; err != nil {
+		return nil, err
+	}
+
+	var stats models.UserStats
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_id = ?", userID).
+		First(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// saveUserStats 只写入经验、等级与做题统计，避免覆盖其他模块维护的字段
+func saveUserStats(tx *gorm.DB, stats *models.UserStats) error {
+	return tx.Model(stats).
+		Select("level", "exp", "total_exp", "submit_count", "accepted_count", "attempted_count", "solved_count").
+		Updates(stats).Error
+}
+
+// solveExp 首次通过题目获得的经验
+func solveExp(cfg config.LevelConfig, difficulty int) int64 {
+	base := cfg.SolveExp
+	if base <= 0 {
+		base = defaultSolveExp
+	}
+	return base + cfg.DifficultyExp*int64(max(difficulty, 0))
+}
+
+// levelExp 从 level 级升到下一级所需的经验
+func levelExp(cfg config.LevelConfig, level int) int64 {
+	base := cfg.BaseExp
+	if base <= 0 {
+		base = defaultBaseExp
+	}
+	// StepExp 为负时经验逐级递减，至少为 1，避免 addExp 无限升级
+	return max(base+cfg.StepExp*int64(level-1), 1)
+}
+
+// addExp 增加经验并处理升级，Exp 为当前等级内的经验，TotalExp 为累计经验
+func addExp(stats *models.UserStats, cfg config.LevelConfig, exp int64) {
+	if exp <= 0 {
+		return
+	}
+	stats.Level = max(stats.Level, 1)
+	stats.Exp += exp
+	stats.TotalExp += exp
+	for cfg.MaxLevel <= 0 || stats.Level < cfg.MaxLevel {
+		required := levelExp(cfg, stats.Level)
+		if stats.Exp < required {
+			break
+		}
+		stats.Exp -= required
+		stats.Level++
+	}
+}
+
+// awardSolve 用户首次通过题目时按题目难度发放经验
+func awardSolve(tx *gorm.DB, stats *models.UserStats, problemID string) error {
+	var problem models.ProblemInfo
+	if err := tx.Select("id", "difficulty").Where("id = ?", problemID).Limit(1).Find(&problem).Error; err != nil {
+		return err
+	}
+	addExp(stats, config.Get().User.Level, solveExp(config.Get().User.Level, problem.Difficulty))
+	return nil
+}
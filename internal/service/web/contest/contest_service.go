@@ -0,0 +1,274 @@
+package contest
+
+import (
+	"errors"
+	dto "galaxy/internal/dto/contest"
+	"galaxy/internal/models"
+	contestQuery "galaxy/internal/query/contest"
+	shareContest "galaxy/internal/service/share/contest"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/query"
+	"galaxy/pkg/redis"
+	"galaxy/pkg/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	unlockRateKeyPrefix = "contest:unlock:" // 竞赛密码尝试次数键前缀
+	unlockMaxAttempts   = 5                 // 时间窗口内允许的尝试次数
+	unlockRateWindow    = 10 * time.Minute  // 尝试次数的统计窗口
+)
+
+var (
+	ErrTeamNameRequired  = errors.New("团队赛须填写队伍名称")
+	ErrContestStarted    = errors.New("竞赛已开始，不能取消报名")
+	ErrUnlockTooFrequent = errors.New("密码尝试次数过多，请稍后再试")
+	ErrProblemNotFound   = errors.New("竞赛题目不存在")
+)
+
+// ContestService 竞赛服务接口定义，userID 为当前登录用户，未登录时为空
+type ContestService interface {
+	ContestList(req *contestQuery.ContestQueryRequest) (*query.PaginationResponse[dto.ContestItem], error)
+	GetContest(id, userID string) (*dto.ContestDetail, error)
+	Register(id, userID string, req *dto.RegisterRequest) error
+	CancelRegistration(id, userID string) error
+	// Unlock 凭密码获得非公开竞赛的访问授权
+	Unlock(id, userID string, req *dto.UnlockRequest) error
+	// GetProblem 竞赛题目详情，code 为题目编号；访问授权由路由中间件校验
+	GetProblem(id, code string) (*dto.ContestProblemDetail, error)
+	// Scoreboard 榜单，按赛制隐藏尚未公布的结果
+	Scoreboard(id string) (*dto.Scoreboard, error)
+}
+
+// ContestServiceImpl 竞赛服务实现
+type ContestServiceImpl struct {
+	db       *gorm.DB
+	dataRoot string
+}
+
+// 确保 ContestServiceImpl 实现 ContestService 接口
+var _ ContestService = (*ContestServiceImpl)(nil)
+
+func NewContestService() ContestService {
+	return &ContestServiceImpl{
+		db:       database.GetDB(),
+		dataRoot: config.Get().Judge.DataRoot,
+	}
+}
+
+// ContestList 获取可见的竞赛列表
+func (s *ContestServiceImpl) ContestList(req *contestQuery.ContestQueryRequest) (*query.PaginationResponse[dto.ContestItem], error) {
+	return shareContest.ContestList(s.db.Where("is_visible = ?", true), req)
+}
+
+// GetContest 获取竞赛详情，比赛开始前或当前用户无权访问时不返回题目
+func (s *ContestServiceImpl) GetContest(id, userID string) (*dto.ContestDetail, error) {
+	contest, err := s.findContest(id)
+	if err != nil {
+		return nil, err
+	}
+
+	languages, err := shareContest.ParseLanguages(contest.AllowedLanguages)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := shareContest.ParticipantCounts(s.db, []string{contest.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	detail := &dto.ContestDetail{
+		ContestItem:      dto.NewContestItem(contest, shareContest.StatusAt(contest, now)),
+		Description:      contest.Description,
+		AllowedLanguages: languages,
+		Problems:         []dto.ContestProblemItem{},
+	}
+	detail.ParticipantCount = counts[contest.ID]
+
+	if userID != "" {
+		var count int64
+		if err := s.db.Model(&models.ContestParticipant{}).
+			Where("contest_id = ? AND user_id = ?", contest.ID, userID).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		detail.Registered = count > 0
+	}
+
+	if err := shareContest.CheckAccess(s.db, contest, userID); err != nil {
+		if !shareContest.AccessDenied(err) {
+			return nil, err
+		}
+		return detail, nil
+	}
+	detail.Authorized = true
+
+	if shareContest.Started(contest, now) {
+		if detail.Problems, err = shareContest.ProblemItems(s.db, contest.ID); err != nil {
+			return nil, err
+		}
+	}
+	return detail, nil
+}
+
+// Register 报名竞赛，须在报名时间窗口内
+func (s *ContestServiceImpl) Register(id, userID string, req *dto.RegisterRequest) error {
+	contest, err := s.findContest(id)
+	if err != nil {
+		return err
+	}
+	if err := shareContest.CheckRegister(contest, time.Now()); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	status := models.ParticipantStatusRegistered
+	participant := &models.ContestParticipant{
+		ContestID:    contest.ID,
+		UserID:       userID,
+		RegisterTime: &now,
+		Status:       &status,
+	}
+	if contest.IsTeamContest {
+		teamName := ""
+		if req != nil {
+			teamName = strings.TrimSpace(req.TeamName)
+		}
+		if teamName == "" {
+			return ErrTeamNameRequired
+		}
+		participant.TeamName = &teamName
+		participant.IsTeamLeader = true
+	}
+	participant.CreateUser = &userID
+
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "contest_id"}, {Name: "user_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "delete_time IS NULL"}}},
+		DoNothing:   true,
+	}).Create(participant)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shareContest.ErrAlreadyRegistered
+	}
+	return nil
+}
+
+// CancelRegistration 取消报名，比赛开始后不能取消
+func (s *ContestServiceImpl) CancelRegistration(id, userID string) error {
+	contest, err := s.findContest(id)
+	if err != nil {
+		return err
+	}
+	if shareContest.Started(contest, time.Now()) {
+		return ErrContestStarted
+	}
+
+	result := s.db.Where("contest_id = ? AND user_id = ?", contest.ID, userID).
+		Delete(&models.ContestParticipant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return shareContest.ErrNotRegistered
+	}
+	return nil
+}
+
+// Unlock 校验竞赛密码并授权当前用户，公开竞赛无需授权
+func (s *ContestServiceImpl) Unlock(id, userID string, req *dto.UnlockRequest) error {
+	contest, err := s.findContest(id)
+	if err != nil {
+		return err
+	}
+	if contest.IsPublic {
+		return nil
+	}
+	if !shareContest.HasPassword(contest) {
+		return shareContest.ErrNotInvited
+	}
+
+	// 限制同一用户对同一竞赛的尝试次数，防止在线穷举密码
+	key := unlockRateKeyPrefix + contest.ID + ":" + userID
+	attempts, err := redis.Incr(key, unlockRateWindow)
+	if err != nil {
+		return err
+	}
+	if attempts > unlockMaxAttempts {
+		return ErrUnlockTooFrequent
+	}
+	if !utils.CheckPassword(req.Password, *contest.Password) {
+		return shareContest.ErrWrongPassword
+	}
+	if err := redis.Delete(key); err != nil {
+		logger.Warn().Str("contest_id", contest.ID).Err(err).Msg("Reset unlock attempts failed")
+	}
+	return shareContest.Grant(s.db, contest.ID, userID, userID)
+}
+
+// GetProblem 获取竞赛题目详情，比赛开始后才能查看
+func (s *ContestServiceImpl) GetProblem(id, code string) (*dto.ContestProblemDetail, error) {
+	contest, err := s.findContest(id)
+	if err != nil {
+		return nil, err
+	}
+	if !shareContest.Started(contest, time.Now()) {
+		return nil, shareContest.ErrContestNotStarted
+	}
+
+	var contestProblem models.ContestProblem
+	if err := s.db.Where("contest_id = ? AND problem_code = ?", contest.ID, code).First(&contestProblem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProblemNotFound
+		}
+		return nil, err
+	}
+	var problem models.ProblemInfo
+	if err := s.db.Where("id = ?", contestProblem.ProblemID).First(&problem).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProblemNotFound
+		}
+		return nil, err
+	}
+
+	detail, err := shareProblem.BuildDetail(s.db, s.dataRoot, &problem)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ContestProblemDetail{
+		ProblemDetail: *detail,
+		ProblemCode:   contestProblem.ProblemCode,
+		Score:         contestProblem.Score,
+	}, nil
+}
+
+// Scoreboard 获取榜单：ICPC 赛制进入封榜时间后直到管理员解除封榜前、OI 赛制比赛结束前返回封榜视图
+func (s *ContestServiceImpl) Scoreboard(id string) (*dto.Scoreboard, error) {
+	contest, err := s.findContest(id)
+	if err != nil {
+		return nil, err
+	}
+	return shareContest.BuildScoreboard(s.db, contest, shareContest.Frozen(contest, time.Now()))
+}
+
+// findContest 查询可见的竞赛
+func (s *ContestServiceImpl) findContest(id string) (*models.ContestInfo, error) {
+	contest, err := shareContest.FindContest(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+	if !contest.IsVisible {
+		return nil, shareContest.ErrContestNotFound
+	}
+	return contest, nil
+}
@@ -0,0 +1,91 @@
+package problem
+
+import (
+	"errors"
+	dto "galaxy/internal/dto/problem"
+	"galaxy/internal/models"
+	problemQuery "galaxy/internal/query/problem"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/query"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrProblemNotFound = errors.New("题目不存在")
+
+// ProblemService 题库服务接口定义
+type ProblemService interface {
+	ProblemList(req *problemQuery.ProblemQueryRequest) (*query.PaginationResponse[dto.ProblemItem], error)
+	GetProblem(id string) (*dto.ProblemDetail, error)
+	// TagTree 可见标签的树形结构，userID 不为空时统计该用户已通过的题目数
+	TagTree(userID string) ([]*dto.TagNode, error)
+}
+
+// ProblemServiceImpl 题库服务实现
+type ProblemServiceImpl struct {
+	db       *gorm.DB
+	dataRoot string
+}
+
+// 确保 ProblemServiceImpl 实现 ProblemService 接口
+var _ ProblemService = (*ProblemServiceImpl)(nil)
+
+func NewProblemService() ProblemService {
+	return &ProblemServiceImpl{
+		db:       database.GetDB(),
+		dataRoot: config.Get().Judge.DataRoot,
+	}
+}
+
+// ProblemList 获取公开题目列表
+func (s *ProblemServiceImpl) ProblemList(req *problemQuery.ProblemQueryRequest) (*query.PaginationResponse[dto.ProblemItem], error) {
+	if req != nil {
+		req.IsPublic = nil
+	}
+	return shareProblem.ProblemList(shareProblem.Public(s.db, time.Now()), req)
+}
+
+// GetProblem 按题目 ID 或展示编号获取公开题目详情，题面渲染为 HTML，只返回样例
+func (s *ProblemServiceImpl) GetProblem(id string) (*dto.ProblemDetail, error) {
+	problem, err := shareProblem.FindProblem(shareProblem.Public(s.db, time.Now()), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProblemNotFound
+		}
+		return nil, err
+	}
+
+	return shareProblem.BuildDetail(s.db, s.dataRoot, problem)
+}
+
+// TagTree 可见标签的树形结构，只统计公开题目
+func (s *ProblemServiceImpl) TagTree(userID string) ([]*dto.TagNode, error) {
+	forest, err := shareProblem.LoadTags(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := shareProblem.Public(s.db, time.Now()).
+		Model(&models.ProblemInfo{}).
+		Select("id")
+	problemIDs, err := shareProblem.TagProblemIDs(s.db, problems)
+	if err != nil {
+		return nil, err
+	}
+
+	var solved map[string]bool
+	if userID != "" {
+		var ids []string
+		if err := shareProblem.SolvedProblemIDs(s.db, userID).Pluck("problem_id", &ids).Error; err != nil {
+			return nil, err
+		}
+		solved = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			solved[id] = true
+		}
+	}
+	return shareProblem.TagTree(forest, problemIDs, solved, true), nil
+}
@@ -0,0 +1,522 @@
+package submission
+
+import (
+	"errors"
+	"fmt"
+	"galaxy/internal/dto/submission"
+	"galaxy/internal/judge/language"
+	"galaxy/internal/judge/progress"
+	"galaxy/internal/judge/template"
+	"galaxy/internal/models"
+	submissionQuery "galaxy/internal/query/submission"
+	shareContest "galaxy/internal/service/share/contest"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/config"
+	"galaxy/pkg/database"
+	"galaxy/pkg/logger"
+	"galaxy/pkg/query"
+	"galaxy/pkg/redis"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxCodeLength = 64 << 10       // 默认代码长度上限（字节）
+	submitRateKeyPrefix  = "submit:rate:" // 提交频率限制键前缀
+)
+
+var (
+	ErrSubmitTooFrequent  = errors.New("提交过于频繁，请稍后再试")
+	ErrSubmissionNotFound = errors.New("提交记录不存在")
+)
+
+// SubmissionService 提交服务接口定义，viewerID 为当前登录用户，未登录时为空。
+// 竞赛提交的判题结果按赛制对 viewerID 隐藏，见 shareContest.ResultHidden
+type SubmissionService interface {
+	Submit(userID string, req *submission.SubmitRequest) (*submission.SubmitResponse, error)
+	GetSubmission(id, viewerID string) (*submission.SubmissionDetail, error)
+	SubmissionList(req *submissionQuery.SubmissionQueryRequest, viewerID string) (*query.PaginationResponse[submission.SubmissionItem], error)
+	// SubmissionEvent 以进度事件的形式返回提交当前状态，用于推送前补发
+	SubmissionEvent(id, viewerID string) (*progress.Event, error)
+	// ResultHidden 提交的判题结果当前是否对 viewerID 隐藏，用于过滤推送的进度事件
+	ResultHidden(id, viewerID string) (bool, error)
+}
+
+// SubmissionServiceImpl 提交服务实现
+type SubmissionServiceImpl struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+// 确保 SubmissionServiceImpl 实现 SubmissionService 接口
+var _ SubmissionService = (*SubmissionServiceImpl)(nil)
+
+func NewSubmissionService() SubmissionService {
+	return &SubmissionServiceImpl{
+		db:  database.GetDB(),
+		cfg: config.Get(),
+	}
+}
+
+// Submit 校验并保存提交，然后放入判题队列
+func (s *SubmissionServiceImpl) Submit(userID string, req *submission.SubmitRequest) (*submission.SubmitResponse, error) {
+	maxCodeLength := s.cfg.Judge.MaxCodeLength
+	if maxCodeLength <= 0 {
+		maxCodeLength = defaultMaxCodeLength
+	}
+	if len(req.Code) > maxCodeLength {
+		return nil, fmt.Errorf("代码长度不能超过 %d 字节", maxCodeLength)
+	}
+
+	problem, err := shareProblem.FindProblem(s.db, req.ProblemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("题目不存在")
+		}
+		return nil, err
+	}
+
+	moduleType := models.ModuleTypeProblem
+	moduleID := problem.ID
+	allowed := []datatypes.JSON{problem.Languages}
+	if req.ContestID == "" {
+		if !problem.IsVisible || !problem.IsPublic {
+			return nil, errors.New("题目不存在")
+		}
+	} else {
+		contest, err := s.checkContest(req.ContestID, problem.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		moduleType = models.ModuleTypeContest
+		moduleID = contest.ID
+		allowed = append(allowed, contest.AllowedLanguages)
+	}
+
+	if err := language.Get().Validate(req.Language, allowed...); err != nil {
+		return nil, err
+	}
+	// 启用代码模板的题目只提交可编辑部分，没有模板的语言无法拼接成完整程序
+	if problem.UseTemplate {
+		if _, err := template.Lookup(problem.CodeTemplate, req.Language); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.checkRate(userID); err != nil {
+		return nil, err
+	}
+
+	status := models.JudgeStatusPending
+	submit := &models.JudgeSubmit{
+		UserID:     &userID,
+		ModuleType: &moduleType,
+		ModuleID:   &moduleID,
+		ProblemID:  &problem.ID,
+		Language:   &req.Language,
+		Code:       &req.Code,
+		CodeLength: len(req.Code),
+		Status:     &status,
+	}
+	if err := s.db.Create(submit).Error; err != nil {
+		return nil, err
+	}
+
+	if err := redis.Enqueue(s.cfg.Queue.JudgeQueue, []byte(submit.ID)); err != nil {
+		// 入队失败时结束该提交，避免一直处于等待状态
+		s.db.Model(&models.JudgeSubmit{}).
+			Where("id = ?", submit.ID).
+			Updates(map[string]interface{}{
+				"status":    models.JudgeStatusSystemError,
+				"message":   "加入判题队列失败",
+				"is_finish": true,
+			})
+		return nil, err
+	}
+
+	if err := shareContest.RecordPending(submit); err != nil {
+		logger.Warn().Str("submit_id", submit.ID).Err(err).Msg("Update scoreboard failed")
+	}
+
+	return &submission.SubmitResponse{SubmitID: submit.ID}, nil
+}
+
+// checkContest 校验竞赛处于进行中、包含该题目且用户有权访问并已报名
+func (s *SubmissionServiceImpl) checkContest(contestID, problemID, userID string) (*models.ContestInfo, error) {
+	contest, err := shareContest.FindContest(s.db, contestID)
+	if err != nil {
+		return nil, err
+	}
+	if !contest.IsVisible {
+		return nil, shareContest.ErrContestNotFound
+	}
+	if err := shareContest.CheckAccess(s.db, contest, userID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if contest.ContestStartTime != nil && now.Before(*contest.ContestStartTime) {
+		return nil, errors.New("竞赛尚未开始")
+	}
+	if contest.ContestEndTime != nil && now.After(*contest.ContestEndTime) {
+		return nil, errors.New("竞赛已结束")
+	}
+
+	var registered int64
+	if err := s.db.Model(&models.ContestParticipant{}).
+		Where("contest_id = ? AND user_id = ?", contest.ID, userID).
+		Count(&registered).Error; err != nil {
+		return nil, err
+	}
+	if registered == 0 {
+		return nil, shareContest.ErrNotRegistered
+	}
+
+	var count int64
+	if err := s.db.Model(&models.ContestProblem{}).
+		Where("contest_id = ? AND problem_id = ?", contest.ID, problemID).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("题目不属于该竞赛")
+	}
+	return contest, nil
+}
+
+// checkRate 限制同一用户的提交间隔
+func (s *SubmissionServiceImpl) checkRate(userID string) error {
+	interval := s.cfg.Judge.SubmitInterval
+	if interval <= 0 {
+		return nil
+	}
+	ok, err := redis.SetNX(submitRateKeyPrefix+userID, 1, time.Duration(interval)*time.Second)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSubmitTooFrequent
+	}
+	return nil
+}
+
+// GetSubmission 获取提交详情及用例、子任务结果
+func (s *SubmissionServiceImpl) GetSubmission(id, viewerID string) (*submission.SubmissionDetail, error) {
+	var submit models.JudgeSubmit
+	if err := s.db.Where("id = ?", id).First(&submit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	if err := s.checkAccess(&submit, viewerID); err != nil {
+		return nil, err
+	}
+
+	detail := &submission.SubmissionDetail{
+		SubmissionItem: toSubmissionItem(&submit),
+		Cases:          []submission.CaseItem{},
+		Subtasks:       []submission.SubtaskItem{},
+	}
+	// 代码与编译信息仅对提交者本人可见
+	if isOwner(&submit, viewerID) {
+		detail.Code = submit.Code
+		detail.Message = submit.Message
+	}
+
+	hidden, err := s.hiddenResults([]models.JudgeSubmit{submit}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if hidden[submit.ID] {
+		redact(&detail.SubmissionItem)
+		detail.Message = nil
+		return detail, nil
+	}
+
+	var cases []models.JudgeCase
+	if err := s.db.Where("submit_id = ?", submit.ID).
+		Order("create_time ASC, id ASC").
+		Find(&cases).Error; err != nil {
+		return nil, err
+	}
+
+	var subtasks []models.JudgeSubtask
+	if err := s.db.Where("submit_id = ?", submit.ID).
+		Order("sort ASC, create_time ASC").
+		Find(&subtasks).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range cases {
+		judgeCase := &cases[i]
+		item := submission.CaseItem{
+			ID:        judgeCase.ID,
+			CaseSign:  judgeCase.CaseSign,
+			SubtaskID: judgeCase.SubtaskID,
+			IsSample:  judgeCase.IsSample,
+			MaxTime:   judgeCase.MaxTime,
+			MaxMemory: judgeCase.MaxMemory,
+			Score:     judgeCase.Score,
+			Status:    judgeCase.Status,
+			ExitCode:  judgeCase.ExitCode,
+		}
+		// 只公开样例数据，避免泄露测试数据；判题信息与标准错误输出可能包含答案或输入
+		if judgeCase.IsSample {
+			item.Message = judgeCase.Message
+			item.InputData = judgeCase.InputData
+			item.OutputData = judgeCase.OutputData
+			item.ExpectedOutput = judgeCase.ExpectedOutput
+		}
+		detail.Cases = append(detail.Cases, item)
+	}
+
+	for i := range subtasks {
+		subtask := &subtasks[i]
+		detail.Subtasks = append(detail.Subtasks, submission.SubtaskItem{
+			SubtaskID: subtask.SubtaskID,
+			Title:     subtask.Title,
+			Score:     subtask.Score,
+			FullScore: subtask.FullScore,
+			Status:    subtask.Status,
+			MaxTime:   subtask.MaxTime,
+			MaxMemory: subtask.MaxMemory,
+		})
+	}
+
+	return detail, nil
+}
+
+// SubmissionList 获取提交列表
+func (s *SubmissionServiceImpl) SubmissionList(req *submissionQuery.SubmissionQueryRequest, viewerID string) (*query.PaginationResponse[submission.SubmissionItem], error) {
+	if req == nil {
+		req = &submissionQuery.SubmissionQueryRequest{}
+	}
+	req.Normalize()
+
+	// 构建查询
+	db := s.db.Model(&models.JudgeSubmit{})
+
+	// 应用查询条件
+	if req.UserID != "" {
+		db = db.Where("user_id = ?", req.UserID)
+	}
+	if req.ProblemID != "" {
+		db = db.Where("problem_id IN (?)", shareProblem.ProblemIDs(s.db, req.ProblemID))
+	}
+	if req.Language != "" {
+		db = db.Where("language = ?", req.Language)
+	}
+	if req.Status != "" {
+		db = db.Where("status = ?", req.Status)
+		// 排除结果未公布的竞赛的提交，避免通过筛选条件推断判题结果
+		contestIDs, err := shareContest.HiddenContests(s.db, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if len(contestIDs) > 0 {
+			db = db.Where("module_type IS DISTINCT FROM ? OR module_id NOT IN ?", models.ModuleTypeContest, contestIDs)
+		}
+	}
+	if req.ContestID != "" {
+		contest, err := shareContest.FindContest(s.db, req.ContestID)
+		if err != nil {
+			return nil, err
+		}
+		if err := shareContest.CheckAccess(s.db, contest, viewerID); err != nil {
+			return nil, err
+		}
+		db = db.Where("module_type = ? AND module_id = ?", models.ModuleTypeContest, req.ContestID)
+	} else {
+		// 排除无权访问的非公开竞赛中他人的提交
+		db = db.Where("module_type IS DISTINCT FROM ? OR module_id NOT IN (?) OR user_id = ?",
+			models.ModuleTypeContest, shareContest.DeniedContests(s.db, viewerID), viewerID)
+	}
+
+	// 获取总数
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	// 应用排序，只允许按提交时间排序，按状态、得分等排序可推断未公布的结果
+	if sort := req.GetSortIn("create_time"); sort != "" {
+		db = db.Order(sort + ", id DESC")
+	} else {
+		// 默认排序
+		db = db.Order("create_time DESC, id DESC")
+	}
+
+	// 应用分页
+	offset := req.GetOffset()
+	var records []models.JudgeSubmit
+	if err := db.Omit("code").Offset(offset).Limit(req.Size).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	hidden, err := s.hiddenResults(records, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]submission.SubmissionItem, 0, len(records))
+	for i := range records {
+		item := toSubmissionItem(&records[i])
+		if hidden[records[i].ID] {
+			redact(&item)
+		}
+		items = append(items, item)
+	}
+
+	// 构建响应
+	return query.BuildPaginationResponse(&req.PaginationRequest, items, total), nil
+}
+
+// SubmissionEvent 获取提交当前状态
+func (s *SubmissionServiceImpl) SubmissionEvent(id, viewerID string) (*progress.Event, error) {
+	var submit models.JudgeSubmit
+	if err := s.db.Omit("code").Where("id = ?", id).First(&submit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubmissionNotFound
+		}
+		return nil, err
+	}
+	if err := s.checkAccess(&submit, viewerID); err != nil {
+		return nil, err
+	}
+
+	event := &progress.Event{
+		Type:      progress.TypeStatus,
+		SubmitID:  submit.ID,
+		Score:     submit.Score,
+		MaxTime:   submit.MaxTime,
+		MaxMemory: submit.MaxMemory,
+	}
+	if submit.IsFinish {
+		event.Type = progress.TypeFinish
+	}
+	if submit.UserID != nil {
+		event.UserID = *submit.UserID
+	}
+	if submit.Status != nil {
+		event.Status = *submit.Status
+	}
+	// 编译信息仅对提交者本人可见，与 GetSubmission 一致
+	if submit.Message != nil && isOwner(&submit, viewerID) {
+		event.Message = *submit.Message
+	}
+
+	hidden, err := s.hiddenResults([]models.JudgeSubmit{submit}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if hidden[submit.ID] {
+		return event.Redact(), nil
+	}
+	return event, nil
+}
+
+// ResultHidden 提交的判题结果当前是否对 viewerID 隐藏
+func (s *SubmissionServiceImpl) ResultHidden(id, viewerID string) (bool, error) {
+	var submit models.JudgeSubmit
+	if err := s.db.Select("id", "user_id", "module_type", "module_id", "create_time").
+		Where("id = ?", id).First(&submit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrSubmissionNotFound
+		}
+		return false, err
+	}
+	hidden, err := s.hiddenResults([]models.JudgeSubmit{submit}, viewerID)
+	if err != nil {
+		return false, err
+	}
+	return hidden[submit.ID], nil
+}
+
+// isOwner viewerID 是否为提交者本人
+func isOwner(submit *models.JudgeSubmit, viewerID string) bool {
+	return submit.UserID != nil && viewerID != "" && *submit.UserID == viewerID
+}
+
+// checkAccess 竞赛提交须有权访问该竞赛，提交者本人始终可以查看
+func (s *SubmissionServiceImpl) checkAccess(submit *models.JudgeSubmit, viewerID string) error {
+	if submit.ModuleType == nil || *submit.ModuleType != models.ModuleTypeContest || submit.ModuleID == nil {
+		return nil
+	}
+	if isOwner(submit, viewerID) {
+		return nil
+	}
+	contest, err := shareContest.FindContest(s.db, *submit.ModuleID)
+	if err != nil {
+		if errors.Is(err, shareContest.ErrContestNotFound) {
+			return nil
+		}
+		return err
+	}
+	return shareContest.CheckAccess(s.db, contest, viewerID)
+}
+
+// hiddenResults 判题结果对 viewerID 隐藏的竞赛提交
+func (s *SubmissionServiceImpl) hiddenResults(submits []models.JudgeSubmit, viewerID string) (map[string]bool, error) {
+	contestIDs := make([]string, 0, len(submits))
+	for i := range submits {
+		if submits[i].ModuleType != nil && *submits[i].ModuleType == models.ModuleTypeContest && submits[i].ModuleID != nil {
+			contestIDs = append(contestIDs, *submits[i].ModuleID)
+		}
+	}
+	if len(contestIDs) == 0 {
+		return nil, nil
+	}
+
+	var contests []models.ContestInfo
+	if err := s.db.Select("id", "rule_type", "contest_start_time", "contest_end_time", "frozen_time", "unfreeze_time").
+		Where("id IN ?", contestIDs).
+		Find(&contests).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.ContestInfo, len(contests))
+	for i := range contests {
+		byID[contests[i].ID] = &contests[i]
+	}
+
+	now := time.Now()
+	hidden := make(map[string]bool)
+	for i := range submits {
+		submit := &submits[i]
+		if submit.ModuleType == nil || *submit.ModuleType != models.ModuleTypeContest || submit.ModuleID == nil {
+			continue
+		}
+		if contest, ok := byID[*submit.ModuleID]; ok && shareContest.ResultHidden(contest, submit, viewerID, now) {
+			hidden[submit.ID] = true
+		}
+	}
+	return hidden, nil
+}
+
+// redact 隐藏提交的判题结果，判题完成的提交显示为已提交
+func redact(item *submission.SubmissionItem) {
+	if item.IsFinish {
+		status := models.JudgeStatusSubmitted
+		item.Status = &status
+	}
+	item.Score, item.MaxTime, item.MaxMemory = 0, 0, 0
+}
+
+func toSubmissionItem(submit *models.JudgeSubmit) submission.SubmissionItem {
+	return submission.SubmissionItem{
+		ID:         submit.ID,
+		UserID:     submit.UserID,
+		ModuleType: submit.ModuleType,
+		ModuleID:   submit.ModuleID,
+		ProblemID:  submit.ProblemID,
+		Language:   submit.Language,
+		CodeLength: submit.CodeLength,
+		MaxTime:    submit.MaxTime,
+		MaxMemory:  submit.MaxMemory,
+		Score:      submit.Score,
+		Status:     submit.Status,
+		IsFinish:   submit.IsFinish,
+		CreateTime: submit.CreatedAt,
+	}
+}
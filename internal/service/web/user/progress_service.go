@@ -0,0 +1,338 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"galaxy/internal/dto/user"
+	"galaxy/internal/models"
+	shareContest "galaxy/internal/service/share/contest"
+	shareProblem "galaxy/internal/service/share/problem"
+	"galaxy/pkg/database"
+	"galaxy/pkg/query"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// heatmapDays 热力图覆盖的天数
+const heatmapDays = 365
+
+const dateLayout = "2006-01-02"
+
+var (
+	ErrUserNotFound   = errors.New("用户不存在")
+	ErrProgressHidden = errors.New("该用户未公开做题记录")
+)
+
+// ProgressService 用户做题记录服务接口定义，viewerID 为当前登录用户，未登录时为空
+type ProgressService interface {
+	GetProgress(userID, viewerID string) (*user.UserProgress, error)
+	// SolvedProblems 已通过的题目，按首次通过时间倒序
+	SolvedProblems(userID, viewerID string, req *query.PaginationRequest) (*query.PaginationResponse[user.ProgressProblem], error)
+	// AttemptedProblems 尝试过但未通过的题目，按首次提交时间倒序
+	AttemptedProblems(userID, viewerID string, req *query.PaginationRequest) (*query.PaginationResponse[user.ProgressProblem], error)
+}
+
+// ProgressServiceImpl 用户做题记录服务实现
+type ProgressServiceImpl struct {
+	db *gorm.DB
+}
+
+// 确保 ProgressServiceImpl 实现 ProgressService 接口
+var _ ProgressService = (*ProgressServiceImpl)(nil)
+
+func NewProgressService() ProgressService {
+	return &ProgressServiceImpl{
+		db: database.GetDB(),
+	}
+}
+
+// progressScope 查询范围：本人可以看到全部题目，他人只能看到公开题目
+type progressScope struct {
+	userID string
+	owner  bool
+	now    time.Time
+}
+
+// GetProgress 获取做题概况、提交热力图、连续天数与分类统计
+func (s *ProgressServiceImpl) GetProgress(userID, viewerID string) (*user.UserProgress, error) {
+	scope, err := s.scope(userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats models.UserStats
+	if err := s.db.Where("account_id = ?", userID).Limit(1).Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	progress := &user.UserProgress{
+		AccountID:      userID,
+		SubmitCount:    stats.SubmitCount,
+		AcceptedCount:  stats.AcceptedCount,
+		AttemptedCount: stats.AttemptedCount,
+		SolvedCount:    stats.SolvedCount,
+	}
+
+	// 结果尚未公布的竞赛提交不计入，避免通过每日与各语言的通过数推断判题结果
+	hidden, err := shareContest.HiddenContests(s.db, scope.now)
+	if err != nil {
+		return nil, err
+	}
+	if progress.Heatmap, err = s.heatmap(scope, hidden); err != nil {
+		return nil, err
+	}
+	if progress.CurrentStreak, progress.LongestStreak, err = s.streaks(scope, hidden); err != nil {
+		return nil, err
+	}
+	if progress.Difficulties, err = s.difficulties(scope); err != nil {
+		return nil, err
+	}
+	if progress.Tags, err = s.tags(scope); err != nil {
+		return nil, err
+	}
+	if progress.Languages, err = s.languages(scope, hidden); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// SolvedProblems 已通过的题目列表，同一题目在多个模块中通过时取最早时间
+func (s *ProgressServiceImpl) SolvedProblems(userID, viewerID string, req *query.PaginationRequest) (*query.PaginationResponse[user.ProgressProblem], error) {
+	scope, err := s.scope(userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.problems(req, s.problemRecords(scope).Having("BOOL_OR(is_solved)"), "first_solved_time DESC, problem_id ASC")
+}
+
+// AttemptedProblems 尝试过但在任何模块中都未通过的题目列表
+func (s *ProgressServiceImpl) AttemptedProblems(userID, viewerID string, req *query.PaginationRequest) (*query.PaginationResponse[user.ProgressProblem], error) {
+	scope, err := s.scope(userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.problems(req, s.problemRecords(scope).Having("NOT BOOL_OR(is_solved)"), "first_submit_time DESC, problem_id ASC")
+}
+
+// scope 校验用户存在，他人查看时须已公开做题记录
+func (s *ProgressServiceImpl) scope(userID, viewerID string) (*progressScope, error) {
+	var count int64
+	if err := s.db.Model(&models.AuthAccount{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrUserNotFound
+	}
+	if userID == viewerID {
+		return &progressScope{userID: userID, owner: true, now: time.Now()}, nil
+	}
+
+	// 没有档案时按默认设置公开
+	var profile models.UserProfile
+	err := s.db.Select("id", "show_progress").Where("account_id = ?", userID).First(&profile).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil && !profile.ShowProgress {
+		return nil, ErrProgressHidden
+	}
+	return &progressScope{userID: userID, now: time.Now()}, nil
+}
+
+// problemRecords 按题目合并各模块的解决记录，他人查看时只包含公开题目
+func (s *ProgressServiceImpl) problemRecords(scope *progressScope) *gorm.DB {
+	db := s.db.Model(&models.RecordSolved{}).
+		Select("problem_id, BOOL_OR(is_solved) AS solved, MIN(first_submit_time) AS first_submit_time, MIN(first_solved_time) AS first_solved_time").
+		Where("user_id = ?", scope.userID).
+		Group("problem_id")
+	if !scope.owner {
+		db = db.Where("problem_id IN (?)", s.publicProblems(scope))
+	}
+	return db
+}
+
+// publicProblems 公开题目 ID 的子查询
+func (s *ProgressServiceImpl) publicProblems(scope *progressScope) *gorm.DB {
+	return shareProblem.Public(s.db, scope.now).
+		Model(&models.ProblemInfo{}).
+		Select("id")
+}
+
+// problems 分页查询题目记录并补充题目信息
+func (s *ProgressServiceImpl) problems(req *query.PaginationRequest, records *gorm.DB, order string) (*query.PaginationResponse[user.ProgressProblem], error) {
+	if req == nil {
+		req = &query.PaginationRequest{}
+	}
+	req.Normalize()
+
+	var total int64
+	if err := s.db.Table("(?) AS r", records).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ProblemID       string
+		FirstSubmitTime *time.Time
+		FirstSolvedTime *time.Time
+	}
+	if err := s.db.Table("(?) AS r", records).
+		Order(order).
+		Offset(req.GetOffset()).
+		Limit(req.Size).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ProblemID)
+	}
+	var problems []models.ProblemInfo
+	if err := s.db.Unscoped().Select("id", "display_id", "title", "difficulty").
+		Where("id IN ?", ids).
+		Find(&problems).Error; err != nil {
+		return nil, err
+	}
+	index := make(map[string]*models.ProblemInfo, len(problems))
+	for i := range problems {
+		index[problems[i].ID] = &problems[i]
+	}
+
+	items := make([]user.ProgressProblem, 0, len(rows))
+	for _, row := range rows {
+		item := user.ProgressProblem{
+			ProblemID:       row.ProblemID,
+			FirstSubmitTime: row.FirstSubmitTime,
+			FirstSolvedTime: row.FirstSolvedTime,
+		}
+		if problem := index[row.ProblemID]; problem != nil {
+			item.DisplayID = problem.DisplayID
+			item.Title = problem.Title
+			item.Difficulty = problem.Difficulty
+		}
+		items = append(items, item)
+	}
+	return query.BuildPaginationResponse(req, items, total), nil
+}
+
+// localDate 按服务器所在时区将提交时间转换为日期
+func localDate(now time.Time) string {
+	_, offset := now.Zone()
+	return fmt.Sprintf("to_char(create_time AT TIME ZONE INTERVAL '%d seconds', 'YYYY-MM-DD')", offset)
+}
+
+// userSubmits 用户的有效提交，不含测试提交与 hidden 中竞赛的提交；他人查看时只包含公开题目
+func (s *ProgressServiceImpl) userSubmits(scope *progressScope, hidden []string) *gorm.DB {
+	db := s.db.Model(&models.JudgeSubmit{}).
+		Where("user_id = ? AND is_test_submit = ?", scope.userID, false)
+	if !scope.owner {
+		db = db.Where("problem_id IN (?)", s.publicProblems(scope))
+	}
+	if len(hidden) > 0 {
+		db = db.Where("module_type IS DISTINCT FROM ? OR module_id NOT IN ?", models.ModuleTypeContest, hidden)
+	}
+	return db
+}
+
+// heatmap 最近 365 天的每日提交数
+func (s *ProgressServiceImpl) heatmap(scope *progressScope, hidden []string) ([]user.HeatmapDay, error) {
+	now := scope.now
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := today.AddDate(0, 0, 1-heatmapDays)
+
+	var rows []user.HeatmapDay
+	if err := s.userSubmits(scope, hidden).
+		Select(localDate(now)+" AS date, COUNT(*) AS submit_count, SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS accepted_count", models.JudgeStatusAccepted).
+		Where("create_time >= ?", start).
+		Group("date").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]user.HeatmapDay, len(rows))
+	for _, row := range rows {
+		counts[row.Date] = row
+	}
+
+	days := make([]user.HeatmapDay, 0, heatmapDays)
+	for day := start; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		item := counts[date]
+		item.Date = date
+		days = append(days, item)
+	}
+	return days, nil
+}
+
+// streaks 当前与最长的连续通过天数
+func (s *ProgressServiceImpl) streaks(scope *progressScope, hidden []string) (int, int, error) {
+	now := scope.now
+	var dates []string
+	if err := s.userSubmits(scope, hidden).
+		Where("status = ?", models.JudgeStatusAccepted).
+		Distinct(localDate(now)).
+		Order(localDate(now)+" ASC").
+		Pluck(localDate(now), &dates).Error; err != nil {
+		return 0, 0, err
+	}
+
+	current, longest := 0, 0
+	var last time.Time
+	for _, date := range dates {
+		day, err := time.ParseInLocation(dateLayout, date, now.Location())
+		if err != nil {
+			return 0, 0, err
+		}
+		if !last.IsZero() && day.Equal(last.AddDate(0, 0, 1)) {
+			current++
+		} else {
+			current = 1
+		}
+		longest = max(longest, current)
+		last = day
+	}
+
+	// 最后一次通过早于昨天时连续已中断
+	today := now.Format(dateLayout)
+	yesterday := now.AddDate(0, 0, -1).Format(dateLayout)
+	if last.IsZero() || (last.Format(dateLayout) != today && last.Format(dateLayout) != yesterday) {
+		current = 0
+	}
+	return current, longest, nil
+}
+
+// difficulties 按题目难度统计
+func (s *ProgressServiceImpl) difficulties(scope *progressScope) ([]user.DifficultyBreakdown, error) {
+	rows := []user.DifficultyBreakdown{}
+	err := s.db.Table("(?) AS r", s.problemRecords(scope)).
+		Select("p.difficulty, COUNT(*) AS attempted, SUM(CASE WHEN r.solved THEN 1 ELSE 0 END) AS solved").
+		Joins("JOIN problem_info p ON p.id = r.problem_id AND p.delete_time IS NULL").
+		Group("p.difficulty").
+		Order("p.difficulty ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// tags 按可见标签统计
+func (s *ProgressServiceImpl) tags(scope *progressScope) ([]user.TagBreakdown, error) {
+	rows := []user.TagBreakdown{}
+	err := s.db.Table("(?) AS r", s.problemRecords(scope)).
+		Select("t.id AS tag_id, t.name, COUNT(DISTINCT r.problem_id) AS attempted, COUNT(DISTINCT CASE WHEN r.solved THEN r.problem_id END) AS solved").
+		Joins("JOIN problem_tag_rel rel ON rel.problem_id = r.problem_id AND rel.delete_time IS NULL").
+		Joins("JOIN content_tag t ON t.id = rel.tag_id AND t.delete_time IS NULL AND t.is_visible = ?", true).
+		Group("t.id, t.name").
+		Order("solved DESC, attempted DESC, t.name ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// languages 按语言统计已完成的提交
+func (s *ProgressServiceImpl) languages(scope *progressScope, hidden []string) ([]user.LanguageBreakdown, error) {
+	rows := []user.LanguageBreakdown{}
+	err := s.userSubmits(scope, hidden).
+		Select("language, COUNT(*) AS submit_count, SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS accepted_count", models.JudgeStatusAccepted).
+		Where("is_finish = ? AND language IS NOT NULL", true).
+		Group("language").
+		Order("submit_count DESC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -13,6 +13,8 @@ type UserService interface {
 	GetUserByID(accountId string) (*user.UserPublicAssociatedInfo, error)
 	GetUserByUsername(username string) (*models.AuthAccount, *user.UserPublicAssociatedInfo, error)
 	GetUserProfile(accountId string) (*user.UserAssociatedProfile, error)
+	// UpdatePrivacy 更新隐私设置，用户没有档案时创建
+	UpdatePrivacy(accountId string, req *user.PrivacySettings) error
 
 	// CreateUser 用户管理
 	CreateUser(account *models.AuthAccount, userInfo *models.UserInfo) error
@@ -142,6 +144,11 @@ func (s *UserServiceImpl) GetUserByUsername(username string) (*models.AuthAccoun
 		LikeCount:    userStats.LikeCount,
 		FollowCount:  userStats.FollowCount,
 		FansCount:    userStats.FansCount,
+		// 做题统计
+		SubmitCount:    userStats.SubmitCount,
+		AcceptedCount:  userStats.AcceptedCount,
+		AttemptedCount: userStats.AttemptedCount,
+		SolvedCount:    userStats.SolvedCount,
 	}
 
 	return &account, &userPublicInfo, nil
@@ -156,3 +163,30 @@ func (s *UserServiceImpl) GetUserProfile(accountId string) (*user.UserAssociated
 	}
 	return &profile, nil
 }
+
+// UpdatePrivacy 更新隐私设置
+func (s *UserServiceImpl) UpdatePrivacy(accountId string, req *user.PrivacySettings) error {
+	updates := map[string]interface{}{}
+	if req.ShowBirthday != nil {
+		updates["show_birthday"] = *req.ShowBirthday
+	}
+	if req.ShowLocation != nil {
+		updates["show_location"] = *req.ShowLocation
+	}
+	if req.ShowProgress != nil {
+		updates["show_progress"] = *req.ShowProgress
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	updates["update_user"] = accountId
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		profile := models.UserProfile{AccountID: accountId}
+		profile.CreateUser = &accountId
+		if err := tx.Where("account_id = ?", accountId).FirstOrCreate(&profile).Error; err != nil {
+			return err
+		}
+		return tx.Model(&profile).Updates(updates).Error
+	})
+}
@@ -19,6 +19,10 @@ type Config struct {
 	Monitor  MonitorConfig  `yaml:"monitor"`
 	Queue    QueueConfig    `yaml:"queue"`
 	JWT      JWTConfig      `yaml:"jwt"`
+	Judge    JudgeConfig    `yaml:"judge"`
+	Problem  ProblemConfig  `yaml:"problem"`
+	User     UserConfig     `yaml:"user"`
+	Contest  ContestConfig  `yaml:"contest"`
 }
 
 type AppConfig struct {
@@ -100,6 +104,84 @@ type JWTConfig struct {
 	ExpireHours int    `yaml:"expire_hours"`
 }
 
+type JudgeConfig struct {
+	WorkDir        string `yaml:"work_dir"`        // 判题工作目录
+	Workers        int    `yaml:"workers"`         // 并发判题协程数
+	CompileTimeout int    `yaml:"compile_timeout"` // 编译超时（秒）
+	OutputLimit    int    `yaml:"output_limit"`    // 单个用例输出上限（KB）
+	ProcessLimit   int    `yaml:"process_limit"`   // 运行时进程/线程数上限
+	RunUID         int    `yaml:"run_uid"`         // 运行提交程序的用户，0 表示不切换
+	RunGID         int    `yaml:"run_gid"`         // 运行提交程序的用户组
+	TestlibPath    string `yaml:"testlib_path"`    // testlib.h 路径，编译特殊判题程序时放入同一目录
+	MaxCodeLength  int    `yaml:"max_code_length"` // 提交代码长度上限（字节）
+	DataRoot       string `yaml:"data_root"`       // 测试数据根目录，ProblemTestCase 中的相对路径基于此目录
+	CacheDir       string `yaml:"cache_dir"`       // 判题端测试数据缓存目录，按 SHA-256 存放，为空表示直接读取数据根目录
+	MaxDataSize    int    `yaml:"max_data_size"`   // 测试数据压缩包解压后的大小上限（MB）
+	SubmitInterval int    `yaml:"submit_interval"` // 同一用户两次提交的最小间隔（秒）
+	// 语言配置，LanguageGroup 对应的配置分组中的同名语言会覆盖此处配置
+	Languages     []LanguageConfig `yaml:"languages"`
+	LanguageGroup string           `yaml:"language_group"`
+}
+
+type ProblemConfig struct {
+	DisplayID  DisplayIDConfig  `yaml:"display_id"`
+	Difficulty DifficultyConfig `yaml:"difficulty"`
+}
+
+// DifficultyConfig 经验难度估计：用户评分为其通过的最难若干题的平均难度，
+// 题目的经验难度为尝试者中通过概率恰为 50% 的评分（逻辑斯蒂模型的极大似然估计）
+type DifficultyConfig struct {
+	Interval   int `yaml:"interval"`    // 估计周期（分钟），0 表示不启用
+	MinSolved  int `yaml:"min_solved"`  // 通过题数不少于该值的用户才有评分
+	TopSolved  int `yaml:"top_solved"`  // 用户评分取通过的最难题目数
+	MinSamples int `yaml:"min_samples"` // 有评分的尝试人数不少于该值时才给出建议
+	MaxLevel   int `yaml:"max_level"`   // 难度上限，建议难度在 1 到该值之间
+}
+
+// DisplayIDConfig 题目展示编号自动分配，编号为前缀加递增数字，如 P1000
+type DisplayIDConfig struct {
+	Prefix string          `yaml:"prefix"` // 默认前缀
+	Start  int             `yaml:"start"`  // 每个前缀的起始编号
+	Rules  []DisplayIDRule `yaml:"rules"`  // 按来源或分类指定前缀，按顺序取第一个匹配的规则
+}
+
+// DisplayIDRule 展示编号前缀规则，CategoryID 与 Source 均配置时需同时匹配
+type DisplayIDRule struct {
+	CategoryID string `yaml:"category_id"`
+	Source     string `yaml:"source"`
+	Prefix     string `yaml:"prefix"`
+}
+
+type UserConfig struct {
+	Level LevelConfig `yaml:"level"`
+}
+
+// LevelConfig 用户经验与等级：首次通过一道题获得 SolveExp + DifficultyExp×难度 的经验，
+// 从 n 级升到 n+1 级需要 BaseExp + StepExp×(n-1) 经验
+type LevelConfig struct {
+	SolveExp      int64 `yaml:"solve_exp"`
+	DifficultyExp int64 `yaml:"difficulty_exp"`
+	BaseExp       int64 `yaml:"base_exp"`
+	StepExp       int64 `yaml:"step_exp"`
+	MaxLevel      int   `yaml:"max_level"` // 达到后不再升级，经验继续累计
+}
+
+type ContestConfig struct {
+	StatusInterval int `yaml:"status_interval"` // 竞赛状态流转检查周期（秒），0 表示不启用
+}
+
+// LanguageConfig 判题语言配置，命令中的 {memory} 会被替换为内存限制（MB）
+type LanguageConfig struct {
+	Name         string   `yaml:"name" json:"name"`                   // 语言名称，对应 JudgeSubmit.Language
+	SourceFile   string   `yaml:"source_file" json:"source_file"`     // 源文件名
+	Compile      []string `yaml:"compile" json:"compile"`             // 编译命令，为空表示无需编译
+	Run          []string `yaml:"run" json:"run"`                     // 运行命令
+	Version      []string `yaml:"version" json:"version"`             // 版本探测命令
+	Env          []string `yaml:"env" json:"env"`                     // 额外环境变量
+	TimeFactor   float64  `yaml:"time_factor" json:"time_factor"`     // 时间限制倍数
+	MemoryFactor float64  `yaml:"memory_factor" json:"memory_factor"` // 内存限制倍数
+}
+
 var (
 	instance *Config
 	once     sync.Once
@@ -104,10 +104,15 @@ func AutoMigrate() error {
 		&models2.ProblemInfo{},
 		&models2.ProblemTagRel{},
 		&models2.ProblemTestCase{},
+		&models2.ProblemSubtask{},
+		&models2.ProblemSequence{},
+		&models2.ProblemReservedRange{},
+		&models2.ProblemStats{},
 
 		// ==================== 提交与判题模块 ====================
 		&models2.JudgeSubmit{},
 		&models2.JudgeCase{},
+		&models2.JudgeSubtask{},
 
 		// ==================== 竞赛管理模块 ====================
 		&models2.ContestInfo{},
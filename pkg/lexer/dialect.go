@@ -0,0 +1,137 @@
+package lexer
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dialect 一种语言的词法规则
+type Dialect struct {
+	Name           string
+	Keywords       map[string]bool
+	LineComments   []string
+	BlockComment   bool            // 是否支持 /* */ 注释
+	Preprocessor   bool            // 是否忽略 # 开头的预处理指令
+	Quotes         string          // 字符串使用的引号
+	CharQuote      bool            // 单引号是否表示字符字面量
+	TripleQuotes   bool            // 是否支持三引号字符串
+	StringPrefixes map[string]bool // 字符串前缀，如 Python 的 r、b、f
+	Lifetimes      bool            // 是否支持 Rust 的生命周期标注
+	DigitSeparator bool            // 数字中是否允许单引号分隔，如 C++14 的 1'000'000
+	operators      []string        // 多字符运算符，按长度降序匹配
+}
+
+func newDialect(d Dialect, keywords, operators string) *Dialect {
+	d.Keywords = make(map[string]bool)
+	for _, word := range strings.Fields(keywords) {
+		d.Keywords[word] = true
+	}
+	d.operators = strings.Fields(operators)
+	sort.SliceStable(d.operators, func(i, j int) bool {
+		return len(d.operators[i]) > len(d.operators[j])
+	})
+	return &d
+}
+
+// C 系语言通用的多字符运算符
+const cOperators = "<<= >>= ... -> ++ -- && || == != <= >= << >> += -= *= /= %= &= |= ^="
+
+var (
+	cDialect = newDialect(Dialect{
+		Name:         "c",
+		LineComments: []string{"//"},
+		BlockComment: true,
+		Preprocessor: true,
+		Quotes:       `"'`,
+		CharQuote:    true,
+	}, `auto break case char const continue default do double else enum extern float for goto if
+		inline int long register restrict return short signed sizeof static struct switch typedef
+		union unsigned void volatile while _Bool bool true false NULL`, cOperators)
+
+	cppDialect = newDialect(Dialect{
+		Name:           "cpp",
+		LineComments:   []string{"//"},
+		BlockComment:   true,
+		Preprocessor:   true,
+		Quotes:         `"'`,
+		CharQuote:      true,
+		DigitSeparator: true,
+	}, `alignas alignof and asm auto bool break case catch char char16_t char32_t class const
+		constexpr const_cast continue decltype default delete do double dynamic_cast else enum
+		explicit export extern false float for friend goto if inline int long mutable namespace new
+		noexcept not nullptr operator or private protected public register reinterpret_cast return
+		short signed sizeof static static_assert static_cast struct switch template this
+		thread_local throw true try typedef typeid typename union unsigned using virtual void
+		volatile wchar_t while xor`, cOperators+" :: ->* .* <=>")
+
+	javaDialect = newDialect(Dialect{
+		Name:         "java",
+		LineComments: []string{"//"},
+		BlockComment: true,
+		Quotes:       `"'`,
+		CharQuote:    true,
+		TripleQuotes: true,
+	}, `abstract assert boolean break byte case catch char class const continue default do double
+		else enum extends final finally float for goto if implements import instanceof int
+		interface long native new package private protected public return short static strictfp
+		super switch synchronized this throw throws transient try void volatile while var record
+		true false null`, cOperators+" >>>= >>> :: ->")
+
+	goDialect = newDialect(Dialect{
+		Name:         "go",
+		LineComments: []string{"//"},
+		BlockComment: true,
+		Quotes:       "\"'`",
+		CharQuote:    true,
+	}, `break case chan const continue default defer else fallthrough for func go goto if import
+		interface map package range return select struct switch type var true false nil`,
+		cOperators+" &^= &^ := <-")
+
+	rustDialect = newDialect(Dialect{
+		Name:         "rust",
+		LineComments: []string{"//"},
+		BlockComment: true,
+		Quotes:       `"'`,
+		CharQuote:    true,
+		Lifetimes:    true,
+	}, `as async await break const continue crate dyn else enum extern false fn for if impl in let
+		loop match mod move mut pub ref return self Self static struct super trait true type unsafe
+		use where while`, cOperators+" :: => ..= ..")
+
+	jsDialect = newDialect(Dialect{
+		Name:         "javascript",
+		LineComments: []string{"//"},
+		BlockComment: true,
+		Quotes:       "\"'`",
+	}, `async await break case catch class const continue debugger default delete do else export
+		extends false finally for function if import in instanceof let new null of return static
+		super switch this throw true try typeof undefined var void while with yield`,
+		cOperators+" >>>= === !== **= ??= ?. ?? ** => >>>")
+
+	pythonDialect = newDialect(Dialect{
+		Name:           "python",
+		LineComments:   []string{"#"},
+		Quotes:         `"'`,
+		TripleQuotes:   true,
+		StringPrefixes: map[string]bool{"r": true, "b": true, "f": true, "u": true, "rb": true, "br": true, "fr": true, "rf": true},
+	}, `False None True and as assert async await break class continue def del elif else except
+		finally for from global if import in is lambda nonlocal not or pass raise return try while
+		with yield`, "**= //= >>= <<= -> ** // == != <= >= << >> += -= *= /= %= &= |= ^= := @=")
+)
+
+// dialects 源文件扩展名到语法的映射
+var dialects = map[string]*Dialect{
+	".c":    cDialect,
+	".h":    cDialect,
+	".cc":   cppDialect,
+	".cpp":  cppDialect,
+	".cxx":  cppDialect,
+	".hpp":  cppDialect,
+	".java": javaDialect,
+	".go":   goDialect,
+	".rs":   rustDialect,
+	".js":   jsDialect,
+	".mjs":  jsDialect,
+	".ts":   jsDialect,
+	".py":   pythonDialect,
+}
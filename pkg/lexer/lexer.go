@@ -0,0 +1,299 @@
+// Package lexer 将源代码切分为词法单元，去除注释与空白并归一化标识符和字面量，
+// 使变量改名、调整格式后的代码得到相同的词法序列
+package lexer
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind 词法单元类型
+type Kind string
+
+const (
+	KindKeyword  Kind = "keyword"
+	KindIdent    Kind = "ident"
+	KindNumber   Kind = "number"
+	KindString   Kind = "string"
+	KindChar     Kind = "char"
+	KindOperator Kind = "operator"
+)
+
+// 归一化后的标识符与字面量
+const (
+	NormIdent  = "ID"
+	NormNumber = "NUM"
+	NormString = "STR"
+	NormChar   = "CHR"
+)
+
+// Token 词法单元
+type Token struct {
+	Kind Kind
+	Text string // 源代码中的原文
+}
+
+// Norm 归一化值：关键字与运算符保留原文，标识符与字面量替换为类别占位符
+func (t Token) Norm() string {
+	switch t.Kind {
+	case KindIdent:
+		return NormIdent
+	case KindNumber:
+		return NormNumber
+	case KindString:
+		return NormString
+	case KindChar:
+		return NormChar
+	default:
+		return t.Text
+	}
+}
+
+// Tokenize 按源文件名对应的语言切分代码，无法识别的语言按 C 系语法处理
+func Tokenize(sourceFile, code string) []Token {
+	l := &lexer{dialect: DialectOf(sourceFile), src: code}
+	l.run()
+	return l.tokens
+}
+
+// Streams 拆分为归一化值、类型与原文三个等长序列
+func Streams(tokens []Token) (norms, kinds, texts []string) {
+	norms = make([]string, len(tokens))
+	kinds = make([]string, len(tokens))
+	texts = make([]string, len(tokens))
+	for i, t := range tokens {
+		norms[i] = t.Norm()
+		kinds[i] = string(t.Kind)
+		texts[i] = t.Text
+	}
+	return norms, kinds, texts
+}
+
+// DialectOf 根据源文件扩展名选择语法
+func DialectOf(sourceFile string) *Dialect {
+	if d, ok := dialects[strings.ToLower(filepath.Ext(sourceFile))]; ok {
+		return d
+	}
+	return cDialect
+}
+
+type lexer struct {
+	dialect *Dialect
+	src     string
+	pos     int
+	tokens  []Token
+	// lineStart 当前位置之前本行只有空白，用于识别预处理指令
+	lineStart bool
+}
+
+func (l *lexer) run() {
+	l.lineStart = true
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		switch {
+		case r == '\n':
+			l.pos += size
+			l.lineStart = true
+			continue
+		case unicode.IsSpace(r):
+			l.pos += size
+			continue
+		case l.skipComment():
+			continue
+		case l.lineStart && r == '#' && l.dialect.Preprocessor:
+			l.skipDirective()
+			continue
+		}
+		l.lineStart = false
+
+		start := l.pos
+		switch {
+		case isIdentStart(r):
+			l.scanIdent(start)
+		case isDigit(r) || (r == '.' && isDigit(l.peekRune(1))):
+			l.scanNumber(start)
+		case l.scanString(start):
+		default:
+			l.scanOperator(start)
+		}
+	}
+}
+
+func (l *lexer) emit(kind Kind, start int) {
+	l.tokens = append(l.tokens, Token{Kind: kind, Text: l.src[start:l.pos]})
+}
+
+// peekRune 当前位置之后第 n 个字节处的字符
+func (l *lexer) peekRune(n int) rune {
+	if l.pos+n >= len(l.src) {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos+n:])
+	return r
+}
+
+func (l *lexer) skipComment() bool {
+	rest := l.src[l.pos:]
+	for _, prefix := range l.dialect.LineComments {
+		if strings.HasPrefix(rest, prefix) {
+			l.skipLine()
+			return true
+		}
+	}
+	if l.dialect.BlockComment && strings.HasPrefix(rest, "/*") {
+		end := strings.Index(rest[2:], "*/")
+		if end < 0 {
+			l.pos = len(l.src)
+		} else {
+			l.pos += end + 4
+		}
+		return true
+	}
+	return false
+}
+
+// skipLine 跳到行尾，保留换行符
+func (l *lexer) skipLine() {
+	end := strings.IndexByte(l.src[l.pos:], '\n')
+	if end < 0 {
+		l.pos = len(l.src)
+		return
+	}
+	l.pos += end
+}
+
+// skipDirective 跳过预处理指令，包括以反斜杠续行的部分
+func (l *lexer) skipDirective() {
+	for {
+		l.skipLine()
+		if l.pos >= len(l.src) || !strings.HasSuffix(strings.TrimRight(l.src[:l.pos], " \t\r"), "\\") {
+			return
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) scanIdent(start int) {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !isIdentPart(r) {
+			break
+		}
+		l.pos += size
+	}
+	word := l.src[start:l.pos]
+
+	// 带前缀的字符串字面量，如 Python 的 f"..."、r'...'
+	if l.dialect.StringPrefixes[strings.ToLower(word)] && l.pos < len(l.src) && strings.ContainsRune(l.dialect.Quotes, rune(l.src[l.pos])) {
+		l.scanQuoted(start)
+		return
+	}
+	if l.dialect.Keywords[word] {
+		l.emit(KindKeyword, start)
+		return
+	}
+	l.emit(KindIdent, start)
+}
+
+func (l *lexer) scanNumber(start int) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		// 指数部分可以带符号，十六进制浮点数使用 p 作为指数
+		if c == '+' || c == '-' {
+			prev := unicode.ToLower(rune(l.src[l.pos-1]))
+			hex := strings.HasPrefix(strings.ToLower(l.src[start:l.pos]), "0x")
+			if prev == 'p' || prev == 'e' && !hex {
+				l.pos++
+				continue
+			}
+			break
+		}
+		if c == '.' && l.pos+1 < len(l.src) && l.src[l.pos+1] == '.' {
+			// 区间运算符，如 Rust 的 0..n
+			break
+		}
+		if !(isDigit(rune(c)) || c == '.' || c == '_' || c == '\'' && l.dialect.DigitSeparator || isIdentStart(rune(c))) {
+			break
+		}
+		l.pos++
+	}
+	l.emit(KindNumber, start)
+}
+
+// scanString 识别字符串或字符字面量
+func (l *lexer) scanString(start int) bool {
+	c := l.src[l.pos]
+	if !strings.ContainsRune(l.dialect.Quotes, rune(c)) {
+		return false
+	}
+	// Rust 的生命周期标注 'a 不是字符字面量
+	if c == '\'' && l.dialect.Lifetimes && isIdentStart(l.peekRune(1)) && l.peekRune(2) != '\'' {
+		l.pos++
+		for l.pos < len(l.src) && isIdentPart(rune(l.src[l.pos])) {
+			l.pos++
+		}
+		l.emit(KindIdent, start)
+		return true
+	}
+	l.scanQuoted(start)
+	return true
+}
+
+// scanQuoted 从 start 开始读取到匹配的结束引号，start 与当前位置之间为字符串前缀
+func (l *lexer) scanQuoted(start int) {
+	quote := l.src[l.pos]
+	delim := string(quote)
+	if l.dialect.TripleQuotes && strings.HasPrefix(l.src[l.pos:], strings.Repeat(delim, 3)) {
+		delim = strings.Repeat(delim, 3)
+	}
+	l.pos += len(delim)
+	// 原始字符串中反斜杠没有转义含义
+	raw := quote == '`' || strings.ContainsAny(strings.ToLower(l.src[start:l.pos-len(delim)]), "r")
+	for l.pos < len(l.src) {
+		if strings.HasPrefix(l.src[l.pos:], delim) {
+			l.pos += len(delim)
+			break
+		}
+		c := l.src[l.pos]
+		if c == '\n' && len(delim) == 1 && quote != '`' {
+			// 未闭合的单行字符串到行尾为止
+			break
+		}
+		if c == '\\' && !raw && l.pos+1 < len(l.src) {
+			l.pos++
+		}
+		l.pos++
+	}
+	kind := KindString
+	if quote == '\'' && l.dialect.CharQuote {
+		kind = KindChar
+	}
+	l.emit(kind, start)
+}
+
+func (l *lexer) scanOperator(start int) {
+	for _, op := range l.dialect.operators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			l.emit(KindOperator, start)
+			return
+		}
+	}
+	_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+	l.emit(KindOperator, start)
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,225 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkHTML "github.com/yuin/goldmark/renderer/html"
+)
+
+// 公式在 Markdown 渲染前替换为占位符，渲染并清理 HTML 后再还原为
+// <span class="math math-inline"> / <span class="math math-display">，由前端交给 KaTeX 渲染
+const (
+	mathPlaceholderPrefix = "GALAXYMATH"
+	mathPlaceholderSuffix = "END"
+)
+
+var (
+	renderer = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		// 保留原始 HTML（FPS 等题面本身就是 HTML），统一由 policy 清理
+		goldmark.WithRendererOptions(goldmarkHTML.WithUnsafe()),
+	)
+	policy = newPolicy()
+
+	placeholderPattern = regexp.MustCompile(mathPlaceholderPrefix + `(\d+)` + mathPlaceholderSuffix)
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	// 代码块语言标记，供前端高亮
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}
+
+// Render 将 Markdown 渲染为清理过的 HTML，$...$ 与 $$...$$ 中的公式原样保留
+func Render(source string) (string, error) {
+	if strings.TrimSpace(source) == "" {
+		return "", nil
+	}
+
+	text, formulas := extractMath(source)
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(text), &buf); err != nil {
+		return "", fmt.Errorf("渲染 Markdown 失败: %w", err)
+	}
+	sanitized := policy.Sanitize(buf.String())
+
+	return placeholderPattern.ReplaceAllStringFunc(sanitized, func(match string) string {
+		index, err := strconv.Atoi(placeholderPattern.FindStringSubmatch(match)[1])
+		if err != nil || index >= len(formulas) {
+			return match
+		}
+		return formulas[index].html()
+	}), nil
+}
+
+// formula 一段 TeX 公式
+type formula struct {
+	tex     string
+	display bool
+}
+
+func (f formula) html() string {
+	class := "math math-inline"
+	if f.display {
+		class = "math math-display"
+	}
+	return `<span class="` + class + `">` + html.EscapeString(f.tex) + `</span>`
+}
+
+// extractMath 将代码以外的公式替换为占位符。行内公式的 $ 内侧不能是空白，
+// 且不能跨行，避免把金额等普通文本当作公式；\$ 表示普通的 $
+func extractMath(source string) (string, []formula) {
+	var (
+		out      strings.Builder
+		formulas []formula
+		fence    string // 当前所在代码块的围栏，为空表示不在代码块内
+	)
+
+	lines := strings.SplitAfter(source, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " ")
+
+		// 围栏代码块内容原样输出
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(trimmed), fence) {
+				fence = ""
+			}
+			out.WriteString(line)
+			continue
+		}
+		if marker := fenceMarker(trimmed); marker != "" {
+			fence = marker
+			out.WriteString(line)
+			continue
+		}
+
+		// 跨行的 $$ 公式块：从以 $$ 开头的行一直到以 $$ 结尾的行
+		if strings.HasPrefix(strings.TrimSpace(line), "$$") && strings.Count(line, "$$") == 1 {
+			if end := findDisplayEnd(lines, i+1); end > 0 {
+				block := strings.Join(lines[i:end+1], "")
+				tex := strings.TrimSpace(block)
+				tex = strings.TrimSpace(tex[2 : len(tex)-2])
+				out.WriteString(placeholder(len(formulas)))
+				if strings.HasSuffix(block, "\n") {
+					out.WriteString("\n")
+				}
+				formulas = append(formulas, formula{tex: tex, display: true})
+				i = end
+				continue
+			}
+		}
+
+		out.WriteString(replaceInline(line, &formulas))
+	}
+	return out.String(), formulas
+}
+
+// fenceMarker 代码块开始行的围栏（``` 或 ~~~）
+func fenceMarker(line string) string {
+	for _, marker := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, marker) {
+			return marker
+		}
+	}
+	return ""
+}
+
+// findDisplayEnd 查找以 $$ 结尾的行
+func findDisplayEnd(lines []string, from int) int {
+	for j := from; j < len(lines); j++ {
+		if strings.HasSuffix(strings.TrimSpace(lines[j]), "$$") {
+			return j
+		}
+	}
+	return -1
+}
+
+// replaceInline 替换单行中的公式，跳过行内代码
+func replaceInline(line string, formulas *[]formula) string {
+	var out strings.Builder
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '$':
+			out.WriteString(`\$`)
+			i += 2
+		case line[i] == '`':
+			// 行内代码原样输出
+			n := 1
+			for i+n < len(line) && line[i+n] == '`' {
+				n++
+			}
+			ticks := line[i : i+n]
+			end := strings.Index(line[i+n:], ticks)
+			if end < 0 {
+				out.WriteString(ticks)
+				i += n
+				continue
+			}
+			out.WriteString(line[i : i+n+end+n])
+			i += n + end + n
+		case strings.HasPrefix(line[i:], "$$"):
+			end := strings.Index(line[i+2:], "$$")
+			if end <= 0 {
+				out.WriteString("$$")
+				i += 2
+				continue
+			}
+			out.WriteString(placeholder(len(*formulas)))
+			*formulas = append(*formulas, formula{tex: strings.TrimSpace(line[i+2 : i+2+end]), display: true})
+			i += 2 + end + 2
+		case line[i] == '$':
+			end := inlineEnd(line, i+1)
+			if end < 0 {
+				out.WriteByte('$')
+				i++
+				continue
+			}
+			out.WriteString(placeholder(len(*formulas)))
+			*formulas = append(*formulas, formula{tex: line[i+1 : end]})
+			i = end + 1
+		default:
+			out.WriteByte(line[i])
+			i++
+		}
+	}
+	return out.String()
+}
+
+// inlineEnd 行内公式的结束位置，开头与结尾紧挨 $ 的字符不能是空白
+func inlineEnd(line string, start int) int {
+	if start >= len(line) || isSpace(line[start]) {
+		return -1
+	}
+	for j := start; j < len(line); j++ {
+		switch line[j] {
+		case '\\':
+			j++
+		case '\n':
+			return -1
+		case '$':
+			if j == start || isSpace(line[j-1]) {
+				return -1
+			}
+			return j
+		}
+	}
+	return -1
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func placeholder(index int) string {
+	return mathPlaceholderPrefix + strconv.Itoa(index) + mathPlaceholderSuffix
+}
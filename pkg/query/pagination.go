@@ -2,6 +2,7 @@
 package query
 
 import (
+	"slices"
 	"strings"
 )
 
@@ -69,3 +70,11 @@ func (p *PaginationRequest) GetSort() string {
 
 	return safeField + " " + order
 }
+
+// GetSortIn 获取排序字符串，排序字段不在 fields 中时返回空字符串，由调用方使用默认排序
+func (p *PaginationRequest) GetSortIn(fields ...string) string {
+	if !slices.Contains(fields, p.SortField) {
+		return ""
+	}
+	return p.GetSort()
+}
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"galaxy/pkg/config"
 	"galaxy/pkg/logger"
@@ -61,6 +62,15 @@ func Enqueue(queueName string, message []byte) error {
 	return client.LPush(ctx, queueName, message).Err()
 }
 
+// EnqueueAll 以一条命令按顺序批量入队，全部成功或全部失败
+func EnqueueAll(queueName string, messages [][]byte) error {
+	values := make([]interface{}, len(messages))
+	for i, message := range messages {
+		values[i] = message
+	}
+	return client.LPush(ctx, queueName, values...).Err()
+}
+
 func Dequeue(queueName string) ([]byte, error) {
 	result, err := client.BRPop(ctx, 0, queueName).Result()
 	if err != nil {
@@ -72,11 +82,83 @@ func Dequeue(queueName string) ([]byte, error) {
 	return []byte(result[1]), nil
 }
 
+// DequeueTimeout 带超时的出队，超时返回 nil
+func DequeueTimeout(queueName string, timeout time.Duration) ([]byte, error) {
+	result, err := client.BRPop(ctx, timeout, queueName).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(result) < 2 {
+		return nil, fmt.Errorf("invalid queue result")
+	}
+	return []byte(result[1]), nil
+}
+
+// DequeueReliable 带超时的可靠出队，消息原子地移入 processing 列表，处理完成后调用 Ack 移除；超时返回 nil
+func DequeueReliable(queueName, processing string, timeout time.Duration) ([]byte, error) {
+	result, err := client.BRPopLPush(ctx, queueName, processing, timeout).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return []byte(result), nil
+}
+
+// Ack 从 processing 列表移除已处理的消息
+func Ack(processing string, message []byte) error {
+	return client.LRem(ctx, processing, 1, message).Err()
+}
+
+// Requeue 将 processing 列表中的消息放回队列，下次出队时优先取出
+func Requeue(queueName, processing string, message []byte) error {
+	_, err := client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, processing, 1, message)
+		pipe.RPush(ctx, queueName, message)
+		return nil
+	})
+	return err
+}
+
+// RecoverProcessing 将 processing 列表中遗留的消息全部放回队列，返回放回的数量
+func RecoverProcessing(queueName, processing string) (int, error) {
+	count := 0
+	for {
+		err := client.RPopLPush(ctx, processing, queueName).Err()
+		if errors.Is(err, redis.Nil) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		count++
+	}
+}
+
+// 发布订阅
+func Publish(channel string, message interface{}) error {
+	return client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe 订阅频道，调用方负责 Close
+func Subscribe(c context.Context, channels ...string) *redis.PubSub {
+	return client.Subscribe(c, channels...)
+}
+
 // 缓存操作
 func Set(key string, value interface{}, expiration time.Duration) error {
 	return client.Set(ctx, key, value, expiration).Err()
 }
 
+// SetNX 键不存在时设置，返回是否设置成功
+func SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return client.SetNX(ctx, key, value, expiration).Result()
+}
+
 func Get(key string) (string, error) {
 	return client.Get(ctx, key).Result()
 }
@@ -89,3 +171,36 @@ func Exists(key string) (bool, error) {
 	result, err := client.Exists(ctx, key).Result()
 	return result > 0, err
 }
+
+// Expire 设置键的过期时间
+func Expire(key string, expiration time.Duration) error {
+	return client.Expire(ctx, key, expiration).Err()
+}
+
+// Incr 计数加一，首次计数时设置过期时间，返回计数后的值
+func Incr(key string, expiration time.Duration) (int64, error) {
+	count, err := client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := client.Expire(ctx, key, expiration).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
+// 哈希操作
+func HSet(key, field string, value interface{}) error {
+	return client.HSet(ctx, key, field, value).Err()
+}
+
+// HSetNX 字段不存在时设置，返回是否设置成功
+func HSetNX(key, field string, value interface{}) (bool, error) {
+	return client.HSetNX(ctx, key, field, value).Result()
+}
+
+func HGetAll(key string) (map[string]string, error) {
+	return client.HGetAll(ctx, key).Result()
+}
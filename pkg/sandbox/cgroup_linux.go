@@ -0,0 +1,180 @@
+package sandbox
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	cgroupRoot   = "/sys/fs/cgroup"
+	cgroupParent = "galaxy-sandbox"
+)
+
+var (
+	cgroupOnce    sync.Once
+	cgroupEnabled bool
+	cgroupSeq     atomic.Uint64
+)
+
+// cgroup 单次运行使用的 cgroups v2 叶子节点。子进程先在 init 节点中完成初始化，
+// exec 前由父进程移入 path 节点；cgroup v2 迁移进程不会迁移已记账的内存，
+// 因此 path 节点的限制与峰值内存只涉及目标程序
+type cgroup struct {
+	path string
+	init string
+	dir  *os.File // init 节点，创建子进程时直接放入
+}
+
+// cgroupAvailable 检测 cgroups v2 是否可用，并为沙箱创建父节点
+func cgroupAvailable() bool {
+	cgroupOnce.Do(func() {
+		if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+			return
+		}
+		parent := filepath.Join(cgroupRoot, cgroupParent)
+		if err := os.MkdirAll(parent, 0o755); err != nil {
+			return
+		}
+		// 父节点需要向子节点开放 memory 与 pids 控制器
+		_ = os.WriteFile(filepath.Join(cgroupRoot, "cgroup.subtree_control"), []byte("+memory +pids"), 0o644)
+		if err := os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte("+memory +pids"), 0o644); err != nil {
+			return
+		}
+		cgroupEnabled = true
+	})
+	return cgroupEnabled
+}
+
+// newCgroup 创建叶子节点并写入限制
+func newCgroup(memoryLimit int64, processLimit int) (*cgroup, error) {
+	name := fmt.Sprintf("%d-%d", os.Getpid(), cgroupSeq.Add(1))
+	path := filepath.Join(cgroupRoot, cgroupParent, name)
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return nil, err
+	}
+
+	cg := &cgroup{path: path}
+	if memoryLimit > 0 {
+		if err := cg.write("memory.max", strconv.FormatInt(memoryLimit, 10)); err != nil {
+			cg.destroy()
+			return nil, err
+		}
+		// 禁止使用 swap 绕过内存限制，旧内核可能不存在该文件
+		_ = cg.write("memory.swap.max", "0")
+	}
+	if processLimit > 0 {
+		if err := cg.write("pids.max", strconv.Itoa(processLimit)); err != nil {
+			cg.destroy()
+			return nil, err
+		}
+	}
+
+	initPath := path + "-init"
+	if err := os.Mkdir(initPath, 0o755); err != nil {
+		cg.destroy()
+		return nil, err
+	}
+	cg.init = initPath
+
+	dir, err := os.Open(initPath)
+	if err != nil {
+		cg.destroy()
+		return nil, err
+	}
+	cg.dir = dir
+	return cg, nil
+}
+
+// enter 将初始化完成的子进程移入计量节点
+func (cg *cgroup) enter(pid int) error {
+	return cg.write("cgroup.procs", strconv.Itoa(pid))
+}
+
+func (cg *cgroup) write(file, value string) error {
+	return os.WriteFile(filepath.Join(cg.path, file), []byte(value), 0o644)
+}
+
+// peakMemory 峰值内存，内核不支持 memory.peak 时返回 0
+func (cg *cgroup) peakMemory() int64 {
+	data, err := os.ReadFile(filepath.Join(cg.path, "memory.peak"))
+	if err != nil {
+		return 0
+	}
+	value, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	return value
+}
+
+// hasPeak 内核是否提供 memory.peak（Linux 5.19 起）
+func (cg *cgroup) hasPeak() bool {
+	_, err := os.Stat(filepath.Join(cg.path, "memory.peak"))
+	return err == nil
+}
+
+// oomKilled 是否发生过 OOM 终止
+func (cg *cgroup) oomKilled() bool {
+	file, err := os.Open(filepath.Join(cg.path, "memory.events"))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			return fields[1] != "0"
+		}
+	}
+	return false
+}
+
+// kill 终止两个节点中的全部进程，包括调用 setsid 脱离进程组的子进程；
+// 内核不支持 cgroup.kill（Linux 5.14 之前）时逐个终止 cgroup.procs 中的进程
+func (cg *cgroup) kill() {
+	for _, path := range []string{cg.path, cg.init} {
+		if path == "" {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(path, "cgroup.kill"), []byte("1"), 0o644); err == nil {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(path, "cgroup.procs"))
+		if err != nil {
+			continue
+		}
+		for _, field := range strings.Fields(string(data)) {
+			if pid, err := strconv.Atoi(field); err == nil {
+				_ = syscall.Kill(pid, syscall.SIGKILL)
+			}
+		}
+	}
+}
+
+// destroy 终止残留进程并删除节点
+func (cg *cgroup) destroy() {
+	if cg.dir != nil {
+		cg.dir.Close()
+	}
+	cg.kill()
+
+	// 被终止的进程退出前节点无法删除
+	for _, path := range []string{cg.init, cg.path} {
+		if path == "" {
+			continue
+		}
+		for i := 0; i < 50; i++ {
+			if err := os.Remove(path); err == nil || os.IsNotExist(err) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// initProcessName 子进程初始化阶段的 argv[0]
+const initProcessName = "galaxy-sandbox-init"
+
+// 子进程继承的文件描述符
+const (
+	initConfigFD = 3 // 父进程写入 initConfig，关闭后子进程才执行 exec
+	initErrorFD  = 4 // 初始化失败时写入错误信息，exec 成功后自动关闭
+	initReadyFD  = 5 // 初始化完成后写入初始化阶段的峰值内存（KB）
+)
+
+// initConfig 父进程传给子进程初始化阶段的参数
+type initConfig struct {
+	Args    []string `json:"args"`
+	Rlimits []rlimit `json:"rlimits"`
+	Seccomp bool     `json:"seccomp"`
+}
+
+type rlimit struct {
+	Resource int    `json:"resource"`
+	Cur      uint64 `json:"cur"`
+	Max      uint64 `json:"max"`
+}
+
+// Init 若当前进程是沙箱子进程，则应用限制并执行目标程序，不会返回；否则直接返回
+func Init() {
+	if len(os.Args) == 0 || os.Args[0] != initProcessName {
+		return
+	}
+
+	// seccomp 与 no_new_privs 都是线程属性，必须在执行 exec 的线程上设置
+	runtime.LockOSThread()
+
+	if err := initProcess(); err != nil {
+		errPipe := os.NewFile(initErrorFD, "error")
+		fmt.Fprint(errPipe, err.Error())
+		errPipe.Close()
+		os.Exit(1)
+	}
+}
+
+func initProcess() error {
+	syscall.CloseOnExec(initErrorFD)
+
+	configPipe := os.NewFile(initConfigFD, "config")
+	defer configPipe.Close()
+	var cfg initConfig
+	if err := json.NewDecoder(configPipe).Decode(&cfg); err != nil {
+		return fmt.Errorf("decode init config: %w", err)
+	}
+	if len(cfg.Args) == 0 {
+		return fmt.Errorf("empty command")
+	}
+
+	path, err := exec.LookPath(cfg.Args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range cfg.Rlimits {
+		if err := unix.Setrlimit(limit.Resource, &unix.Rlimit{Cur: limit.Cur, Max: limit.Max}); err != nil {
+			return fmt.Errorf("setrlimit %d: %w", limit.Resource, err)
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("set no_new_privs: %w", err)
+	}
+	if cfg.Seccomp {
+		if err := loadSeccompFilter(); err != nil {
+			return fmt.Errorf("load seccomp filter: %w", err)
+		}
+	}
+
+	// 通知父进程初始化完成，等父进程把本进程移入计量的 cgroup 并关闭配置管道后再 exec，
+	// 初始化阶段占用的内存因此不计入目标程序
+	var usage unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_SELF, &usage); err != nil {
+		return fmt.Errorf("getrusage: %w", err)
+	}
+	readyPipe := os.NewFile(initReadyFD, "ready")
+	_, err = fmt.Fprint(readyPipe, usage.Maxrss)
+	readyPipe.Close()
+	if err != nil {
+		return fmt.Errorf("write ready: %w", err)
+	}
+	if _, err := io.Copy(io.Discard, configPipe); err != nil {
+		return fmt.Errorf("wait for exec: %w", err)
+	}
+	configPipe.Close()
+
+	return syscall.Exec(path, cfg.Args, os.Environ())
+}
@@ -0,0 +1,76 @@
+// Package sandbox 在 Linux 上以受限进程运行不可信程序。
+//
+// 限制手段只依赖内核自带能力：rlimit、cgroups v2（可用时）、seccomp 系统调用过滤、
+// 独立的网络命名空间以及独立的工作目录。
+//
+// 子进程通过重新执行当前程序完成初始化，因此使用本包的程序必须在 main 函数开头调用 Init：
+//
+//	func main() {
+//		sandbox.Init()
+//		...
+//	}
+package sandbox
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// Status 运行结果状态
+type Status string
+
+const (
+	StatusOK                  Status = "OK"                  // 正常退出
+	StatusTimeLimitExceeded   Status = "TimeLimitExceeded"   // CPU 或墙上时间超限
+	StatusMemoryLimitExceeded Status = "MemoryLimitExceeded" // 内存超限
+	StatusOutputLimitExceeded Status = "OutputLimitExceeded" // 输出超限
+	StatusRuntimeError        Status = "RuntimeError"        // 非零退出码或被信号终止
+	StatusSystemError         Status = "SystemError"         // 沙箱自身出错
+)
+
+// ErrUnsupported 当前平台不支持沙箱
+var ErrUnsupported = errors.New("sandbox: unsupported platform")
+
+// Config 运行配置
+type Config struct {
+	Args []string // 命令及参数，Args[0] 按 Env 中的 PATH 查找
+	Dir  string   // 工作目录
+	Env  []string // 环境变量，为空时只保留 PATH
+
+	Stdin  io.Reader // 为 *os.File 时直接传递文件描述符
+	Stdout io.Writer // 为 *os.File 时输出上限由 RLIMIT_FSIZE 保证
+	Stderr io.Writer
+
+	TimeLimit     time.Duration // CPU 时间限制
+	WallTimeLimit time.Duration // 墙上时间限制，默认 TimeLimit*2+1s
+	MemoryLimit   int64         // 内存限制（字节）
+	StackLimit    int64         // 栈限制（字节），默认与 MemoryLimit 相同
+	OutputLimit   int64         // 单个文件写入上限（字节）
+	ProcessLimit  int           // 进程/线程数上限
+	OpenFileLimit int           // 打开文件数上限
+
+	DisableNetwork bool // 在独立的网络命名空间中运行
+	Seccomp        bool // 启用系统调用过滤
+	UID            int  // 运行用户，0 表示不切换（仅 root 可用）
+	GID            int  // 运行用户组
+}
+
+// Result 运行结果
+type Result struct {
+	Status   Status
+	CPUTime  time.Duration // 用户态 + 内核态 CPU 时间
+	WallTime time.Duration // 墙上时间
+	Memory   int64         // 峰值内存（字节）
+	ExitCode int           // 退出码，被信号终止时为 -1
+	Signal   int           // 终止信号，0 表示正常退出
+	Error    string        // 系统错误信息
+}
+
+// wallTimeLimit 墙上时间限制
+func (c *Config) wallTimeLimit() time.Duration {
+	if c.WallTimeLimit > 0 {
+		return c.WallTimeLimit
+	}
+	return c.TimeLimit*2 + time.Second
+}
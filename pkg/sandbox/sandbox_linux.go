@@ -0,0 +1,283 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// defaultPath 未指定环境变量时使用的 PATH
+const defaultPath = "PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// Run 在沙箱中运行程序直到退出或超限
+//
+// 返回 error 表示沙箱自身无法完成运行，程序的异常退出通过 Result.Status 体现。
+func Run(ctx context.Context, cfg *Config) (*Result, error) {
+	if len(cfg.Args) == 0 {
+		return nil, errors.New("sandbox: empty command")
+	}
+
+	configReader, configWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer configReader.Close()
+	defer configWriter.Close()
+
+	errReader, errWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer errReader.Close()
+	defer errWriter.Close()
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer readyReader.Close()
+	defer readyWriter.Close()
+
+	env := cfg.Env
+	if len(env) == 0 {
+		env = []string{defaultPath}
+	}
+
+	cmd := &exec.Cmd{
+		Path:       "/proc/self/exe",
+		Args:       []string{initProcessName},
+		Dir:        cfg.Dir,
+		Env:        env,
+		Stdin:      cfg.Stdin,
+		Stdout:     cfg.Stdout,
+		Stderr:     cfg.Stderr,
+		ExtraFiles: []*os.File{configReader, errWriter, readyWriter},
+		WaitDelay:  time.Second,
+	}
+
+	attr := &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+	}
+	root := os.Geteuid() == 0
+	if cfg.DisableNetwork {
+		attr.Cloneflags |= syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC
+		if !root {
+			// 非 root 需要借助用户命名空间创建网络命名空间
+			attr.Cloneflags |= syscall.CLONE_NEWUSER
+			attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+			attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+		}
+	}
+	if root && cfg.UID > 0 {
+		attr.Credential = &syscall.Credential{Uid: uint32(cfg.UID), Gid: uint32(cfg.GID)}
+	}
+
+	var cg *cgroup
+	if cgroupAvailable() {
+		if cg, err = newCgroup(cfg.MemoryLimit, cfg.ProcessLimit); err == nil {
+			defer cg.destroy()
+			attr.UseCgroupFD = true
+			attr.CgroupFD = int(cg.dir.Fd())
+		} else {
+			cg = nil
+		}
+	}
+	cmd.SysProcAttr = attr
+
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("sandbox: start: %w", err)
+	}
+	configReader.Close()
+	errWriter.Close()
+	readyWriter.Close()
+
+	// 调用 setsid 的子进程会脱离进程组，有 cgroup 时按 cgroup 终止
+	pid := cmd.Process.Pid
+	kill := func() {
+		_ = syscall.Kill(-pid, syscall.SIGKILL)
+		if cg != nil {
+			cg.kill()
+		}
+	}
+
+	var timedOut atomic.Bool
+	timer := time.AfterFunc(cfg.wallTimeLimit(), func() {
+		timedOut.Store(true)
+		kill()
+	})
+	stopCancel := context.AfterFunc(ctx, kill)
+
+	initCfg := initConfig{
+		Args:    cfg.Args,
+		Rlimits: cfg.rlimits(cg != nil),
+		Seccomp: cfg.Seccomp,
+	}
+	if err := json.NewEncoder(configWriter).Encode(&initCfg); err != nil {
+		kill()
+	}
+
+	// 子进程初始化完成后报告初始化阶段的峰值内存，移入计量节点后关闭配置管道，子进程随即 exec
+	var initMemory int64
+	var enterErr error
+	ready, _ := io.ReadAll(readyReader)
+	if len(ready) > 0 {
+		maxrss, _ := strconv.ParseInt(string(ready), 10, 64)
+		initMemory = maxrss * 1024
+		if cg != nil {
+			if enterErr = cg.enter(pid); enterErr != nil {
+				kill()
+			}
+		}
+	}
+	configWriter.Close()
+
+	// exec 成功后错误管道被关闭，读到的内容即初始化失败原因
+	initErr, _ := io.ReadAll(errReader)
+
+	// 进程退出后 rusage 的峰值包含初始化阶段，没有 memory.peak 时在运行期间采样目标程序的峰值
+	var sampled <-chan int64
+	stopSample := make(chan struct{})
+	if len(initErr) == 0 && (cg == nil || !cg.hasPeak()) {
+		sampled = sampleMemory(pid, stopSample)
+	}
+
+	waitErr := cmd.Wait()
+	wallTime := time.Since(start)
+	timer.Stop()
+	stopCancel()
+	close(stopSample)
+
+	if len(initErr) > 0 {
+		return nil, fmt.Errorf("sandbox: init: %s", initErr)
+	}
+	if cmd.ProcessState == nil {
+		return nil, fmt.Errorf("sandbox: wait: %w", waitErr)
+	}
+	if enterErr != nil {
+		return nil, fmt.Errorf("sandbox: enter cgroup: %w", enterErr)
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state := cmd.ProcessState
+	result := &Result{
+		WallTime: wallTime,
+		ExitCode: state.ExitCode(),
+	}
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		result.CPUTime = time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+		result.Memory = usage.Maxrss * 1024
+	}
+	if cg != nil && sampled == nil {
+		result.Memory = cg.peakMemory()
+	} else if sampled != nil && result.Memory <= initMemory {
+		// rusage 的峰值不超过初始化阶段时无法区分，以采样到的目标程序峰值为准
+		result.Memory = <-sampled
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		result.Signal = int(status.Signal())
+	}
+
+	switch {
+	case timedOut.Load(),
+		cfg.TimeLimit > 0 && result.CPUTime > cfg.TimeLimit,
+		result.Signal == int(syscall.SIGXCPU):
+		result.Status = StatusTimeLimitExceeded
+	case result.Signal == int(syscall.SIGXFSZ):
+		result.Status = StatusOutputLimitExceeded
+	case cg != nil && cg.oomKilled(),
+		cfg.MemoryLimit > 0 && result.Memory > cfg.MemoryLimit:
+		result.Status = StatusMemoryLimitExceeded
+	case result.Signal != 0 || result.ExitCode != 0:
+		result.Status = StatusRuntimeError
+	default:
+		result.Status = StatusOK
+	}
+	return result, nil
+}
+
+// rlimits 子进程的资源限制
+func (c *Config) rlimits(useCgroup bool) []rlimit {
+	limits := []rlimit{
+		{Resource: unix.RLIMIT_CORE, Cur: 0, Max: 0},
+	}
+	if c.TimeLimit > 0 {
+		// 软限制触发 SIGXCPU，硬限制兜底 SIGKILL
+		seconds := uint64(math.Ceil(c.TimeLimit.Seconds())) + 1
+		limits = append(limits, rlimit{Resource: unix.RLIMIT_CPU, Cur: seconds, Max: seconds + 1})
+	}
+	if c.MemoryLimit > 0 && !useCgroup {
+		// 没有 cgroup 时以数据段上限兜底，是否超限以峰值内存判断
+		limits = append(limits, rlimit{Resource: unix.RLIMIT_DATA, Cur: uint64(c.MemoryLimit) * 2, Max: uint64(c.MemoryLimit) * 2})
+	}
+	if stack := c.StackLimit; stack > 0 || c.MemoryLimit > 0 {
+		if stack <= 0 {
+			stack = c.MemoryLimit
+		}
+		limits = append(limits, rlimit{Resource: unix.RLIMIT_STACK, Cur: uint64(stack), Max: uint64(stack)})
+	}
+	if c.OutputLimit > 0 {
+		limits = append(limits, rlimit{Resource: unix.RLIMIT_FSIZE, Cur: uint64(c.OutputLimit), Max: uint64(c.OutputLimit)})
+	}
+	if c.ProcessLimit > 0 && !useCgroup {
+		limits = append(limits, rlimit{Resource: unix.RLIMIT_NPROC, Cur: uint64(c.ProcessLimit), Max: uint64(c.ProcessLimit)})
+	}
+	if c.OpenFileLimit > 0 {
+		limits = append(limits, rlimit{Resource: unix.RLIMIT_NOFILE, Cur: uint64(c.OpenFileLimit), Max: uint64(c.OpenFileLimit)})
+	}
+	return limits
+}
+
+// sampleMemory 每隔 10ms 读取进程的 VmHWM，直到 stop 关闭，通过返回的通道给出采样到的最大值（字节）。
+// 只统计 exec 之后的地址空间，最后一次采样之后的增长会被遗漏
+func sampleMemory(pid int, stop <-chan struct{}) <-chan int64 {
+	result := make(chan int64, 1)
+	path := fmt.Sprintf("/proc/%d/status", pid)
+	go func() {
+		var peak int64
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			if hwm := readVmHWM(path); hwm > peak {
+				peak = hwm
+			}
+			select {
+			case <-stop:
+				result <- peak
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return result
+}
+
+// readVmHWM 读取 /proc/<pid>/status 中的 VmHWM，进程已退出时返回 0
+func readVmHWM(path string) int64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "VmHWM:"); ok {
+			kb, _ := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+			return kb * 1024
+		}
+	}
+	return 0
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package sandbox
+
+import "context"
+
+// Init 非 Linux 平台无需初始化
+func Init() {}
+
+// Run 非 Linux 平台不支持沙箱
+func Run(ctx context.Context, cfg *Config) (*Result, error) {
+	return nil, ErrUnsupported
+}